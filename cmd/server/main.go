@@ -2,21 +2,34 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"book-api/internal/config"
 	"book-api/internal/database"
 	"book-api/internal/handlers"
+	"book-api/internal/jobs"
+	"book-api/internal/logging"
+	"book-api/internal/metadata"
+	"book-api/internal/metrics"
+	"book-api/internal/middlewares"
+	"book-api/internal/migrations"
 	"book-api/internal/models"
 	"book-api/internal/repository"
 	"book-api/internal/routes"
 	"book-api/internal/services"
+	"book-api/internal/storage"
+	"book-api/internal/utils"
+
+	"gorm.io/gorm"
 )
 
 // @title Book API
@@ -39,41 +52,151 @@ import (
 // @description Type "Bearer" followed by space and JWT token.
 
 func main() {
+	// Subcommand: server migrate up|down|status|create <name>
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Subcommand: server import-books [--dry-run] [--format csv|jsonl] <file>
+	if len(os.Args) > 1 && os.Args[1] == "import-books" {
+		runImportBooks(os.Args[2:])
+		return
+	}
+
 	// Load config
 	cfg := config.LoadConfig()
+	logger := setupLogger(cfg)
 
 	// Connect to database
 	db, err := database.ConnectDB(cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("failed to connect to database", "error", err)
 	}
 
-	// Auto migrate models
-	if err := db.AutoMigrate(&models.User{}, &models.Book{}, &models.Borrow{}); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+	// Tolak start jika ada migration yang belum diterapkan
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		fatal("failed to load migrations", "error", err)
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		fatal("failed to check migrations", "error", err)
+	}
+	if len(pending) > 0 {
+		latest := pending[len(pending)-1]
+		if !cfg.AllowPendingMigrations {
+			fatal("pending migrations, run `migrate up` first or set ALLOW_PENDING_MIGRATIONS=true",
+				"pending", len(pending), "latest", fmt.Sprintf("%d_%s", latest.Version, latest.Name))
+		}
+		slog.Warn("starting with pending migrations (ALLOW_PENDING_MIGRATIONS=true)", "pending", len(pending))
+	} else {
+		slog.Info("database schema is up to date")
 	}
-	log.Println("✅ Database migration completed")
 
 	// Initialize repository
 	userRepo 	:= repository.NewUserRepository(db)
 	bookRepo 	:= repository.NewBookRepository(db)
 	borrowRepo 	:= repository.NewBorrowRepository(db)
+	tokenRepo 	:= repository.NewRefreshTokenRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	fineRepo 	:= repository.NewFineRepository(db)
+	copyRepo 	:= repository.NewBookCopyRepository(db)
+	authorRepo 	:= repository.NewAuthorRepository(db)
+	publisherRepo	:= repository.NewPublisherRepository(db)
+	genreRepo 	:= repository.NewGenreRepository(db)
+	auditRepo 	:= repository.NewAuditLogRepository(db)
 
 	// Initialize transaction manager
 	txManager	:= database.NewTransactionManager(db)
 
 	// Initialize services
-	authService 	:= services.NewAuthService(userRepo)
-	bookService 	:= services.NewBookService(bookRepo)
-	borrowService 	:= services.NewBorrowService(borrowRepo, bookRepo, txManager)
+	authService 	:= services.NewAuthService(userRepo, tokenRepo, txManager, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	appMetrics, err := newMetrics(cfg, db)
+	if err != nil {
+		fatal("failed to set up metrics", "error", err)
+	}
+
+	metadataProvider, err := newMetadataProvider(cfg)
+	if err != nil {
+		fatal("failed to set up metadata provider", "error", err)
+	}
+
+	bookService 	:= services.NewBookService(bookRepo, copyRepo, borrowRepo, reservationRepo, authorRepo, publisherRepo, genreRepo, auditRepo, txManager, metadataProvider)
+	pickupWindow	:= time.Duration(cfg.HoldPickupDays) * 24 * time.Hour
+	borrowLimits	:= services.BorrowLimits{
+		MaxActiveLoans:	cfg.MaxActiveLoans,
+		BlockOnOverdue:	cfg.BlockOnOverdue,
+		MaxFineBalance:	cfg.FineBlockThreshold,
+	}
+	librarianLimits	:= borrowLimits
+	librarianLimits.MaxActiveLoans = cfg.LibrarianMaxActiveLoans
+	adminLimits		:= borrowLimits
+	adminLimits.MaxActiveLoans = cfg.AdminMaxActiveLoans
+	eligibility		:= services.NewLimitPolicy(borrowLimits, map[models.Role]services.BorrowLimits{
+		models.RoleLibrarian:	librarianLimits,
+		models.RoleAdmin:		adminLimits,
+	})
+	borrowService 	:= services.NewBorrowService(borrowRepo, bookRepo, copyRepo, reservationRepo, fineRepo, userRepo, auditRepo, txManager, eligibility, services.BorrowConfig{
+		LoanPeriod:		time.Duration(cfg.LoanPeriodDays) * 24 * time.Hour,
+		RenewalPeriod:	time.Duration(cfg.RenewalPeriodDays) * 24 * time.Hour,
+		MaxRenewals:	cfg.MaxRenewals,
+		PickupWindow:	pickupWindow,
+		Fines: services.FineConfig{
+			DailyRate:	cfg.FineDailyRate,
+			MaxLateFee:	cfg.FineMaxLateFee,
+		},
+	}, appMetrics.borrows)
+	reservationService := services.NewReservationService(reservationRepo, bookRepo, copyRepo, borrowRepo, auditRepo, txManager, pickupWindow)
+	copyService 	:= services.NewBookCopyService(copyRepo, bookRepo, reservationRepo, auditRepo, txManager, pickupWindow)
+	userService 	:= services.NewUserService(userRepo, txManager)
+	fineService 	:= services.NewFineService(fineRepo, userRepo, borrowRepo, txManager)
+	importService 	:= services.NewBookImportService(bookRepo, copyRepo, authorRepo, publisherRepo, genreRepo, auditRepo, txManager, cfg.ImportBatchSize)
+	exportService 	:= services.NewExportService(bookRepo, borrowRepo)
+	authorService 	:= services.NewAuthorService(authorRepo)
+	publisherService := services.NewPublisherService(publisherRepo)
+	genreService 	:= services.NewGenreService(genreRepo)
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		fatal("failed to set up blob storage", "error", err)
+	}
+	coverService 	:= services.NewBookCoverService(bookRepo, auditRepo, txManager, blobStore)
+	trashService 	:= services.NewBookTrashService(bookRepo, borrowRepo, auditRepo, txManager, blobStore)
+	auditService 	:= services.NewAuditService(auditRepo)
+
+	// Bootstrap admin pertama (jika ADMIN_EMAIL diset dan belum ada admin)
+	admin, err := userService.BootstrapAdmin(cfg.AdminName, cfg.AdminEmail, cfg.AdminPassword)
+	if err != nil {
+		fatal("failed to bootstrap admin", "error", err)
+	}
+	if admin != nil {
+		slog.Info("admin account ready", "email", admin.Email)
+	}
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, cfg.JWTSecret)
 	bookHandler := handlers.NewBookHandler(bookService)
 	borrowHandler := handlers.NewBorrowHandler(borrowService)
+	userHandler := handlers.NewUserHandler(userService)
+	reservationHandler := handlers.NewReservationHandler(reservationService)
+	fineHandler := handlers.NewFineHandler(fineService)
+	copyHandler := handlers.NewBookCopyHandler(copyService)
+	importHandler := handlers.NewBookImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	authorHandler := handlers.NewAuthorHandler(authorService, bookService)
+	publisherHandler := handlers.NewPublisherHandler(publisherService, bookService)
+	genreHandler := handlers.NewGenreHandler(genreService, bookService)
+	coverHandler := handlers.NewBookCoverHandler(coverService, cfg.CoverMaxBytes)
+	trashHandler := handlers.NewBookTrashHandler(trashService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	errorFormat, err := utils.ParseErrorFormat(cfg.ErrorFormat)
+	if err != nil {
+		fatal("invalid error format", "error", err)
+	}
 
 	// Setup routes
-	router := routes.SetupRoutes(authHandler, bookHandler, borrowHandler, cfg.JWTSecret)
+	router := routes.SetupRoutes(authHandler, bookHandler, borrowHandler, userHandler, reservationHandler, fineHandler, copyHandler, importHandler, exportHandler, authorHandler, publisherHandler, genreHandler, coverHandler, trashHandler, auditHandler, cfg.JWTSecret, authService, errorFormat, logger, appMetrics.requests, appMetrics.handler)
 
 	// Create HTTP server
 	addr := fmt.Sprintf(":%s", cfg.AppPort)
@@ -82,35 +205,42 @@ func main() {
 		Handler: router,
 	}
 
-	log.Println("🔧 Server configured, starting goroutine...")
+	// Start background jobs
+	scheduler, err := jobs.NewScheduler(
+		jobs.NewOverdueJob(borrowService, cfg.OverdueCheckInterval),
+		jobs.NewHoldExpiryJob(reservationService, cfg.HoldExpiryCheckInterval),
+	)
+	if err != nil {
+		fatal("invalid OVERDUE_CHECK_INTERVAL or HOLD_EXPIRY_CHECK_INTERVAL", "error", err)
+	}
+	scheduler.Start(context.Background())
+	slog.Info("background jobs started")
 
 	// Start server in goroutine
-	serverErrors := make(chan error, 1)
+	serverErrors := make(chan error, 2)
 	go func() {
-		log.Printf("🚀 Server running on http://localhost%s", addr)
-		log.Printf("📚 API Documentation: http://localhost%s/api/v1", addr)
-		log.Println("👉 Press Ctrl+C to stop")
-		
+		slog.Info("server running", "url", "http://localhost"+addr, "docs", "http://localhost"+addr+"/swagger/index.html")
 		serverErrors <- srv.ListenAndServe()
 	}()
-
-	log.Println("⏸️  Main goroutine waiting for signal...")
+	if appMetrics.server != nil {
+		go func() {
+			slog.Info("metrics server running", "url", "http://localhost"+appMetrics.server.Addr+"/metrics")
+			serverErrors <- appMetrics.server.ListenAndServe()
+		}()
+	}
 
 	// Wait for interrupt signal for graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	
-	log.Println("✅ Signal handler registered")
-	
 	// Wait for either error from server or interrupt signal
 	select {
 	case err := <-serverErrors:
 		if err != nil && err != http.ErrServerClosed {
-			log.Fatalf("❌ Server error: %v", err)
+			fatal("server error", "error", err)
 		}
 	case sig := <-quit:
-		log.Printf("\n🛑 Signal received: %v", sig)
-		log.Println("⏳ Shutting down server gracefully...")
+		slog.Info("shutting down server gracefully", "signal", sig.String())
 	}
 
 	// Give outstanding requests 30 seconds to complete
@@ -118,10 +248,249 @@ func main() {
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("❌ Error during shutdown: %v\n", err)
-		os.Exit(1)
+		fatal("error during shutdown", "error", err)
+	}
+	if appMetrics.server != nil {
+		if err := appMetrics.server.Shutdown(ctx); err != nil {
+			fatal("error during metrics server shutdown", "error", err)
+		}
+	}
+
+	// Stop background jobs setelah server berhenti menerima request
+	if err := scheduler.Stop(ctx); err != nil {
+		fatal("error stopping background jobs", "error", err)
+	}
+
+	slog.Info("server stopped gracefully")
+}
+
+// setupLogger - logger dari LOG_FORMAT dan LOG_LEVEL dipasang sebagai slog.Default(),
+// sehingga log dari package log bawaan juga keluar dengan format yang sama
+func setupLogger(cfg *config.Config) *slog.Logger {
+	logger, err := logging.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		fatal("invalid logging config", "error", err)
+	}
+	slog.SetDefault(logger)
+	return logger
+}
+
+// fatal - pengganti log.Fatal: catat error lalu keluar dengan exit code 1
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+
+// metricsSetup - bagian metrics.Metrics yang dipasang di service, router dan server.
+// Semua field nil jika METRICS_ENABLED=false.
+type metricsSetup struct {
+	borrows		services.BorrowMetrics
+	requests	middlewares.RequestObserver
+	handler		http.Handler	// /metrics di router utama, nil jika memakai METRICS_PORT
+	server		*http.Server	// server /metrics di METRICS_PORT
+}
+
+// newMetrics - metric Prometheus beserta statistik connection pool database. Dengan
+// METRICS_PORT, /metrics hanya dilayani di port itu dan tidak di port API.
+func newMetrics(cfg *config.Config, db *gorm.DB) (metricsSetup, error) {
+	if !cfg.MetricsEnabled {
+		return metricsSetup{}, nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return metricsSetup{}, err
+	}
+	m := metrics.New()
+	m.RegisterDB(sqlDB, db.Dialector.Name())
+
+	result := metricsSetup{borrows: m, requests: m}
+	if cfg.MetricsPort == "" {
+		result.handler = m.Handler()
+		return result, nil
 	}
 
-	log.Println("✅ Server stopped gracefully")
-	log.Println("👋 Goodbye!")
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	result.server = &http.Server{
+		Addr:		":" + cfg.MetricsPort,
+		Handler:	mux,
+	}
+	return result, nil
+}
+
+// newMetadataProvider - provider dari METADATA_PROVIDER, dibungkus cache. nil jika dimatikan.
+func newMetadataProvider(cfg *config.Config) (metadata.Provider, error) {
+	var provider metadata.Provider
+	switch cfg.MetadataProvider {
+	case "none", "":
+		slog.Info("metadata lookup disabled")
+		return nil, nil
+	case "openlibrary":
+		provider = metadata.NewOpenLibrary(cfg.MetadataBaseURL, cfg.MetadataTimeout)
+	case "fixture":
+		fixture, err := metadata.LoadFixture(cfg.MetadataFixtureFile)
+		if err != nil {
+			return nil, err
+		}
+		provider = fixture
+	default:
+		return nil, fmt.Errorf("unknown METADATA_PROVIDER %q, use openlibrary, fixture or none", cfg.MetadataProvider)
+	}
+
+	return metadata.NewCache(provider, cfg.MetadataCacheTTL, metadata.DefaultCacheSize), nil
+}
+
+// newBlobStore - penyimpanan file cover dari STORAGE_DRIVER
+func newBlobStore(cfg *config.Config) (storage.BlobStore, error) {
+	switch cfg.StorageDriver {
+	case "local", "":
+		return storage.NewLocalStore(cfg.StorageDir)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q, use local", cfg.StorageDriver)
+	}
+}
+
+// runMigrate - jalankan subcommand migrate lalu keluar
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fatal("usage: migrate up|down|status|create <name>")
+	}
+
+	// create tidak butuh koneksi database
+	if args[0] == "create" {
+		if len(args) < 2 {
+			fatal("usage: migrate create <name>")
+		}
+		// Setiap migration butuh versi PostgreSQL dan SQLite
+		for _, dir := range []string{migrations.DefaultDir, migrations.DefaultSQLiteDir} {
+			upPath, downPath, err := migrations.Create(dir, strings.Join(args[1:], "_"))
+			if err != nil {
+				fatal("failed to create migration", "error", err)
+			}
+			slog.Info("created migration", "up", upPath, "down", downPath)
+		}
+		return
+	}
+
+	cfg := config.LoadConfig()
+	setupLogger(cfg)
+	db, err := database.ConnectDB(cfg)
+	if err != nil {
+		fatal("failed to connect to database", "error", err)
+	}
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		fatal("failed to load migrations", "error", err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			slog.Info("applied migration", "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			fatal("migrate up failed", "error", err)
+		}
+		if len(applied) == 0 {
+			slog.Info("no pending migrations")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			fatal("migrate down failed", "error", err)
+		}
+		if migration == nil {
+			slog.Info("no applied migrations to roll back")
+			return
+		}
+		slog.Info("rolled back migration", "version", migration.Version, "name", migration.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fatal("migrate status failed", "error", err)
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, state)
+		}
+	default:
+		fatal("unknown migrate command, usage: migrate up|down|status|create <name>", "command", args[0])
+	}
+}
+
+// runImportBooks - import buku dari file CSV / JSON Lines lalu keluar.
+// Exit code 1 jika ada baris yang gagal.
+func runImportBooks(args []string) {
+	fs := flag.NewFlagSet("import-books", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "validate and report without saving")
+	format := fs.String("format", "", "csv or jsonl, defaults to the file extension")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fatal("usage: import-books [--dry-run] [--format csv|jsonl] <file>")
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			*format = string(services.ImportFormatCSV)
+		case ".jsonl", ".ndjson":
+			*format = string(services.ImportFormatJSONL)
+		default:
+			fatal("cannot detect import format, pass --format csv|jsonl", "file", path)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fatal("failed to open import file", "error", err)
+	}
+	defer file.Close()
+
+	cfg := config.LoadConfig()
+	setupLogger(cfg)
+	db, err := database.ConnectDB(cfg)
+	if err != nil {
+		fatal("failed to connect to database", "error", err)
+	}
+
+	importService := services.NewBookImportService(
+		repository.NewBookRepository(db),
+		repository.NewBookCopyRepository(db),
+		repository.NewAuthorRepository(db),
+		repository.NewPublisherRepository(db),
+		repository.NewGenreRepository(db),
+		repository.NewAuditLogRepository(db),
+		database.NewTransactionManager(db),
+		cfg.ImportBatchSize,
+	)
+
+	// Import lewat CLI dicatat di audit log tanpa actor
+	report, err := importService.Import(context.Background(), services.ImportFormat(*format), file, *dryRun)
+	if err != nil {
+		fatal("import failed", "error", err)
+	}
+
+	for _, row := range report.Rows {
+		if row.Status == services.ImportRowFailed {
+			fmt.Printf("row %-6d %-13s %s\n", row.Row, row.ISBN, row.Error)
+		}
+	}
+	if report.DryRun {
+		slog.Info("dry run, nothing was saved")
+	}
+	slog.Info("import finished", "rows", report.Total, "created", report.Created, "updated", report.Updated, "failed", report.Failed)
+
+	if report.Failed > 0 {
+		file.Close()
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,95 @@
+// Package apperrors - error domain yang dikembalikan oleh service. Setiap error punya
+// Kind (menentukan HTTP status di handler) dan Code yang stabil untuk dibaca client.
+package apperrors
+
+import "errors"
+
+// Kind - jenis error, dipetakan ke satu HTTP status oleh handler
+type Kind string
+
+const (
+	KindNotFound			Kind = "not_found"				// 404
+	KindConflict			Kind = "conflict"				// 409
+	KindValidation			Kind = "validation"				// 400
+	KindForbidden			Kind = "forbidden"				// 403
+	KindBusinessRule		Kind = "business_rule"			// 422
+	KindUnauthorized		Kind = "unauthorized"			// 401
+	KindPreconditionFailed	Kind = "precondition_failed"	// 412
+	KindPayloadTooLarge		Kind = "payload_too_large"		// 413
+	KindUnsupportedMedia	Kind = "unsupported_media"		// 415
+	KindUnavailable			Kind = "unavailable"			// 503
+	KindInternal			Kind = "internal"				// 500
+)
+
+// Error - error domain. Dibuat sekali sebagai variable sentinel lalu dibandingkan
+// dengan errors.Is, sehingga pesan dan code-nya tidak berubah antar request.
+type Error struct {
+	Kind	Kind
+	Code	string
+	Message	string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func BusinessRule(code, message string) *Error {
+	return New(KindBusinessRule, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+func PayloadTooLarge(code, message string) *Error {
+	return New(KindPayloadTooLarge, code, message)
+}
+
+func UnsupportedMedia(code, message string) *Error {
+	return New(KindUnsupportedMedia, code, message)
+}
+
+func Unavailable(code, message string) *Error {
+	return New(KindUnavailable, code, message)
+}
+
+// As - error domain pertama di rantai err, nil jika tidak ada
+func As(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return nil
+}
+
+// KindOf - KindInternal untuk error yang bukan error domain
+func KindOf(err error) Kind {
+	if domainErr := As(err); domainErr != nil {
+		return domainErr.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,35 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAs - error domain tetap ditemukan setelah dibungkus fmt.Errorf
+func TestAs(t *testing.T) {
+	errNotFound := NotFound("book_not_found", "book not found")
+	wrapped := fmt.Errorf("restore book: %w", errNotFound)
+
+	domainErr := As(wrapped)
+	require.NotNil(t, domainErr)
+	assert.Equal(t, "book_not_found", domainErr.Code)
+	assert.Equal(t, KindNotFound, domainErr.Kind)
+	assert.ErrorIs(t, wrapped, errNotFound)
+
+	assert.Nil(t, As(errors.New("boom")))
+	assert.Nil(t, As(nil))
+}
+
+// TestKindOf - error domain pertama di rantai yang menentukan Kind, error lain internal
+func TestKindOf(t *testing.T) {
+	errRelation := Validation("invalid_relation", "invalid book relation")
+	errAuthor := NotFound("author_not_found", "author not found")
+
+	assert.Equal(t, KindValidation, KindOf(fmt.Errorf("%w: %w", errRelation, errAuthor)))
+	assert.Equal(t, KindConflict, KindOf(Conflict("isbn_exists", "ISBN already exists")))
+	assert.Equal(t, KindInternal, KindOf(errors.New("connection refused")))
+}
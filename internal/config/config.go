@@ -1,7 +1,8 @@
 package config
 
 import (
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -10,6 +11,16 @@ type Config struct {
 	AppName   string
 	AppPort   string
 
+	ErrorFormat string
+
+	LogFormat string
+	LogLevel  string
+
+	MetricsEnabled bool
+	MetricsPort    string
+
+	DBDriver  string
+	DBPath    string
 	DBHost    string
 	DBPort    string
 	DBUser    string
@@ -17,7 +28,46 @@ type Config struct {
 	DBName    string
 	DBSSLMode string
 
-	JWTSecret string
+	DBSlowQueryThreshold time.Duration
+
+	AllowPendingMigrations bool
+
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	LoanPeriodDays       int
+	RenewalPeriodDays    int
+	MaxRenewals          int
+	OverdueCheckInterval time.Duration
+
+	HoldPickupDays          int
+	HoldExpiryCheckInterval time.Duration
+
+	FineDailyRate      int64
+	FineMaxLateFee     int64
+	FineBlockThreshold int64
+
+	MaxActiveLoans          int
+	LibrarianMaxActiveLoans int
+	AdminMaxActiveLoans     int
+	BlockOnOverdue          bool
+
+	ImportBatchSize int
+
+	MetadataProvider    string
+	MetadataBaseURL     string
+	MetadataFixtureFile string
+	MetadataTimeout     time.Duration
+	MetadataCacheTTL    time.Duration
+
+	StorageDriver string
+	StorageDir    string
+	CoverMaxBytes int64
+
+	AdminName     string
+	AdminEmail    string
+	AdminPassword string
 }
 
 func LoadConfig() *Config {
@@ -29,19 +79,51 @@ func LoadConfig() *Config {
 
 	viper.SetDefault("APP_NAME", "App Name")
 	viper.SetDefault("APP_PORT", "8080")
+	viper.SetDefault("ERROR_FORMAT", "legacy")
+	viper.SetDefault("LOG_FORMAT", "text")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("METRICS_ENABLED", true)
 
+	viper.SetDefault("DB_DRIVER", "postgres")
+	viper.SetDefault("DB_PATH", "./data/book_api.db")
 	viper.SetDefault("DB_HOST", "localhost")
 	viper.SetDefault("DB_PORT", "5432")
 	viper.SetDefault("DB_USER", "postgres")
 	viper.SetDefault("DB_PASS", "123123")
 	viper.SetDefault("DB_NAME", "book_api")
 	viper.SetDefault("DB_SSLMODE", "disable")
+	viper.SetDefault("DB_SLOW_QUERY_THRESHOLD", "200ms")
+	viper.SetDefault("ALLOW_PENDING_MIGRATIONS", false)
 	viper.SetDefault("JWT_SECRET", "secret")
+	viper.SetDefault("ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("REFRESH_TOKEN_TTL", "168h")
+	viper.SetDefault("LOAN_PERIOD_DAYS", 14)
+	viper.SetDefault("RENEWAL_PERIOD_DAYS", 14)
+	viper.SetDefault("MAX_RENEWALS", 2)
+	viper.SetDefault("OVERDUE_CHECK_INTERVAL", "1h")
+	viper.SetDefault("HOLD_PICKUP_DAYS", 3)
+	viper.SetDefault("HOLD_EXPIRY_CHECK_INTERVAL", "15m")
+	viper.SetDefault("FINE_DAILY_RATE", 1000)
+	viper.SetDefault("FINE_MAX_LATE_FEE", 50000)
+	viper.SetDefault("FINE_BLOCK_THRESHOLD", 20000)
+	viper.SetDefault("MAX_ACTIVE_LOANS", 5)
+	viper.SetDefault("LIBRARIAN_MAX_ACTIVE_LOANS", 10)
+	viper.SetDefault("ADMIN_MAX_ACTIVE_LOANS", 0)
+	viper.SetDefault("BORROW_BLOCK_ON_OVERDUE", true)
+	viper.SetDefault("IMPORT_BATCH_SIZE", 100)
+	viper.SetDefault("METADATA_PROVIDER", "openlibrary")
+	viper.SetDefault("METADATA_BASE_URL", "https://openlibrary.org")
+	viper.SetDefault("METADATA_TIMEOUT", "5s")
+	viper.SetDefault("METADATA_CACHE_TTL", "24h")
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_DIR", "./data/blobs")
+	viper.SetDefault("COVER_MAX_BYTES", 5<<20)
+	viper.SetDefault("ADMIN_NAME", "Administrator")
 
 	if err := viper.ReadInConfig(); err != nil {
-		 log.Println("No .env file found, using environment variables or defaults")
+		 slog.Info("no .env file found, using environment variables or defaults")
 	}else{
-		log.Println("✅ Configuration loaded successfully.")
+		slog.Info("configuration loaded from .env")
 	}
 
 
@@ -49,6 +131,16 @@ func LoadConfig() *Config {
 		AppName: viper.GetString("APP_NAME"),
 		AppPort: viper.GetString("APP_PORT"),
 
+		ErrorFormat: viper.GetString("ERROR_FORMAT"),
+
+		LogFormat: viper.GetString("LOG_FORMAT"),
+		LogLevel: viper.GetString("LOG_LEVEL"),
+
+		MetricsEnabled: viper.GetBool("METRICS_ENABLED"),
+		MetricsPort: viper.GetString("METRICS_PORT"),
+
+		DBDriver: viper.GetString("DB_DRIVER"),
+		DBPath: viper.GetString("DB_PATH"),
 		DBHost: viper.GetString("DB_HOST"),
 		DBPort: viper.GetString("DB_PORT"),
 		DBUser: viper.GetString("DB_USER"),
@@ -56,6 +148,45 @@ func LoadConfig() *Config {
 		DBName: viper.GetString("DB_NAME"),
 		DBSSLMode: viper.GetString("DB_SSLMODE"),
 
+		DBSlowQueryThreshold: viper.GetDuration("DB_SLOW_QUERY_THRESHOLD"),
+
+		AllowPendingMigrations: viper.GetBool("ALLOW_PENDING_MIGRATIONS"),
+
 		JWTSecret: viper.GetString("JWT_SECRET"),
+		AccessTokenTTL: viper.GetDuration("ACCESS_TOKEN_TTL"),
+		RefreshTokenTTL: viper.GetDuration("REFRESH_TOKEN_TTL"),
+
+		LoanPeriodDays: viper.GetInt("LOAN_PERIOD_DAYS"),
+		RenewalPeriodDays: viper.GetInt("RENEWAL_PERIOD_DAYS"),
+		MaxRenewals: viper.GetInt("MAX_RENEWALS"),
+		OverdueCheckInterval: viper.GetDuration("OVERDUE_CHECK_INTERVAL"),
+
+		HoldPickupDays: viper.GetInt("HOLD_PICKUP_DAYS"),
+		HoldExpiryCheckInterval: viper.GetDuration("HOLD_EXPIRY_CHECK_INTERVAL"),
+
+		FineDailyRate: viper.GetInt64("FINE_DAILY_RATE"),
+		FineMaxLateFee: viper.GetInt64("FINE_MAX_LATE_FEE"),
+		FineBlockThreshold: viper.GetInt64("FINE_BLOCK_THRESHOLD"),
+
+		MaxActiveLoans: viper.GetInt("MAX_ACTIVE_LOANS"),
+		LibrarianMaxActiveLoans: viper.GetInt("LIBRARIAN_MAX_ACTIVE_LOANS"),
+		AdminMaxActiveLoans: viper.GetInt("ADMIN_MAX_ACTIVE_LOANS"),
+		BlockOnOverdue: viper.GetBool("BORROW_BLOCK_ON_OVERDUE"),
+
+		ImportBatchSize: viper.GetInt("IMPORT_BATCH_SIZE"),
+
+		MetadataProvider: viper.GetString("METADATA_PROVIDER"),
+		MetadataBaseURL: viper.GetString("METADATA_BASE_URL"),
+		MetadataFixtureFile: viper.GetString("METADATA_FIXTURE_FILE"),
+		MetadataTimeout: viper.GetDuration("METADATA_TIMEOUT"),
+		MetadataCacheTTL: viper.GetDuration("METADATA_CACHE_TTL"),
+
+		StorageDriver: viper.GetString("STORAGE_DRIVER"),
+		StorageDir: viper.GetString("STORAGE_DIR"),
+		CoverMaxBytes: viper.GetInt64("COVER_MAX_BYTES"),
+
+		AdminName: viper.GetString("ADMIN_NAME"),
+		AdminEmail: viper.GetString("ADMIN_EMAIL"),
+		AdminPassword: viper.GetString("ADMIN_PASSWORD"),
 	}
 }
\ No newline at end of file
@@ -2,34 +2,92 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 
 	"book-api/internal/config"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
+// Driver database yang didukung (DB_DRIVER)
+const (
+	DriverPostgres	= "postgres"
+	DriverSQLite	= "sqlite"
+)
+
+// SQLiteMemory - DB_PATH untuk database SQLite in-memory
+const SQLiteMemory = ":memory:"
+
+// memoryDBCount - setiap koneksi in-memory mendapat database sendiri
+var memoryDBCount atomic.Int64
+
 
 func ConnectDB(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		cfg.DBHost,
-		cfg.DBUser,
-		cfg.DBPass,
-		cfg.DBName,
-		cfg.DBPort,
-		cfg.DBSSLMode,
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	return Open(cfg, &gorm.Config{
+		Logger: NewGormLogger(slog.Default(), cfg.DBSlowQueryThreshold),
 	})
+}
+
+// Open - buka database sesuai cfg.DBDriver dengan konfigurasi GORM yang diberikan.
+// Error driver untuk unique index dan foreign key diterjemahkan ke gorm.ErrDuplicatedKey
+// dan gorm.ErrForeignKeyViolated, sama untuk Postgres dan SQLite.
+func Open(cfg *config.Config, gormConfig *gorm.Config) (*gorm.DB, error) {
+	gormConfig.TranslateError = true
+
+	var dialector gorm.Dialector
+	switch cfg.DBDriver {
+	case DriverPostgres, "":
+		dialector = postgres.Open(fmt.Sprintf(
+			"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+			cfg.DBHost,
+			cfg.DBUser,
+			cfg.DBPass,
+			cfg.DBName,
+			cfg.DBPort,
+			cfg.DBSSLMode,
+		))
+	case DriverSQLite:
+		dsn, err := sqliteDSN(cfg.DBPath)
+		if err != nil {
+			return nil, err
+		}
+		dialector = sqlite.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q, use %s or %s", cfg.DBDriver, DriverPostgres, DriverSQLite)
+	}
+
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
-	log.Println("✅ Database connected successfully.")
+	slog.Info("database connected", "driver", db.Dialector.Name())
 	return db, nil
 }
+
+// sqliteDSN - SQLite tidak punya row lock, jadi setiap transaction dimulai dengan
+// BEGIN IMMEDIATE: transaction yang menulis berjalan satu per satu dan yang lain
+// menunggu (busy_timeout) sampai lock database dilepas.
+//
+// ":memory:" memakai VFS memdb dengan nama unik supaya semua koneksi di pool
+// melihat database yang sama. Database hilang saat koneksi terakhir ditutup.
+func sqliteDSN(path string) (string, error) {
+	pragmas := "_txlock=immediate&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	if path == SQLiteMemory {
+		return fmt.Sprintf("file:/book-api-%d?vfs=memdb&%s", memoryDBCount.Add(1), pragmas), nil
+	}
+
+	if path == "" {
+		return "", fmt.Errorf("DB_PATH is required for DB_DRIVER=%s", DriverSQLite)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create database directory: %w", err)
+	}
+	return fmt.Sprintf("file:%s?%s&_pragma=journal_mode(WAL)", path, pragmas), nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"book-api/internal/logging"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger - adapter logger GORM ke slog. Query gagal dicatat sebagai error, query
+// yang lebih lama dari slowThreshold sebagai warn, dan semua query lain hanya di
+// level debug (LOG_LEVEL=debug) supaya log tidak penuh SQL.
+type gormLogger struct {
+	logger			*slog.Logger
+	slowThreshold	time.Duration
+}
+
+// NewGormLogger - slowThreshold 0 mematikan log slow query
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration) gormlogger.Interface {
+	return &gormLogger{logger: logger.With(slog.String("component", "gorm")), slowThreshold: slowThreshold}
+}
+
+// LogMode - level diatur oleh handler slog, bukan oleh GORM
+func (l *gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx).InfoContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx).WarnContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.log(ctx).ErrorContext(ctx, fmt.Sprintf(msg, data...))
+}
+
+// Trace - dipanggil GORM setelah setiap query. ErrRecordNotFound bukan kegagalan:
+// service memakainya untuk "tidak ada".
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	logger := l.log(ctx)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		logger.ErrorContext(ctx, "query failed", queryAttrs(sql, rows, elapsed, slog.Any("error", err))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		sql, rows := fc()
+		logger.WarnContext(ctx, "slow query", queryAttrs(sql, rows, elapsed, slog.Duration("threshold", l.slowThreshold))...)
+	case logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		logger.DebugContext(ctx, "query", queryAttrs(sql, rows, elapsed)...)
+	}
+}
+
+// log - query di dalam TransactionManager.WithTransaction membawa ctx request sehingga ikut
+// mencatat request ID. Query repository di luar transaction tidak punya ctx dan memakai logger global.
+func (l *gormLogger) log(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return l.logger
+	}
+	if requestLogger, ok := logging.Lookup(ctx); ok {
+		return requestLogger.With(slog.String("component", "gorm"))
+	}
+	return l.logger
+}
+
+func queryAttrs(sql string, rows int64, elapsed time.Duration, extra ...any) []any {
+	return append([]any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("elapsed_ms", float64(elapsed.Microseconds())/1000),
+	}, extra...)
+}
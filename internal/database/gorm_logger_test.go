@@ -0,0 +1,58 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"book-api/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestGormLogger(t *testing.T, level string) (*gormLogger, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "text", level)
+	require.NoError(t, err)
+	return NewGormLogger(logger, 100*time.Millisecond).(*gormLogger), &buf
+}
+
+func query() (string, int64) {
+	return "SELECT * FROM books", 1
+}
+
+// TestGormLogger_Levels - query lambat jadi warn, query biasa hanya di debug
+func TestGormLogger_Levels(t *testing.T) {
+	ctx := context.Background()
+
+	l, buf := newTestGormLogger(t, "info")
+	l.Trace(ctx, time.Now(), query, nil)
+	assert.Empty(t, buf.String())
+
+	l.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Contains(t, buf.String(), "level=WARN msg=\"slow query\"")
+	assert.Contains(t, buf.String(), "component=gorm")
+
+	l, buf = newTestGormLogger(t, "debug")
+	l.Trace(ctx, time.Now(), query, nil)
+	assert.Contains(t, buf.String(), "level=DEBUG msg=query")
+}
+
+// TestGormLogger_Errors - ErrRecordNotFound bukan kegagalan, error lain dicatat
+func TestGormLogger_Errors(t *testing.T) {
+	ctx := context.Background()
+	l, buf := newTestGormLogger(t, "info")
+
+	l.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	assert.Empty(t, buf.String())
+
+	l.Trace(ctx, time.Now(), query, errors.New("connection reset"))
+	assert.Contains(t, buf.String(), "level=ERROR msg=\"query failed\"")
+	assert.Contains(t, buf.String(), "connection reset")
+}
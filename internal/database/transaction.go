@@ -1,12 +1,15 @@
 package database
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
 // TransactionManager - interface untuk transaction operator
 type TransactionManager interface {
-	WithTransaction(fn func(tx *gorm.DB) error) error
+	WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+	WithSavepoint(tx *gorm.DB, name string, fn func() error) error
 }
 
 // transactionManager - implementasi transaction manager
@@ -18,8 +21,9 @@ func NewTransactionManager(db *gorm.DB) TransactionManager {
 	return &transactionManager{db: db}
 }
 
-func (tm *transactionManager) WithTransaction(fn func(tx *gorm.DB) error) error {
-	tx := tm.db.Begin()
+// WithTransaction - ctx ikut dibawa tx, jadi query di dalam fn tercatat dengan logger request
+func (tm *transactionManager) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	tx := tm.db.WithContext(ctx).Begin()
 	defer func(){
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -34,3 +38,19 @@ func (tm *transactionManager) WithTransaction(fn func(tx *gorm.DB) error) error
 	return tx.Commit().Error
 }
 
+// WithSavepoint - jalankan fn di dalam transaction tx. Jika fn gagal, hanya perubahan
+// fn yang dibatalkan (ROLLBACK TO SAVEPOINT) dan tx tetap bisa dipakai.
+func (tm *transactionManager) WithSavepoint(tx *gorm.DB, name string, fn func() error) error {
+	if err := tx.SavePoint(name).Error; err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if rollbackErr := tx.RollbackTo(name).Error; rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+
+	return nil
+}
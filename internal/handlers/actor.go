@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"book-api/internal/middlewares"
+	"book-api/internal/models"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// actorFromClaims - identitas user yang sedang login untuk diteruskan ke service
+func actorFromClaims(claims *utils.JWTClaim) services.Actor {
+	return services.Actor{UserID: claims.UserID, Role: models.Role(claims.Role)}
+}
+
+// auditContext - context request beserta user yang login dan request ID,
+// untuk service yang mencatat perubahan di audit log
+func auditContext(r *http.Request) context.Context {
+	info := services.AuditInfo{RequestID: middleware.GetReqID(r.Context())}
+	if claims := middlewares.GetUserFromContext(r); claims != nil {
+		userID := claims.UserID
+		info.ActorID = &userID
+	}
+	return services.WithAuditInfo(r.Context(), info)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetBookHistory godoc
+// @Summary Get book change history
+// @Description Changes to a book and to its loans, newest first, with the acting user, request ID and old/new values
+// @Description of every changed field (requires librarian or admin role). History is kept after the book is purged.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.AuditLog}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/history [get]
+func (h *AuditHandler) GetBookHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	page, pageSize := parsePage(r.URL.Query())
+
+	entries, total, err := h.auditService.GetBookHistory(uint(id), page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Book history retrieved successfully", paginated(entries, total, page, pageSize))
+}
+
+// SearchAuditLogs godoc
+// @Summary Search the audit log
+// @Description Search changes to books and loans, newest first (admin only). All filters are optional and combined with AND.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type query string false "book or borrow"
+// @Param entity_id query int false "ID of the book or borrow"
+// @Param book_id query int false "Book, including changes to its loans"
+// @Param actor_id query int false "User who made the change"
+// @Param action query string false "create, update, delete, restore, purge, borrow, return, renew or overdue"
+// @Param request_id query string false "Request ID (X-Request-Id)"
+// @Param from query string false "Changes at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Changes before this time (RFC 3339 or YYYY-MM-DD)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.AuditLog}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/audit-logs [get]
+func (h *AuditHandler) SearchAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter, err := parseAuditFilter(query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, pageSize := parsePage(query)
+
+	entries, total, err := h.auditService.SearchAuditLogs(filter, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Audit logs retrieved successfully", paginated(entries, total, page, pageSize))
+}
+
+// parseAuditFilter - query parameter pencarian audit log
+func parseAuditFilter(query url.Values) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		EntityType: models.AuditEntity(query.Get("entity_type")),
+		Action:     models.AuditAction(query.Get("action")),
+		RequestID:  query.Get("request_id"),
+	}
+
+	switch filter.EntityType {
+	case "", models.AuditEntityBook, models.AuditEntityBorrow:
+	default:
+		return filter, invalidQuery("entity_type", "must be one of [book borrow]")
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *uint
+	}{
+		{"entity_id", &filter.EntityID},
+		{"book_id", &filter.BookID},
+		{"actor_id", &filter.ActorID},
+	} {
+		if raw := query.Get(param.name); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil || id == 0 {
+				return filter, invalidQuery(param.name, "must be a positive integer")
+			}
+			*param.target = uint(id)
+		}
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		if raw := query.Get(param.name); raw != "" {
+			t, err := parseAuditTime(raw)
+			if err != nil {
+				return filter, invalidQuery(param.name, "must be an RFC 3339 time or a YYYY-MM-DD date")
+			}
+			*param.target = &t
+		}
+	}
+
+	return filter, nil
+}
+
+// parseAuditTime - tanggal tanpa jam berarti awal hari itu (UTC)
+func parseAuditTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
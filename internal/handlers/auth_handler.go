@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"book-api/internal/middlewares"
 	"book-api/internal/services"
 	"book-api/internal/utils"
 	"encoding/json"
@@ -30,8 +31,24 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
 type LoginResponse struct {
 	Token string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+func newLoginResponse(pair *services.TokenPair) LoginResponse {
+	return LoginResponse{
+		Token: pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType: "Bearer",
+		ExpiresIn: pair.ExpiresIn,
+	}
 }
 
 // Register godoc
@@ -53,13 +70,13 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	// Validasi dengan validator
 	if err := utils.ValidateStruct(req); err != nil {
-		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
 	user, err := h.authService.Register(req.Name, req.Email, req.Password)
 	if err != nil {
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
@@ -86,15 +103,73 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	// Validasi input
 	if err := utils.ValidateStruct(req); err != nil {
-		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
-	token, err := h.authService.Login(req.Email, req.Password, h.jwtSecret)
+	pair, err := h.authService.Login(r.Context(), req.Email, req.Password, h.jwtSecret)
 	if err != nil {
-		utils.ErrorResponse(w, http.StatusUnauthorized, err.Error())
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Login Successfully", newLoginResponse(pair))
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and a new (rotated) refresh token.
+// @Description Reusing an already rotated refresh token revokes the whole session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response{data=LoginResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /token/refresh [post]
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pair, err := h.authService.RefreshToken(r.Context(), req.RefreshToken, h.jwtSecret)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Token refreshed successfully", newLoginResponse(pair))
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the current session (access token and its refresh token)
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.authService.Logout(claims.ID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	utils.SuccessResponse(w, http.StatusOK, "Login Successfully", LoginResponse{Token: token})
+	utils.SuccessResponse(w, http.StatusOK, "Logout successfully", nil)
 }
\ No newline at end of file
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AuthorHandler struct {
+	authorService services.AuthorService
+	bookService   services.BookService
+}
+
+func NewAuthorHandler(authorService services.AuthorService, bookService services.BookService) *AuthorHandler {
+	return &AuthorHandler{authorService: authorService, bookService: bookService}
+}
+
+// CreateAuthor godoc
+// @Summary Create an author
+// @Description Create an author (requires librarian or admin role). Names that differ only in spacing, punctuation or case,
+// @Description like "J.K. Rowling" and "JK Rowling", are the same author.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.AuthorInput true "Author details"
+// @Success 201 {object} utils.Response{data=models.Author}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /authors [post]
+func (h *AuthorHandler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	var req services.AuthorInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	author, err := h.authorService.CreateAuthor(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Author created successfully", author)
+}
+
+// GetAuthors godoc
+// @Summary Get all authors
+// @Description Get list of authors ordered by name, with pagination
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param q query string false "Name contains (case-insensitive)"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.Author}}
+// @Failure 500 {object} utils.Response
+// @Router /authors [get]
+func (h *AuthorHandler) GetAuthors(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := parsePage(r.URL.Query())
+
+	authors, total, err := h.authorService.GetAuthors(strings.TrimSpace(r.URL.Query().Get("q")), page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Authors retrieved successfully", paginated(authors, total, page, pageSize))
+}
+
+// GetAuthorByID godoc
+// @Summary Get author by ID
+// @Description Get an author
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Param id path int true "Author ID"
+// @Success 200 {object} utils.Response{data=models.Author}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /authors/{id} [get]
+func (h *AuthorHandler) GetAuthorByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	author, err := h.authorService.GetAuthorByID(uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Author retrieved successfully", author)
+}
+
+// GetAuthorBooks godoc
+// @Summary Get books by an author
+// @Description Get the books of an author with pagination. Accepts the same search, filter and sort parameters as GET /books.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Param id path int true "Author ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param q query string false "Full-text search over title, author and description"
+// @Param genre_id query int false "Only books in this genre"
+// @Param in_stock query bool false "Only books in stock (true) or out of stock (false)"
+// @Param sort query string false "Comma-separated sort fields, see GET /books"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.Book}}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /authors/{id}/books [get]
+func (h *AuthorHandler) GetAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	if _, err := h.authorService.GetAuthorByID(uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := parseBookFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.AuthorID = uint(id)
+
+	page, pageSize := parsePage(r.URL.Query())
+	books, total, err := h.bookService.GetAllBooks(filter, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Books retrieved successfully", paginated(books, total, page, pageSize))
+}
+
+// UpdateAuthor godoc
+// @Summary Update an author
+// @Description Update an author (requires librarian or admin role). The author name already stored on existing books is not changed.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Author ID"
+// @Param request body services.AuthorInput true "Author details"
+// @Success 200 {object} utils.Response{data=models.Author}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	var req services.AuthorInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	author, err := h.authorService.UpdateAuthor(uint(id), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Author updated successfully", author)
+}
+
+// DeleteAuthor godoc
+// @Summary Delete an author
+// @Description Delete an author (requires librarian or admin role). Authors that still have books cannot be deleted.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Author ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid author ID")
+		return
+	}
+
+	if err := h.authorService.DeleteAuthor(uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Author deleted successfully", nil)
+}
+
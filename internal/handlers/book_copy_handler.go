@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type BookCopyHandler struct {
+	copyService services.BookCopyService
+}
+
+func NewBookCopyHandler(copyService services.BookCopyService) *BookCopyHandler {
+	return &BookCopyHandler{copyService: copyService}
+}
+
+type AddCopyRequest struct {
+	Barcode       string `json:"barcode" validate:"max=64"`
+	ShelfLocation string `json:"shelf_location" validate:"max=100"`
+	Condition     string `json:"condition" validate:"omitempty,oneof=new good fair poor damaged"`
+}
+
+type UpdateCopyRequest struct {
+	ShelfLocation *string `json:"shelf_location" validate:"omitempty,max=100"` // tidak dikirim = tidak diubah, "" = dikosongkan
+	Condition     string  `json:"condition" validate:"omitempty,oneof=new good fair poor damaged"`
+	Status        string  `json:"status" validate:"omitempty,oneof=available lost maintenance withdrawn"`
+}
+
+// GetBookCopies godoc
+// @Summary List copies of a book
+// @Description List every physical copy of a book with barcode, shelf location, condition and status (requires librarian or admin role)
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} utils.Response{data=[]models.BookCopy}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/copies [get]
+func (h *BookCopyHandler) GetBookCopies(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	copies, err := h.copyService.GetCopies(uint(bookID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Copies retrieved successfully", copies)
+}
+
+// AddCopy godoc
+// @Summary Register a new copy
+// @Description Register a physical copy of a book (requires librarian or admin role). A barcode is generated when none is given.
+// @Description The new copy goes to the first waiting hold, if any.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param request body AddCopyRequest true "Copy details"
+// @Success 201 {object} utils.Response{data=models.BookCopy}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/copies [post]
+func (h *BookCopyHandler) AddCopy(w http.ResponseWriter, r *http.Request) {
+	bookID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	var req AddCopyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	bookCopy, err := h.copyService.AddCopy(auditContext(r), uint(bookID), req.Barcode, req.ShelfLocation, models.CopyCondition(req.Condition))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Copy added successfully", bookCopy)
+}
+
+// GetCopyByBarcode godoc
+// @Summary Find a copy by barcode
+// @Description Look up a physical copy and its book by barcode (requires librarian or admin role)
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param barcode path string true "Copy barcode"
+// @Success 200 {object} utils.Response{data=models.BookCopy}
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /copies/barcode/{barcode} [get]
+func (h *BookCopyHandler) GetCopyByBarcode(w http.ResponseWriter, r *http.Request) {
+	bookCopy, err := h.copyService.GetCopyByBarcode(chi.URLParam(r, "barcode"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Copy retrieved successfully", bookCopy)
+}
+
+// UpdateCopy godoc
+// @Summary Update a copy
+// @Description Update shelf location, condition or status of a copy (requires librarian or admin role).
+// @Description Omitted fields are left unchanged; send an empty shelf_location to clear it.
+// @Description Status can only be changed while the copy is not on loan or on hold; a copy made available goes to the first waiting hold.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Copy ID"
+// @Param request body UpdateCopyRequest true "Copy details"
+// @Success 200 {object} utils.Response{data=models.BookCopy}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /copies/{id} [put]
+func (h *BookCopyHandler) UpdateCopy(w http.ResponseWriter, r *http.Request) {
+	copyID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid copy ID")
+		return
+	}
+
+	var req UpdateCopyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	bookCopy, err := h.copyService.UpdateCopy(auditContext(r), uint(copyID), req.ShelfLocation, models.CopyCondition(req.Condition), models.CopyStatus(req.Status))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Copy updated successfully", bookCopy)
+}
+
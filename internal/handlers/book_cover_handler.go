@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// coverFormField - nama field multipart yang berisi file cover
+const coverFormField = "cover"
+
+type BookCoverHandler struct {
+	coverService services.BookCoverService
+	maxBytes     int64
+}
+
+// NewBookCoverHandler - maxBytes adalah batas ukuran file cover yang di-upload
+func NewBookCoverHandler(coverService services.BookCoverService, maxBytes int64) *BookCoverHandler {
+	return &BookCoverHandler{coverService: coverService, maxBytes: maxBytes}
+}
+
+// UploadCover godoc
+// @Summary Upload a book cover
+// @Description Upload or replace the cover image of a book as multipart/form-data in the `cover` field (requires librarian or admin role).
+// @Description The type is detected from the file content; JPEG, PNG and GIF are accepted. Small (160px) and medium (480px)
+// @Description JPEG thumbnails are generated. The book's cover_url changes with every new image.
+// @Tags Books
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param cover formData file true "Cover image"
+// @Success 200 {object} utils.Response{data=models.Book}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/cover [post]
+func (h *BookCoverHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	data, err := h.readCover(w, r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	book, err := h.coverService.UploadCover(auditContext(r), uint(id), data)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Cover uploaded successfully", book)
+}
+
+// GetCover godoc
+// @Summary Get a book cover
+// @Description Get the cover image of a book. With `v` equal to the version in the book's cover_url the response is cached
+// @Description for a year, otherwise clients must revalidate with If-None-Match.
+// @Tags Books
+// @Produce image/jpeg
+// @Produce image/png
+// @Produce image/gif
+// @Param id path int true "Book ID"
+// @Param size query string false "original, medium or small" default(original)
+// @Param v query string false "Cover version from cover_url"
+// @Success 200 {file} binary
+// @Success 304
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/cover [get]
+func (h *BookCoverHandler) GetCover(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	cover, err := h.coverService.GetCover(r.Context(), uint(id), services.CoverSize(r.URL.Query().Get("size")))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer cover.Body.Close()
+
+	// URL dengan versi yang cocok tidak pernah berubah isinya
+	if r.URL.Query().Get("v") == cover.Version {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "public, no-cache")
+	}
+	w.Header().Set("ETag", cover.ETag)
+	w.Header().Set("Last-Modified", cover.ModTime.UTC().Format(http.TimeFormat))
+
+	if utils.MatchETag(r.Header.Get("If-None-Match"), cover.ETag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", cover.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(cover.Size, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, cover.Body)
+}
+
+// DeleteCover godoc
+// @Summary Delete a book cover
+// @Description Remove the cover image and thumbnails of a book (requires librarian or admin role)
+// @Tags Books
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/cover [delete]
+func (h *BookCoverHandler) DeleteCover(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	if err := h.coverService.DeleteCover(auditContext(r), uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Cover deleted successfully", nil)
+}
+
+var (
+	errNotMultipart   = apperrors.Validation("invalid_cover_upload", "request must be multipart/form-data")
+	errMissingCover   = apperrors.Validation("invalid_cover_upload", fmt.Sprintf("missing %q file field", coverFormField))
+	errEmptyCover     = apperrors.Validation("invalid_cover_upload", fmt.Sprintf("%q file is empty", coverFormField))
+	errMalformedCover = apperrors.Validation("invalid_cover_upload", "malformed multipart body")
+)
+
+// readCover - isi field cover dari body multipart, dibaca langsung tanpa file sementara.
+// Error parsing multipart tidak dikirim apa adanya ke client.
+func (h *BookCoverHandler) readCover(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	// Sisa ruang untuk boundary dan header part multipart
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBytes+64<<10)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errNotMultipart
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errMissingCover
+		}
+		if err != nil {
+			return nil, h.readError(err)
+		}
+		if part.FormName() != coverFormField {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, h.maxBytes+1))
+		part.Close()
+		if err != nil {
+			return nil, h.readError(err)
+		}
+		if int64(len(data)) > h.maxBytes {
+			return nil, h.tooLarge()
+		}
+		if len(data) == 0 {
+			return nil, errEmptyCover
+		}
+		return data, nil
+	}
+}
+
+// readError - body yang melebihi batas MaxBytesReader menjadi 413, error lain 400
+func (h *BookCoverHandler) readError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return h.tooLarge()
+	}
+	return errMalformedCover
+}
+
+func (h *BookCoverHandler) tooLarge() error {
+	return apperrors.PayloadTooLarge("cover_file_too_large", fmt.Sprintf("cover is larger than %d bytes", h.maxBytes))
+}
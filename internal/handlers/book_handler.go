@@ -1,15 +1,19 @@
 package handlers
 
 import (
+	"book-api/internal/isbn"
+	"book-api/internal/models"
 	"book-api/internal/services"
 	"book-api/internal/utils"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"gorm.io/gorm"
 )
 
 type BookHandler struct {
@@ -20,25 +24,36 @@ func NewBookHandler(bookService services.BookService) *BookHandler {
 	return &BookHandler{bookService: bookService}
 }
 
+// CreateBookRequest - rule validasi sama dengan baris bulk import.
+// AutoFill mengisi title, author dan description yang kosong dari metadata ISBN.
 type CreateBookRequest struct {
-	Title string `json:"title" validate:"required,min=1,max=200"`
-	Author string `json:"author" validate:"required,min=1,max=100"`
-	ISBN string `json:"isbn" validate:"required,min=10,max=13"`
-	Description string `json:"description" validate:"max=1000"`
-	Stock int `json:"stock" validate:"gte=0"`
+	services.BookInput
+	AutoFill bool `json:"auto_fill"`
+}
+
+type LookupBookRequest struct {
+	ISBN string `json:"isbn" validate:"required,isbn"`
 }
 
+// UpdateBookRequest - author_ids, publisher_ids dan genre_ids yang tidak dikirim
+// tidak mengubah relasi, array kosong menghapusnya
 type UpdateBookRequest struct {
 	Title string `json:"title" validate:"required,min=1,max=200"`
-	Author string `json:"author" validate:"required,min=1,max=100"`
-	ISBN string `json:"isbn" validate:"required,min=10,max=13"`
+	Author string `json:"author" validate:"required_without=AuthorIDs,max=100"`
+	ISBN string `json:"isbn" validate:"required,isbn"`
 	Description string `json:"description" validate:"max=1000"`
-	Stock int `json:"stock" validate:"gte=0"`
+	AuthorIDs []uint `json:"author_ids" validate:"omitempty,min=1,max=20,dive,gt=0"`
+	PublisherIDs []uint `json:"publisher_ids" validate:"omitempty,max=20,dive,gt=0"`
+	GenreIDs []uint `json:"genre_ids" validate:"omitempty,max=20,dive,gt=0"`
 }
 
 // CreateBook godoc
 // @Summary Create a new book
-// @Description Create a new book (requires authentication)
+// @Description Create a new book (requires librarian or admin role). Stock is the number of physical copies to register,
+// @Description each with a generated barcode; more copies can be added later via /books/{id}/copies.
+// @Description With auto_fill=true, an empty title, author or description is filled from the ISBN's metadata.
+// @Description author_ids links existing authors and replaces the author name; without it, the author name is split on
+// @Description ",", ";", "&" and "and" and each name is linked to an existing or new author.
 // @Tags Books
 // @Accept json
 // @Produce json
@@ -46,6 +61,7 @@ type UpdateBookRequest struct {
 // @Param request body CreateBookRequest true "Book details"
 // @Success 201 {object} utils.Response{data=models.Book}
 // @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /books [post]
 func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
@@ -55,15 +71,25 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Field kosong diisi dulu dari metadata; jika provider gagal, field yang wajib
+	// tetap ditolak oleh validasi di bawah
+	var fillErr error
+	if req.AutoFill {
+		fillErr = h.bookService.FillMissing(r.Context(), &req.BookInput)
+	}
+
 	// Validasi dengan validator
 	if err := utils.ValidateStruct(req); err != nil {
-		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		if fillErr != nil {
+			err = fmt.Errorf("%w; auto_fill: %s", err, fillErr)
+		}
+		writeError(w, r, err)
 		return
 	}
 
-	book, err := h.bookService.CreateBook(req.Title, req.Author, req.ISBN, req.Description, req.Stock)
+	book, err := h.bookService.CreateBook(auditContext(r), req.BookInput)
 	if err != nil {
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 	utils.SuccessResponse(w, http.StatusCreated, "Book created successfully", book)
@@ -72,74 +98,106 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 
 // GetAllBooks godoc
 // @Summary Get all books
-// @Description Get list of all books with pagination
+// @Description Get list of books with pagination, full-text search and filters.
+// @Description Without an explicit sort, search results are ordered by relevance.
 // @Tags Books
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1) 
 // @Param page_size query int false "Page size" default(10) 
+// @Param q query string false "Full-text search over title, author and description"
+// @Param author query string false "Author name contains (case-insensitive)"
+// @Param isbn query string false "Exact ISBN"
+// @Param author_id query int false "Only books by this author"
+// @Param publisher_id query int false "Only books from this publisher"
+// @Param genre_id query int false "Only books in this genre"
+// @Param in_stock query bool false "Only books in stock (true) or out of stock (false)"
+// @Param sort query string false "Comma-separated sort fields: title, author, stock, created_at, updated_at, relevance. Prefix with - for descending" example(title,-created_at)
 // @Success 200 {object} utils.Response{data=utils.PaginatedResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /books [get]
 func (h *BookHandler) GetAllBooks(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameter untuk pagination
-	pageStr := r.URL.Query().Get("page")
-	pageSizeStr := r.URL.Query().Get("page_size")
+	page, pageSize := parsePage(r.URL.Query())
 
-	page := 1 // default
-	pageSize := 10 // default
+	// Parse query parameter untuk search dan filter
+	filter, err := parseBookFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
+	books, total, err := h.bookService.GetAllBooks(filter, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	if pageSizeStr != "" {
-		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
-			pageSize = ps
-		}
+	response := paginated(books, total, page, pageSize)
+
+	utils.SuccessResponse(w, http.StatusOK, "Books retrieved successfully", response)
+}
+
+// parseBookFilter - query parameter q, author, isbn, author_id, publisher_id, genre_id, in_stock dan sort.
+// Dipakai oleh list buku dan export supaya filternya sama.
+func parseBookFilter(query url.Values) (models.BookFilter, error) {
+	filter := models.BookFilter{
+		Query:	strings.TrimSpace(query.Get("q")),
+		Author:	strings.TrimSpace(query.Get("author")),
+		ISBN:	strings.TrimSpace(query.Get("isbn")),
 	}
 
-	books, total, err := h.bookService.GetAllBooks(page, pageSize)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
-		return
+	// ISBN disimpan sebagai ISBN-13, jadi ISBN-10 juga bisa dipakai untuk filter
+	if canonical, err := isbn.Canonical(filter.ISBN); err == nil {
+		filter.ISBN = canonical
 	}
 
-	//Hitung total page
-	if pageSize == 0 {
-		pageSize = 10
+	for _, param := range []struct {
+		name	string
+		target	*uint
+	}{
+		{"author_id", &filter.AuthorID},
+		{"publisher_id", &filter.PublisherID},
+		{"genre_id", &filter.GenreID},
+	} {
+		if raw := query.Get(param.name); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil || id == 0 {
+				return filter, invalidQuery(param.name, "must be a positive integer")
+			}
+			*param.target = uint(id)
+		}
 	}
-	totalPages := int(total) / pageSize
-	if int(total)%pageSize != 0 {
-		totalPages++
+
+	if inStockStr := query.Get("in_stock"); inStockStr != "" {
+		inStock, err := strconv.ParseBool(inStockStr)
+		if err != nil {
+			return filter, invalidQuery("in_stock", "must be true or false")
+		}
+		filter.InStock = &inStock
 	}
 
-	response := utils.PaginatedResponse{
-		Data: books,
-		Page: page,
-		PageSize: pageSize,
-		TotalItems: int(total),
-		TotalPages: totalPages,
+	sort, err := services.ParseBookSort(query.Get("sort"))
+	if err != nil {
+		return filter, err
 	}
+	filter.Sort = sort
 
-	utils.SuccessResponse(w, http.StatusOK, "Books retrieved successfully", response)
+	return filter, nil
 }
 
 // GetBookByID godoc
 // @Summary Get book by ID
-// @Description Get detailed information about a specific book 
+// @Description Get detailed information about a specific book. The response has an ETag that changes whenever the book's
+// @Description version changes (not on stock changes); send it in If-None-Match to get 304, or in If-Match on PUT/PATCH.
 // @Tags Books
 // @Accept json
 // @Produce json
 // @Param id path int true "Book ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} utils.Response{data=models.Book}
+// @Success 304
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -156,28 +214,95 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 
 	book, err := h.bookService.GetBookByID(uint(id))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", book.ETag())
+	if utils.MatchETag(r.Header.Get("If-None-Match"), book.ETag(), true) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	utils.SuccessResponse(w, http.StatusOK, "Book rretrieved successfully", book)
 }
 
+// LookupBook godoc
+// @Summary Look up book metadata by ISBN
+// @Description Fetch title, author, description and more for an ISBN from the configured metadata provider,
+// @Description to prefill the Create Book form. Results are cached. Requires librarian or admin role.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LookupBookRequest true "ISBN-10 or ISBN-13"
+// @Success 200 {object} utils.Response{data=services.BookLookup}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /books/lookup [post]
+func (h *BookHandler) LookupBook(w http.ResponseWriter, r *http.Request) {
+	var req LookupBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	lookup, err := h.bookService.LookupMetadata(r.Context(), req.ISBN)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Book metadata retrieved successfully", lookup)
+}
+
+// GetBookByISBN godoc
+// @Summary Get book by ISBN
+// @Description Look up a book by ISBN-10 or ISBN-13, with or without hyphens. Both forms of the same ISBN find the same book.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Param isbn path string true "ISBN-10 or ISBN-13"
+// @Success 200 {object} utils.Response{data=models.Book}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	book, err := h.bookService.GetBookByISBN(chi.URLParam(r, "isbn"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Book retrieved successfully", book)
+}
+
 // UpdateBook godoc
 // @Summary Update a book 
-// @Description Update book information (requires authentication) 
+// @Description Update book information (requires librarian or admin role). Stock is derived from available copies and cannot be set here.
+// @Description Omitted author_ids, publisher_ids or genre_ids keep the current links; an empty array removes them.
+// @Description With If-Match the update only succeeds if the book's ETag still matches (412 otherwise).
 // @Tags Books
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Book ID"
+// @Param If-Match header string false "ETag from GET /books/{id}"
 // @Param request body UpdateBookRequest true "Update book details"
 // @Success 200 {object} utils.Response{data=models.Book}
 // @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 412 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /books/{id} [put]
 func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
@@ -197,22 +322,77 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 
 	// Validasi dengan validator
 	if err := utils.ValidateStruct(req); err != nil {
-		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
-	book, err := h.bookService.UpdateBook(uint(id), req.Title, req.Author, req.ISBN, req.Description, req.Stock)
+	book, err := h.bookService.UpdateBook(auditContext(r), uint(id), services.BookInput{
+		Title: req.Title,
+		Author: req.Author,
+		ISBN: req.ISBN,
+		Description: req.Description,
+		AuthorIDs: req.AuthorIDs,
+		PublisherIDs: req.PublisherIDs,
+		GenreIDs: req.GenreIDs,
+	}, r.Header.Get("If-Match"))
 	if err != nil {
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", book.ETag())
+	utils.SuccessResponse(w, http.StatusOK, "Book updated successfully", book)
+}
+
+// PatchBookContentTypes - content type yang diterima oleh PATCH /books/{id}
+var PatchBookContentTypes = []string{"application/merge-patch+json", "application/json"}
+
+// PatchBook godoc
+// @Summary Partially update a book
+// @Description Update some fields of a book with a JSON Merge Patch (RFC 7396) (requires librarian or admin role).
+// @Description Fields that are left out keep their value, null clears description or removes all author, publisher or genre links.
+// @Description The result is validated like PUT. With If-Match the update only succeeds if the book's ETag still matches (412 otherwise).
+// @Tags Books
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Param If-Match header string false "ETag from GET /books/{id}"
+// @Param request body UpdateBookRequest true "Fields to change"
+// @Success 200 {object} utils.Response{data=models.Book}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 412 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id} [patch]
+func (h *BookHandler) PatchBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	book, err := h.bookService.PatchBook(auditContext(r), uint(id), patch, r.Header.Get("If-Match"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", book.ETag())
 	utils.SuccessResponse(w, http.StatusOK, "Book updated successfully", book)
 }
 
 // DeleteBook godoc
 // @Summary Delete a book
-// @Description Delete a book (requires authentication) 
+// @Description Move a book to the trash (requires librarian or admin role). Fails with 409 while copies are on loan.
+// @Description Open holds are cancelled. Deleted books can be restored or permanently deleted under /books/trash.
 // @Tags Books
 // @Accept json
 // @Produce json
@@ -221,6 +401,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} utils.Response{data=models.Book}
 // @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /books/{id} [delete]
 func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
@@ -232,14 +413,11 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.bookService.DeleteBook(uint(id)); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+	if err := h.bookService.DeleteBook(auditContext(r), uint(id)); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	utils.SuccessResponse(w, http.StatusOK, "Book deleted successfully", nil)
-}
\ No newline at end of file
+}
+
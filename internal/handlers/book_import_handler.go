@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxImportBytes - batas ukuran file import lewat API; file yang lebih besar pakai CLI
+const maxImportBytes = 10 << 20
+
+var errImportTooLarge = apperrors.PayloadTooLarge("import_file_too_large", "import file is larger than 10 MB, use the import-books command instead")
+
+// ImportContentTypes - content type yang diterima oleh endpoint import
+var ImportContentTypes = []string{"text/csv", "application/csv", "application/x-ndjson", "application/jsonl"}
+
+type BookImportHandler struct {
+	importService services.BookImportService
+}
+
+func NewBookImportHandler(importService services.BookImportService) *BookImportHandler {
+	return &BookImportHandler{importService: importService}
+}
+
+// ImportBooks godoc
+// @Summary Bulk import books
+// @Description Import books from a CSV file (header row with title, author, isbn, description, stock) or JSON Lines
+// @Description (one CreateBookRequest object per line). Rows are validated like POST /books and upserted by ISBN:
+// @Description new books get `stock` copies, existing books get title, author and description updated.
+// @Description Rows are written in batches; a database error rolls back and fails the whole batch.
+// @Description With dry_run=true everything is validated and written, then rolled back. Requires librarian or admin role.
+// @Tags Books
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "csv or jsonl, defaults to the Content-Type"
+// @Param dry_run query bool false "Validate and report without saving"
+// @Param file body string true "CSV or JSON Lines content"
+// @Success 200 {object} utils.Response{data=services.ImportReport}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/import [post]
+func (h *BookImportHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := services.ImportFormat(query.Get("format"))
+	if format == "" {
+		format = importFormatFromContentType(r.Header.Get("Content-Type"))
+	}
+
+	dryRun := false
+	if dryRunStr := query.Get("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			writeError(w, r, invalidQuery("dry_run", "must be true or false"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	report, err := h.importService.Import(auditContext(r), format, body, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			err = errImportTooLarge
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	message := "Books imported"
+	if dryRun {
+		message = "Import dry run completed, nothing was saved"
+	}
+	utils.SuccessResponse(w, http.StatusOK, message, report)
+}
+
+func importFormatFromContentType(contentType string) services.ImportFormat {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return services.ImportFormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return services.ImportFormatJSONL
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type BookTrashHandler struct {
+	trashService services.BookTrashService
+}
+
+func NewBookTrashHandler(trashService services.BookTrashService) *BookTrashHandler {
+	return &BookTrashHandler{trashService: trashService}
+}
+
+// GetTrashedBooks godoc
+// @Summary List deleted books
+// @Description List books in the trash, most recently deleted first (requires librarian or admin role)
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]services.TrashedBook}}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/trash [get]
+func (h *BookTrashHandler) GetTrashedBooks(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := parsePage(r.URL.Query())
+
+	books, total, err := h.trashService.GetTrashedBooks(page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Deleted books retrieved successfully", paginated(books, total, page, pageSize))
+}
+
+// RestoreBook godoc
+// @Summary Restore a deleted book
+// @Description Move a book out of the trash together with its copies (requires librarian or admin role).
+// @Description Fails with 409 when another book now uses the same ISBN.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} utils.Response{data=models.Book}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/trash/{id}/restore [post]
+func (h *BookTrashHandler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	book, err := h.trashService.RestoreBook(auditContext(r), uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Book restored successfully", book)
+}
+
+// PurgeBook godoc
+// @Summary Permanently delete a book
+// @Description Permanently delete a book in the trash with its copies, holds and cover image (requires librarian or admin role).
+// @Description Books that were ever borrowed stay in the trash because loans and fines refer to them.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/trash/{id} [delete]
+func (h *BookTrashHandler) PurgeBook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	if err := h.trashService.PurgeBook(auditContext(r), uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Book permanently deleted", nil)
+}
+
@@ -5,12 +5,10 @@ import (
 	"book-api/internal/services"
 	"book-api/internal/utils"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"gorm.io/gorm"
 )
 
 type BorrowHandler struct {
@@ -26,12 +24,13 @@ type BorrowBookRequest struct {
 }
 
 type ReturnBookRequest struct {
-	BorrowID uint `json:"borrow_id" validate:"required,gte=0"`
+	Barcode string `json:"barcode" validate:"required,max=64"`
 }
 
 // BorrowBook godoc
 // @Summary Borrow a book
-// @Description Borrow a book (requires authentication, decreases stock) 
+// @Description Borrow a book (requires authentication). An available copy is checked out to you, or the copy set aside for your ready hold. Refused when the active loan limit is reached,
+// @Description the book is already on loan to you, or you have overdue loans or outstanding fines above the limit.
 // @Tags Borrows
 // @Accept json
 // @Produce json
@@ -39,8 +38,11 @@ type ReturnBookRequest struct {
 // @Param request body BorrowBookRequest true "Book ID to borrow"
 // @Success 201 {object} utils.Response{data=models.Borrow}
 // @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /borrows [post]
 func (h *BorrowHandler) BorrowBook(w http.ResponseWriter, r *http.Request) {
@@ -58,18 +60,14 @@ func (h *BorrowHandler) BorrowBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := utils.ValidateStruct(req); err != nil {
-		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
 	// Borrow book
-	borrow, err := h.borrowService.BorrowBook(claims.UserID, req.BookID)
+	borrow, err := h.borrowService.BorrowBook(auditContext(r), claims.UserID, req.BookID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
@@ -78,14 +76,16 @@ func (h *BorrowHandler) BorrowBook(w http.ResponseWriter, r *http.Request) {
 
 // ReturnBook godoc
 // @Summary Return a borrowed book
-// @Description Return a borrowe book (requires authentication, increases stock) 
+// @Description Check in a borrowed copy by its barcode (requires authentication). The copy goes to the next hold in the queue
+// @Description or back on the shelf. Members can only return their own borrows; librarians and admins can return any borrow.
 // @Tags Borrows
 // @Accept json
 // @Produce json
 // @Security BeareAuth
-// @Param request body ReturnBookRequest true "Book ID to return"
+// @Param request body ReturnBookRequest true "Barcode of the copy to return"
 // @Success 200 {object} utils.Response{data=models.Borrow}
 // @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -104,24 +104,59 @@ func (h *BorrowHandler) ReturnBook(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := utils.ValidateStruct(req); err != nil {
-		utils.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
 	// Return borrowed
-	borrow, err := h.borrowService.ReturnBook(req.BorrowID)
+	borrow, err := h.borrowService.ReturnBook(auditContext(r), actorFromClaims(claims), req.Barcode)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
 	utils.SuccessResponse(w, http.StatusOK, "Book returned successfully", borrow)
 }
 
+// RenewBorrow godoc
+// @Summary Renew a borrowed book
+// @Description Extend the due date of one of your own borrows (librarians and admins can renew any borrow). Refused when the renewal limit is reached, the borrow is overdue
+// @Description or another member has a hold on the book.
+// @Tags Borrows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Borrow ID"
+// @Success 200 {object} utils.Response{data=models.Borrow}
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /borrow/{id}/renew [post]
+func (h *BorrowHandler) RenewBorrow(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid borrow ID")
+		return
+	}
+
+	borrow, err := h.borrowService.RenewBorrow(auditContext(r), actorFromClaims(claims), uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Borrow renewed successfully", borrow)
+}
+
 // GetMyBorrows godoc
 // @Summary Get my borrow history
 // @Description Get current user`s borrow history with pagination 
@@ -143,66 +178,41 @@ func (h *BorrowHandler) GetMyBorrows(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pageStr := r.URL.Query().Get("page")
-	pageSizeStr := r.URL.Query().Get("page_size")
-
-	page := 1
-	pageSize := 10
-
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
-	if pageSizeStr != "" {
-		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
-			pageSize = ps
-		}
-	}
+	page, pageSize := parsePage(r.URL.Query())
 
 	// 'total' it contain all count borrowed
 	borrows, total, err := h.borrowService.GetUserBorrows(claims.UserID, page, pageSize)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
-	// Count pages for paginate (total borrowed / pageSize)  
-	totalPages := int(total) / pageSize
-	if int(total)%pageSize != 0 {
-		// tambah satu halaman paginate jika ada sisa
-		totalPages++
-	}
-
-	response := utils.PaginatedResponse{
-		Data 			: borrows,
-		Page 			: page,
-		PageSize 		: pageSize,
-		TotalItems 		: int(total),
-		TotalPages		: totalPages,
-	}
+	response := paginated(borrows, total, page, pageSize)
 
 	utils.SuccessResponse(w, http.StatusOK, "Borrow retrieved successfully", response)
 }
 
 // GetBorrowByID godoc
 // @Summary Get a borrow history
-// @Description Get current user`s a borrow history 
+// @Description Get one of current user`s borrows. Librarians and admins can get any borrow.
 // @Tags Borrows
 // @Accept json
 // @Produce json
-// @Security BeareAuth
-// @Param id query int true "Borrow ID to get"
+// @Security BearerAuth
+// @Param id path int true "Borrow ID to get"
 // @Success 200 {object} utils.Response{data=models.Borrow}
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Failure 500 {object} utils.Response
-// @Router /borrows/me [get]
+// @Router /borrow/{id} [get]
 func (h *BorrowHandler) GetBorrowByID(w http.ResponseWriter, r * http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 32)
 	if err != nil {
@@ -210,15 +220,39 @@ func (h *BorrowHandler) GetBorrowByID(w http.ResponseWriter, r * http.Request) {
 		return
 	}
 	
-	borrow, err := h.borrowService.GetBorrowByID(uint(id))
+	borrow, err := h.borrowService.GetBorrowByID(actorFromClaims(claims), uint(id))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.ErrorResponse(w, http.StatusNotFound, err.Error())
-			return
-		}
-		utils.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 
 	utils.SuccessResponse(w, http.StatusOK, "Borrow retrieved successfully", borrow)
+}
+
+// GetOverdueBorrows godoc
+// @Summary Get overdue borrows
+// @Description Get all unreturned borrows past their due date with pagination (requires admin role)
+// @Tags Borrows
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /borrows/overdue [get]
+func (h *BorrowHandler) GetOverdueBorrows(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := parsePage(r.URL.Query())
+
+	borrows, total, err := h.borrowService.GetOverdueBorrows(page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := paginated(borrows, total, page, pageSize)
+
+	utils.SuccessResponse(w, http.StatusOK, "Overdue borrows retrieved successfully", response)
 }
\ No newline at end of file
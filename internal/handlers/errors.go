@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/isbn"
+	"book-api/internal/logging"
+	"book-api/internal/metadata"
+	"book-api/internal/utils"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// kindStatus - HTTP status untuk setiap jenis error domain
+var kindStatus = map[apperrors.Kind]int{
+	apperrors.KindNotFound:           http.StatusNotFound,
+	apperrors.KindConflict:           http.StatusConflict,
+	apperrors.KindValidation:         http.StatusBadRequest,
+	apperrors.KindForbidden:          http.StatusForbidden,
+	apperrors.KindBusinessRule:       http.StatusUnprocessableEntity,
+	apperrors.KindUnauthorized:       http.StatusUnauthorized,
+	apperrors.KindPreconditionFailed: http.StatusPreconditionFailed,
+	apperrors.KindPayloadTooLarge:    http.StatusRequestEntityTooLarge,
+	apperrors.KindUnsupportedMedia:   http.StatusUnsupportedMediaType,
+	apperrors.KindUnavailable:        http.StatusServiceUnavailable,
+}
+
+// writeError - satu-satunya penerjemah error service ke response HTTP. Error domain
+// memakai status dari Kind dan Code-nya; error lain yang tidak dikenal menjadi 500
+// tanpa membocorkan pesan aslinya ke client. Detail field dari utils.ValidateStruct
+// ikut dikirim jika client memakai problem+json.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code, message := translateError(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("internal error", "error", err)
+	}
+	utils.ErrorResponseWithDetails(w, status, code, message, utils.ValidationFields(err))
+}
+
+func translateError(err error) (status int, code, message string) {
+	if domainErr := apperrors.As(err); domainErr != nil {
+		status, ok := kindStatus[domainErr.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		// err.Error() bukan domainErr.Message supaya detail dari fmt.Errorf("%w: ...") ikut
+		return status, domainErr.Code, err.Error()
+	}
+
+	// Error dari package di luar service yang bisa sampai ke handler
+	var validationErr *utils.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, "validation_failed", err.Error()
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound, utils.StatusCode(http.StatusNotFound), err.Error()
+	case errors.Is(err, metadata.ErrNotFound):
+		return http.StatusNotFound, "metadata_not_found", err.Error()
+	case errors.Is(err, metadata.ErrUnavailable):
+		return http.StatusServiceUnavailable, "metadata_unavailable", err.Error()
+	case errors.Is(err, isbn.ErrInvalidLength),
+		errors.Is(err, isbn.ErrInvalidCharacter),
+		errors.Is(err, isbn.ErrInvalidChecksum):
+		return http.StatusBadRequest, "invalid_isbn", err.Error()
+	}
+
+	status = http.StatusInternalServerError
+	return status, utils.StatusCode(status), "internal server error"
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"book-api/internal/logging"
+	"book-api/internal/models"
+	"book-api/internal/services"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// exportContentTypes - Content-Type response per format export
+var exportContentTypes = map[services.ExportFormat]string{
+	services.ExportFormatCSV:   "text/csv; charset=utf-8",
+	services.ExportFormatJSONL: "application/x-ndjson",
+}
+
+type ExportHandler struct {
+	exportService services.ExportService
+}
+
+func NewExportHandler(exportService services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// ExportBooks godoc
+// @Summary Export books
+// @Description Stream the catalog as CSV or JSON Lines. Accepts the same filters and sort as GET /books, without pagination.
+// @Description The CSV columns title, author, isbn, description and stock can be fed back into POST /books/import.
+// @Description Requires librarian or admin role.
+// @Tags Export
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "csv or jsonl" default(csv)
+// @Param q query string false "Full-text search in title, author and description"
+// @Param author query string false "Filter by author (partial match)"
+// @Param isbn query string false "Filter by exact ISBN"
+// @Param in_stock query bool false "true = only available books, false = only out of stock"
+// @Param sort query string false "Comma separated fields, prefix with - for descending"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /export/books [get]
+func (h *ExportHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format, err := parseExportFormat(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := parseBookFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	out := newAttachmentWriter(w, r, format, "books")
+	out.finish(h.exportService.ExportBooks(out, format, filter))
+}
+
+// ExportBorrows godoc
+// @Summary Export borrows
+// @Description Stream the loan history as CSV or JSON Lines, ordered by borrow ID. Requires librarian or admin role.
+// @Tags Export
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "csv or jsonl" default(csv)
+// @Param user_id query int false "Only borrows of this user"
+// @Param book_id query int false "Only borrows of this book"
+// @Param status query string false "borrowed, returned or overdue"
+// @Param overdue query bool false "true = only unreturned borrows past their due date (same as GET /borrows/overdue)"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /export/borrows [get]
+func (h *ExportHandler) ExportBorrows(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format, err := parseExportFormat(query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := parseBorrowExportFilter(query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	out := newAttachmentWriter(w, r, format, "borrows")
+	out.finish(h.exportService.ExportBorrows(out, format, filter))
+}
+
+// parseBorrowExportFilter - query parameter filter export peminjaman
+func parseBorrowExportFilter(query url.Values) (models.BorrowFilter, error) {
+	var filter models.BorrowFilter
+	for _, param := range []struct {
+		name   string
+		target *uint
+	}{
+		{"user_id", &filter.UserID},
+		{"book_id", &filter.BookID},
+	} {
+		if raw := query.Get(param.name); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil || id == 0 {
+				return filter, invalidQuery(param.name, "must be a positive integer")
+			}
+			*param.target = uint(id)
+		}
+	}
+
+	if status := models.BorrowStatus(query.Get("status")); status != "" {
+		switch status {
+		case models.BorrowStatusBorrowed, models.BorrowStatusReturned, models.BorrowStatusOverdue:
+			filter.Status = status
+		default:
+			return filter, invalidQuery("status", "must be one of [borrowed returned overdue]")
+		}
+	}
+
+	if overdueStr := query.Get("overdue"); overdueStr != "" {
+		overdue, err := strconv.ParseBool(overdueStr)
+		if err != nil {
+			return filter, invalidQuery("overdue", "must be true or false")
+		}
+		if overdue {
+			now := time.Now()
+			filter.OverdueAt = &now
+		}
+	}
+	return filter, nil
+}
+
+func parseExportFormat(query url.Values) (services.ExportFormat, error) {
+	format := services.ExportFormat(query.Get("format"))
+	if format == "" {
+		return services.ExportFormatCSV, nil
+	}
+	if _, ok := exportContentTypes[format]; !ok {
+		return "", services.ErrUnsupportedExportFormat
+	}
+	return format, nil
+}
+
+// attachmentWriter - header download baru dikirim saat byte pertama ditulis, supaya
+// error sebelum data pertama masih bisa dibalas sebagai JSON biasa
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	r           *http.Request
+	contentType string
+	filename    string
+	started     bool
+}
+
+func newAttachmentWriter(w http.ResponseWriter, r *http.Request, format services.ExportFormat, name string) *attachmentWriter {
+	return &attachmentWriter{
+		w:           w,
+		r:           r,
+		contentType: exportContentTypes[format],
+		filename:    fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102-150405"), format),
+	}
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	a.start()
+	return a.w.Write(p)
+}
+
+func (a *attachmentWriter) Flush() {
+	if flusher, ok := a.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (a *attachmentWriter) start() {
+	if a.started {
+		return
+	}
+	a.started = true
+	a.w.Header().Set("Content-Type", a.contentType)
+	a.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.filename))
+	a.w.WriteHeader(http.StatusOK)
+}
+
+// finish - selesaikan response. Error setelah data mulai terkirim hanya bisa di-log;
+// client menerima file yang terpotong.
+func (a *attachmentWriter) finish(err error) {
+	if err == nil {
+		a.start()
+		return
+	}
+	if a.started {
+		logging.FromContext(a.r.Context()).Error("export aborted", "file", a.filename, "error", err)
+		return
+	}
+	writeError(a.w, a.r, err)
+}
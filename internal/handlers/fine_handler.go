@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"book-api/internal/middlewares"
+	"book-api/internal/models"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type FineHandler struct {
+	fineService services.FineService
+}
+
+func NewFineHandler(fineService services.FineService) *FineHandler {
+	return &FineHandler{fineService: fineService}
+}
+
+type ChargeFineRequest struct {
+	Type     string `json:"type" validate:"required,oneof=lost damaged"`
+	Amount   int64  `json:"amount" validate:"required,gt=0"`
+	BorrowID *uint  `json:"borrow_id,omitempty"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type FineAdjustmentRequest struct {
+	Amount int64  `json:"amount" validate:"required,gt=0"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+// FineLedgerResponse - saldo outstanding beserta entri ledger (paginated)
+type FineLedgerResponse struct {
+	Balance int64 `json:"balance"`
+	utils.PaginatedResponse
+}
+
+// GetMyFines godoc
+// @Summary Get my fines
+// @Description Get current user`s outstanding fine balance and fine ledger with pagination
+// @Tags Fines
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=FineLedgerResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /me/fines [get]
+func (h *FineHandler) GetMyFines(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	h.writeLedger(w, r, claims.UserID)
+}
+
+// GetUserFines godoc
+// @Summary Get a user's fines
+// @Description Get the outstanding fine balance and fine ledger of a user (requires admin role)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=FineLedgerResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/users/{id}/fines [get]
+func (h *FineHandler) GetUserFines(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	h.writeLedger(w, r, uint(userID))
+}
+
+// ChargeFine godoc
+// @Summary Charge a fine
+// @Description Charge a user for a lost or damaged item (requires admin role). Amount is in the smallest currency unit.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body ChargeFineRequest true "Fine details"
+// @Success 201 {object} utils.Response{data=models.Fine}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/users/{id}/fines [post]
+func (h *FineHandler) ChargeFine(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req ChargeFineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	fine, err := h.fineService.ChargeFine(r.Context(), claims.UserID, uint(userID), models.FineType(req.Type), req.Amount, req.BorrowID, req.Note)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Fine charged successfully", fine)
+}
+
+// RecordPayment godoc
+// @Summary Record a fine payment
+// @Description Record a payment against a user's outstanding fines (requires admin role). Amount is in the smallest currency unit.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body FineAdjustmentRequest true "Payment details"
+// @Success 201 {object} utils.Response{data=models.Fine}
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/users/{id}/fines/payments [post]
+func (h *FineHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	h.adjust(w, r, h.fineService.RecordPayment, "Payment recorded successfully")
+}
+
+// WaiveFine godoc
+// @Summary Waive fines
+// @Description Waive part or all of a user's outstanding fines (requires admin role). Amount is in the smallest currency unit.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body FineAdjustmentRequest true "Waiver details"
+// @Success 201 {object} utils.Response{data=models.Fine}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/users/{id}/fines/waivers [post]
+func (h *FineHandler) WaiveFine(w http.ResponseWriter, r *http.Request) {
+	h.adjust(w, r, h.fineService.WaiveFine, "Fine waived successfully")
+}
+
+// adjust - alur bersama untuk payment dan waiver
+func (h *FineHandler) adjust(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, recordedBy, userID uint, amount int64, note string) (*models.Fine, error), message string) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	userID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req FineAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	fine, err := apply(r.Context(), claims.UserID, uint(userID), req.Amount, req.Note)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, message, fine)
+}
+
+func (h *FineHandler) writeLedger(w http.ResponseWriter, r *http.Request, userID uint) {
+	page, pageSize := parsePage(r.URL.Query())
+
+	fines, total, err := h.fineService.GetUserFines(userID, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	balance, err := h.fineService.GetBalance(userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := FineLedgerResponse{
+		Balance: balance,
+		PaginatedResponse: paginated(fines, total, page, pageSize),
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Fines retrieved successfully", response)
+}
+
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GenreHandler struct {
+	genreService services.GenreService
+	bookService  services.BookService
+}
+
+func NewGenreHandler(genreService services.GenreService, bookService services.BookService) *GenreHandler {
+	return &GenreHandler{genreService: genreService, bookService: bookService}
+}
+
+// CreateGenre godoc
+// @Summary Create a genre
+// @Description Create a genre (requires librarian or admin role). Names that differ only in spacing, punctuation or case are the same genre.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.GenreInput true "Genre details"
+// @Success 201 {object} utils.Response{data=models.Genre}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /genres [post]
+func (h *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
+	var req services.GenreInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	genre, err := h.genreService.CreateGenre(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Genre created successfully", genre)
+}
+
+// GetGenres godoc
+// @Summary Get all genres
+// @Description Get list of genres ordered by name, with pagination
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param q query string false "Name contains (case-insensitive)"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.Genre}}
+// @Failure 500 {object} utils.Response
+// @Router /genres [get]
+func (h *GenreHandler) GetGenres(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := parsePage(r.URL.Query())
+
+	genres, total, err := h.genreService.GetGenres(strings.TrimSpace(r.URL.Query().Get("q")), page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Genres retrieved successfully", paginated(genres, total, page, pageSize))
+}
+
+// GetGenreByID godoc
+// @Summary Get genre by ID
+// @Description Get a genre
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Success 200 {object} utils.Response{data=models.Genre}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /genres/{id} [get]
+func (h *GenreHandler) GetGenreByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	genre, err := h.genreService.GetGenreByID(uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Genre retrieved successfully", genre)
+}
+
+// GetGenreBooks godoc
+// @Summary Get books in a genre
+// @Description Get the books of a genre with pagination. Accepts the same search, filter and sort parameters as GET /books.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param id path int true "Genre ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param q query string false "Full-text search over title, author and description"
+// @Param author_id query int false "Only books by this author"
+// @Param in_stock query bool false "Only books in stock (true) or out of stock (false)"
+// @Param sort query string false "Comma-separated sort fields, see GET /books"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.Book}}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /genres/{id}/books [get]
+func (h *GenreHandler) GetGenreBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	if _, err := h.genreService.GetGenreByID(uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := parseBookFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.GenreID = uint(id)
+
+	page, pageSize := parsePage(r.URL.Query())
+	books, total, err := h.bookService.GetAllBooks(filter, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Books retrieved successfully", paginated(books, total, page, pageSize))
+}
+
+// UpdateGenre godoc
+// @Summary Update a genre
+// @Description Update a genre (requires librarian or admin role). Books show the new name.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Genre ID"
+// @Param request body services.GenreInput true "Genre details"
+// @Success 200 {object} utils.Response{data=models.Genre}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /genres/{id} [put]
+func (h *GenreHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	var req services.GenreInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	genre, err := h.genreService.UpdateGenre(uint(id), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Genre updated successfully", genre)
+}
+
+// DeleteGenre godoc
+// @Summary Delete a genre
+// @Description Delete a genre (requires librarian or admin role). Genres that still have books cannot be deleted.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Genre ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /genres/{id} [delete]
+func (h *GenreHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	if err := h.genreService.DeleteGenre(uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Genre deleted successfully", nil)
+}
+
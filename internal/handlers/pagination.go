@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/utils"
+	"net/url"
+	"strconv"
+)
+
+// parsePage - query parameter page dan page_size. Nilai tidak valid memakai default,
+// sama dengan batas yang dipakai service (page_size maksimal 100).
+func parsePage(query url.Values) (page, pageSize int) {
+	page, pageSize = 1, 10
+
+	if p, err := strconv.Atoi(query.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if ps, err := strconv.Atoi(query.Get("page_size")); err == nil && ps > 0 && ps <= 100 {
+		pageSize = ps
+	}
+	return page, pageSize
+}
+
+// invalidQuery - error validasi query parameter dengan code invalid_<nama parameter>,
+// misalnya invalid_in_stock, supaya client bisa tahu parameter mana yang salah
+func invalidQuery(param, message string) error {
+	return apperrors.Validation("invalid_"+param, param+" "+message)
+}
+
+// paginated - data satu halaman beserta total item dan total halaman
+func paginated(data interface{}, total int64, page, pageSize int) utils.PaginatedResponse {
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize != 0 {
+		totalPages++
+	}
+
+	return utils.PaginatedResponse{
+		Data:       data,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: int(total),
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PublisherHandler struct {
+	publisherService services.PublisherService
+	bookService      services.BookService
+}
+
+func NewPublisherHandler(publisherService services.PublisherService, bookService services.BookService) *PublisherHandler {
+	return &PublisherHandler{publisherService: publisherService, bookService: bookService}
+}
+
+// CreatePublisher godoc
+// @Summary Create a publisher
+// @Description Create a publisher (requires librarian or admin role). Names that differ only in spacing, punctuation or case are the same publisher.
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.PublisherInput true "Publisher details"
+// @Success 201 {object} utils.Response{data=models.Publisher}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /publishers [post]
+func (h *PublisherHandler) CreatePublisher(w http.ResponseWriter, r *http.Request) {
+	var req services.PublisherInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	publisher, err := h.publisherService.CreatePublisher(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Publisher created successfully", publisher)
+}
+
+// GetPublishers godoc
+// @Summary Get all publishers
+// @Description Get list of publishers ordered by name, with pagination
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param q query string false "Name contains (case-insensitive)"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.Publisher}}
+// @Failure 500 {object} utils.Response
+// @Router /publishers [get]
+func (h *PublisherHandler) GetPublishers(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := parsePage(r.URL.Query())
+
+	publishers, total, err := h.publisherService.GetPublishers(strings.TrimSpace(r.URL.Query().Get("q")), page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Publishers retrieved successfully", paginated(publishers, total, page, pageSize))
+}
+
+// GetPublisherByID godoc
+// @Summary Get publisher by ID
+// @Description Get a publisher
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Success 200 {object} utils.Response{data=models.Publisher}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /publishers/{id} [get]
+func (h *PublisherHandler) GetPublisherByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	publisher, err := h.publisherService.GetPublisherByID(uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Publisher retrieved successfully", publisher)
+}
+
+// GetPublisherBooks godoc
+// @Summary Get books from a publisher
+// @Description Get the books of a publisher with pagination. Accepts the same search, filter and sort parameters as GET /books.
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Param id path int true "Publisher ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Param q query string false "Full-text search over title, author and description"
+// @Param genre_id query int false "Only books in this genre"
+// @Param in_stock query bool false "Only books in stock (true) or out of stock (false)"
+// @Param sort query string false "Comma-separated sort fields, see GET /books"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{data=[]models.Book}}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /publishers/{id}/books [get]
+func (h *PublisherHandler) GetPublisherBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	if _, err := h.publisherService.GetPublisherByID(uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := parseBookFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.PublisherID = uint(id)
+
+	page, pageSize := parsePage(r.URL.Query())
+	books, total, err := h.bookService.GetAllBooks(filter, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Books retrieved successfully", paginated(books, total, page, pageSize))
+}
+
+// UpdatePublisher godoc
+// @Summary Update a publisher
+// @Description Update a publisher (requires librarian or admin role). The publisher name is not copied onto books, so books show the new name.
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Publisher ID"
+// @Param request body services.PublisherInput true "Publisher details"
+// @Success 200 {object} utils.Response{data=models.Publisher}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /publishers/{id} [put]
+func (h *PublisherHandler) UpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	var req services.PublisherInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	publisher, err := h.publisherService.UpdatePublisher(uint(id), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Publisher updated successfully", publisher)
+}
+
+// DeletePublisher godoc
+// @Summary Delete a publisher
+// @Description Delete a publisher (requires librarian or admin role). Publishers that still have books cannot be deleted.
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Publisher ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /publishers/{id} [delete]
+func (h *PublisherHandler) DeletePublisher(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid publisher ID")
+		return
+	}
+
+	if err := h.publisherService.DeletePublisher(uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Publisher deleted successfully", nil)
+}
+
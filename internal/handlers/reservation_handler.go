@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"book-api/internal/middlewares"
+	"book-api/internal/services"
+	"book-api/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReservationHandler struct {
+	reservationService services.ReservationService
+}
+
+func NewReservationHandler(reservationService services.ReservationService) *ReservationHandler {
+	return &ReservationHandler{reservationService: reservationService}
+}
+
+// PlaceHold godoc
+// @Summary Place a hold on a book
+// @Description Join the FIFO hold queue of an out-of-stock book. When a copy is returned it is set aside
+// @Description for the first member in the queue until the pickup deadline. Members who already have the
+// @Description book on loan cannot hold it.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 201 {object} utils.Response{data=models.Reservation}
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/holds [post]
+func (h *ReservationHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	bookID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	reservation, err := h.reservationService.PlaceHold(r.Context(), claims.UserID, uint(bookID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusCreated, "Hold placed successfully", reservation)
+}
+
+// CancelHold godoc
+// @Summary Cancel a hold on a book
+// @Description Cancel your active hold on a book. A copy already set aside for you passes to the next member in the queue.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Book ID"
+// @Success 200 {object} utils.Response{data=models.Reservation}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /books/{id}/holds [delete]
+func (h *ReservationHandler) CancelHold(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	bookID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(w, http.StatusBadRequest, "Invalid book ID")
+		return
+	}
+
+	reservation, err := h.reservationService.CancelHold(auditContext(r), claims.UserID, uint(bookID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, "Hold cancelled successfully", reservation)
+}
+
+// GetMyHolds godoc
+// @Summary Get my holds
+// @Description Get current user`s holds with pagination
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /me/holds [get]
+func (h *ReservationHandler) GetMyHolds(w http.ResponseWriter, r *http.Request) {
+	claims := middlewares.GetUserFromContext(r)
+	if claims == nil {
+		utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	page, pageSize := parsePage(r.URL.Query())
+
+	reservations, total, err := h.reservationService.GetUserHolds(claims.UserID, page, pageSize)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := paginated(reservations, total, page, pageSize)
+
+	utils.SuccessResponse(w, http.StatusOK, "Holds retrieved successfully", response)
+}
@@ -0,0 +1,76 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Fit - perkecil src supaya muat di kotak maxSize x maxSize dengan rasio tetap.
+// Gambar yang sudah lebih kecil tidak diperbesar. Bagian transparan diberi latar
+// putih karena thumbnail disimpan sebagai JPEG.
+func Fit(src image.Image, maxSize int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := fitSize(bounds.Dx(), bounds.Dy(), maxSize)
+
+	// Ratakan ke RGBA dulu supaya perhitungan di bawah bisa langsung membaca Pix
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return flat
+	}
+	return boxResize(flat, width, height)
+}
+
+// fitSize - ukuran hasil Fit, minimal 1x1
+func fitSize(width, height, maxSize int) (int, int) {
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
+
+// boxResize - setiap pixel hasil adalah rata-rata area pixel sumber yang diwakilinya.
+// Hanya untuk memperkecil; cukup halus untuk thumbnail tanpa dependency tambahan.
+func boxResize(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, (y+1)*srcH/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, (x+1)*srcW/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
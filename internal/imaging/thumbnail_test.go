@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFit - rasio dipertahankan dan gambar kecil tidak diperbesar
+func TestFit_Size(t *testing.T) {
+	tests := []struct {
+		width, height	int
+		wantW, wantH	int
+	}{
+		{800, 1200, 160, 240},
+		{1200, 800, 240, 160},
+		{100, 150, 100, 150},
+		{5000, 2, 240, 1},
+	}
+
+	for _, tt := range tests {
+		src := image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))
+		got := Fit(src, 240)
+		assert.Equal(t, tt.wantW, got.Bounds().Dx(), "%dx%d", tt.width, tt.height)
+		assert.Equal(t, tt.wantH, got.Bounds().Dy(), "%dx%d", tt.width, tt.height)
+	}
+}
+
+// TestFit - warna dirata-rata dan area transparan menjadi putih
+func TestFit_Colors(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		src.Set(0, y, color.NRGBA{R: 255, A: 255})
+		src.Set(1, y, color.NRGBA{B: 255, A: 255})
+		// x = 2, 3 transparan
+	}
+
+	got := Fit(src, 2)
+
+	assert.Equal(t, color.RGBA{R: 127, B: 127, A: 255}, got.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, got.RGBAAt(1, 0))
+}
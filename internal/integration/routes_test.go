@@ -0,0 +1,291 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"book-api/internal/database"
+	"book-api/internal/handlers"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"book-api/internal/routes"
+	"book-api/internal/services"
+	"book-api/internal/storage"
+	"book-api/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJWTSecret = "routes-test-secret"
+
+// testServer - router lengkap dari routes.SetupRoutes di atas database testEnv
+type testServer struct {
+	env    *testEnv
+	auth   services.AuthService
+	router http.Handler
+}
+
+// newTestServer - semua handler dan middleware seperti di cmd/server, dengan
+// ERROR_FORMAT=legacy sehingga problem+json hanya lewat header Accept
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	env := newTestEnv(t)
+	db := env.db
+
+	userRepo := repository.NewUserRepository(db)
+	bookRepo := repository.NewBookRepository(db)
+	borrowRepo := repository.NewBorrowRepository(db)
+	tokenRepo := repository.NewRefreshTokenRepository(db)
+	fineRepo := repository.NewFineRepository(db)
+	copyRepo := repository.NewBookCopyRepository(db)
+	authorRepo := repository.NewAuthorRepository(db)
+	publisherRepo := repository.NewPublisherRepository(db)
+	genreRepo := repository.NewGenreRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	txManager := database.NewTransactionManager(db)
+
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	authService := services.NewAuthService(userRepo, tokenRepo, txManager, 15*time.Minute, 24*time.Hour)
+	bookService := env.books
+	authorService := services.NewAuthorService(authorRepo)
+	publisherService := services.NewPublisherService(publisherRepo)
+	genreService := services.NewGenreService(genreRepo)
+
+	router := routes.SetupRoutes(
+		handlers.NewAuthHandler(authService, testJWTSecret),
+		handlers.NewBookHandler(bookService),
+		handlers.NewBorrowHandler(env.borrows),
+		handlers.NewUserHandler(env.roles),
+		handlers.NewReservationHandler(env.holds),
+		handlers.NewFineHandler(services.NewFineService(fineRepo, userRepo, borrowRepo, txManager)),
+		handlers.NewBookCopyHandler(env.copies),
+		handlers.NewBookImportHandler(services.NewBookImportService(bookRepo, copyRepo, authorRepo, publisherRepo, genreRepo, auditRepo, txManager, 100)),
+		handlers.NewExportHandler(services.NewExportService(bookRepo, borrowRepo)),
+		handlers.NewAuthorHandler(authorService, bookService),
+		handlers.NewPublisherHandler(publisherService, bookService),
+		handlers.NewGenreHandler(genreService, bookService),
+		handlers.NewBookCoverHandler(services.NewBookCoverService(bookRepo, auditRepo, txManager, store), 1<<20),
+		handlers.NewBookTrashHandler(env.trash),
+		handlers.NewAuditHandler(env.audit),
+		testJWTSecret,
+		authService,
+		utils.ErrorFormatLegacy,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		nil,
+		nil,
+	)
+
+	return &testServer{env: env, auth: authService, router: router}
+}
+
+// login - user baru dengan role ini, dikembalikan access token-nya
+func (s *testServer) login(t *testing.T, name string, role models.Role) string {
+	t.Helper()
+
+	email := name + "@example.com"
+	user, err := s.auth.Register(name, email, "secret123")
+	require.NoError(t, err)
+	require.NoError(t, s.env.db.Model(user).Update("role", role).Error)
+
+	pair, err := s.auth.Login(context.Background(), email, "secret123", testJWTSecret)
+	require.NoError(t, err)
+	return pair.AccessToken
+}
+
+func (s *testServer) do(req *http.Request, token string) *httptest.ResponseRecorder {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *testServer) createBook(t *testing.T) *models.Book {
+	t.Helper()
+
+	book, err := s.env.books.CreateBook(context.Background(), services.BookInput{Title: "Ronggeng Dukuh Paruk", Author: "Ahmad Tohari", ISBN: "9789792201963", Stock: 1})
+	require.NoError(t, err)
+	return book
+}
+
+// coverRequest - body multipart dengan satu file di field cover
+func coverRequest(t *testing.T, bookID uint, data []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("cover", "cover.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/books/"+strconv.FormatUint(uint64(bookID), 10)+"/cover", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 40, 60))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 60; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 6), G: uint8(y * 4), B: 120, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// Test upload cover - multipart lolos AllowContentType dan gambar diterima
+func TestRoutes_UploadCover(t *testing.T) {
+	server := newTestServer(t)
+	token := server.login(t, "librarian", models.RoleLibrarian)
+	book := server.createBook(t)
+
+	rec := server.do(coverRequest(t, book.ID, testPNG(t)), token)
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+// Test upload cover - file yang bukan gambar ditolak 415 dengan code stabil
+func TestRoutes_UploadCoverNotAnImage(t *testing.T) {
+	server := newTestServer(t)
+	token := server.login(t, "librarian", models.RoleLibrarian)
+	book := server.createBook(t)
+
+	rec := server.do(coverRequest(t, book.ID, []byte("just some text")), token)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"unsupported_cover_type"`)
+}
+
+// Test upload cover - file di atas batas ukuran ditolak 413 lewat writeError
+func TestRoutes_UploadCoverTooLarge(t *testing.T) {
+	server := newTestServer(t)
+	token := server.login(t, "librarian", models.RoleLibrarian)
+	book := server.createBook(t)
+
+	rec := server.do(coverRequest(t, book.ID, bytes.Repeat([]byte{0xff}, 2<<20)), token)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"cover_file_too_large"`)
+}
+
+// Test route JSON - content type lain tetap ditolak 415
+func TestRoutes_JSONOnlyRejectsOtherContentTypes(t *testing.T) {
+	server := newTestServer(t)
+	token := server.login(t, "librarian", models.RoleLibrarian)
+
+	cases := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+	}{
+		{"login as form", http.MethodPost, "/api/v1/login", "application/x-www-form-urlencoded"},
+		{"create book as csv", http.MethodPost, "/api/v1/books", "text/csv"},
+		{"create book as multipart", http.MethodPost, "/api/v1/books", "multipart/form-data; boundary=x"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader("title=x"))
+			req.Header.Set("Content-Type", tc.contentType)
+
+			rec := server.do(req, token)
+
+			assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		})
+	}
+}
+
+// Test import dan merge patch - dikecualikan dari aturan JSON-only
+func TestRoutes_ContentTypeExemptions(t *testing.T) {
+	server := newTestServer(t)
+	token := server.login(t, "librarian", models.RoleLibrarian)
+	book := server.createBook(t)
+
+	csv := "title,author,isbn,stock\nSaman,Ayu Utami,9789799023179,2\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/books/import", strings.NewReader(csv))
+	req.Header.Set("Content-Type", "text/csv")
+	rec := server.do(req, token)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"created":1`)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/books/"+strconv.FormatUint(uint64(book.ID), 10), strings.NewReader(`{"description":"Trilogi"}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	rec = server.do(req, token)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Import tetap hanya menerima CSV / JSON Lines
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/books/import", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = server.do(req, token)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+// Test logout - access token yang sesinya sudah dicabut ditolak 401
+func TestRoutes_RevokedAccessToken(t *testing.T) {
+	server := newTestServer(t)
+	token := server.login(t, "member", models.RoleMember)
+
+	rec := server.do(httptest.NewRequest(http.MethodGet, "/api/v1/me/holds", nil), token)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/logout", nil)
+	req.Header.Set("Content-Type", "application/json")
+	rec = server.do(req, token)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = server.do(httptest.NewRequest(http.MethodGet, "/api/v1/me/holds", nil), token)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// Test problem+json - Accept memilih problem+json, tanpa Accept tetap format lama
+func TestRoutes_ProblemJSON(t *testing.T) {
+	server := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/books/999", nil)
+	req.Header.Set("Accept", utils.ProblemContentType)
+	rec := server.do(req, "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, utils.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var problem utils.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "book_not_found", problem.Code)
+	assert.Equal(t, "urn:book-api:problem:book_not_found", problem.Type)
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.NotEmpty(t, problem.Instance)
+
+	rec = server.do(httptest.NewRequest(http.MethodGet, "/api/v1/books/999", nil), "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	var legacy utils.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &legacy))
+	assert.False(t, legacy.Success)
+	assert.Equal(t, "book_not_found", legacy.Code)
+}
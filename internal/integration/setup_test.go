@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"book-api/internal/config"
+	"book-api/internal/database"
+	"book-api/internal/migrations"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"book-api/internal/services"
+	"book-api/internal/storage"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testEnv - repository dan service asli di atas database SQLite in-memory
+type testEnv struct {
+	db		*gorm.DB
+	users	repository.UserRepository
+	roles	services.UserService
+	books	services.BookService
+	borrows	services.BorrowService
+	copies	services.BookCopyService
+	holds	services.ReservationService
+	trash	services.BookTrashService
+	audit	services.AuditService
+}
+
+// newTestEnv - database baru untuk setiap test, dengan semua migration SQLite
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	db := openSQLite(t)
+	migrator, err := migrations.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(db)
+	bookRepo := repository.NewBookRepository(db)
+	borrowRepo := repository.NewBorrowRepository(db)
+	copyRepo := repository.NewBookCopyRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	fineRepo := repository.NewFineRepository(db)
+	authorRepo := repository.NewAuthorRepository(db)
+	publisherRepo := repository.NewPublisherRepository(db)
+	genreRepo := repository.NewGenreRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	txManager := database.NewTransactionManager(db)
+
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	eligibility := services.NewLimitPolicy(services.BorrowLimits{MaxActiveLoans: 5, BlockOnOverdue: true}, nil)
+
+	return &testEnv{
+		db:		db,
+		users:	userRepo,
+		roles:	services.NewUserService(userRepo, txManager),
+		books:	services.NewBookService(bookRepo, copyRepo, borrowRepo, reservationRepo, authorRepo, publisherRepo, genreRepo, auditRepo, txManager, nil),
+		borrows:	services.NewBorrowService(borrowRepo, bookRepo, copyRepo, reservationRepo, fineRepo, userRepo, auditRepo, txManager, eligibility, services.BorrowConfig{
+			LoanPeriod:		14 * 24 * time.Hour,
+			RenewalPeriod:	14 * 24 * time.Hour,
+			MaxRenewals:	2,
+			PickupWindow:	3 * 24 * time.Hour,
+			Fines:			services.FineConfig{DailyRate: 1000, MaxLateFee: 50000},
+		}, nil),
+		copies:	services.NewBookCopyService(copyRepo, bookRepo, reservationRepo, auditRepo, txManager, 3*24*time.Hour),
+		holds:	services.NewReservationService(reservationRepo, bookRepo, copyRepo, borrowRepo, auditRepo, txManager, 3*24*time.Hour),
+		trash:	services.NewBookTrashService(bookRepo, borrowRepo, auditRepo, txManager, store),
+		audit:	services.NewAuditService(auditRepo),
+	}
+}
+
+// openSQLite - koneksi lewat database.Open dengan DB_DRIVER=sqlite dan DB_PATH=:memory:
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Open(&config.Config{DBDriver: database.DriverSQLite, DBPath: database.SQLiteMemory}, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+// createMember - user baru dengan role member
+func (e *testEnv) createMember(t *testing.T, name string) *models.User {
+	t.Helper()
+
+	user := &models.User{Name: name, Email: fmt.Sprintf("%s@example.com", name), Password: "hash", Role: models.RoleMember}
+	require.NoError(t, e.users.Create(user))
+	return user
+}
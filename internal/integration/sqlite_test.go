@@ -0,0 +1,306 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"book-api/internal/database"
+	"book-api/internal/migrations"
+	"book-api/internal/models"
+	"book-api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Test migration SQLite - semua versi bisa di-rollback lalu diterapkan lagi
+func TestMigrations_DownAndUpAgain(t *testing.T) {
+	db := openSQLite(t)
+	migrator, err := migrations.NewMigrator(db)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	all, err := migrations.Load()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	for range applied {
+		_, err := migrator.Down()
+		require.NoError(t, err)
+	}
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	assert.Len(t, pending, len(all))
+
+	_, err = migrator.Up()
+	require.NoError(t, err)
+}
+
+// Test pinjam dan kembalikan - stock, status copy dan audit log lewat repository asli
+func TestBorrowAndReturn(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	member := env.createMember(t, "ana")
+
+	book, err := env.books.CreateBook(ctx, services.BookInput{Title: "Laskar Pelangi", Author: "Andrea Hirata", ISBN: "9789793062792", Stock: 2})
+	require.NoError(t, err)
+
+	borrow, err := env.borrows.BorrowBook(ctx, member.ID, book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BorrowStatusBorrowed, borrow.Status)
+
+	book, err = env.books.GetBookByID(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, book.Stock)
+
+	returned, err := env.borrows.ReturnBook(ctx, services.Actor{UserID: member.ID, Role: models.RoleMember}, borrow.Copy.Barcode)
+	require.NoError(t, err)
+	assert.Equal(t, models.BorrowStatusReturned, returned.Status)
+
+	book, err = env.books.GetBookByID(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, book.Stock)
+
+	history, total, err := env.audit.GetBookHistory(book.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, models.AuditActionReturn, history[0].Action)
+	assert.Equal(t, models.AuditChange{Old: float64(1), New: float64(2)}, history[0].Changes["stock"])
+}
+
+// Test tandai copy hilang - perubahan stock tercatat di riwayat buku beserta actor dan request
+func TestUpdateCopy_LostRecordsStockChange(t *testing.T) {
+	env := newTestEnv(t)
+	librarian := env.createMember(t, "librarian")
+
+	book, err := env.books.CreateBook(context.Background(), services.BookInput{Title: "Cantik Itu Luka", Author: "Eka Kurniawan", ISBN: "9789792207439", Stock: 2})
+	require.NoError(t, err)
+	copies, err := env.copies.GetCopies(book.ID)
+	require.NoError(t, err)
+
+	ctx := services.WithAuditInfo(context.Background(), services.AuditInfo{ActorID: &librarian.ID, RequestID: "req-lost"})
+	_, err = env.copies.UpdateCopy(ctx, copies[0].ID, nil, "", models.CopyStatusLost)
+	require.NoError(t, err)
+
+	history, total, err := env.audit.GetBookHistory(book.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, models.AuditActionUpdate, history[0].Action)
+	assert.Equal(t, models.AuditChange{Old: float64(2), New: float64(1)}, history[0].Changes["stock"])
+	require.NotNil(t, history[0].ActorID)
+	assert.Equal(t, librarian.ID, *history[0].ActorID)
+	assert.Equal(t, "req-lost", history[0].RequestID)
+}
+
+// Test hapus buku - hold yang masih aktif dibatalkan dan copy yang disisihkan kembali available
+func TestDeleteBook_CancelsOpenHolds(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	reader := env.createMember(t, "dewi")
+	first := env.createMember(t, "budi")
+	second := env.createMember(t, "sari")
+
+	book, err := env.books.CreateBook(ctx, services.BookInput{Title: "Bumi Manusia", Author: "Pramoedya Ananta Toer", ISBN: "9789799731234", Stock: 1})
+	require.NoError(t, err)
+	borrow, err := env.borrows.BorrowBook(ctx, reader.ID, book.ID)
+	require.NoError(t, err)
+
+	_, err = env.holds.PlaceHold(ctx, first.ID, book.ID)
+	require.NoError(t, err)
+	_, err = env.holds.PlaceHold(ctx, second.ID, book.ID)
+	require.NoError(t, err)
+
+	// Copy yang dikembalikan disisihkan untuk hold pertama
+	_, err = env.borrows.ReturnBook(ctx, services.Actor{UserID: reader.ID, Role: models.RoleMember}, borrow.Copy.Barcode)
+	require.NoError(t, err)
+
+	require.NoError(t, env.books.DeleteBook(ctx, book.ID))
+
+	var reservations []models.Reservation
+	require.NoError(t, env.db.Where("book_id = ?", book.ID).Order("id").Find(&reservations).Error)
+	require.Len(t, reservations, 2)
+	for _, reservation := range reservations {
+		assert.Equal(t, models.ReservationStatusCancelled, reservation.Status)
+	}
+
+	var bookCopy models.BookCopy
+	require.NoError(t, env.db.First(&bookCopy, *borrow.CopyID).Error)
+	assert.Equal(t, models.CopyStatusAvailable, bookCopy.Status)
+
+	var trashed models.Book
+	require.NoError(t, env.db.Unscoped().First(&trashed, book.ID).Error)
+	assert.Equal(t, 1, trashed.Stock)
+}
+
+// Test savepoint - import memakai savepoint per baris: unique violation diterjemahkan ke
+// gorm.ErrDuplicatedKey dan hanya baris itu yang dibatalkan
+func TestWithSavepoint_RollsBackOnlyFailedStep(t *testing.T) {
+	env := newTestEnv(t)
+	txManager := database.NewTransactionManager(env.db)
+
+	err := txManager.WithTransaction(context.Background(), func(tx *gorm.DB) error {
+		for i, email := range []string{"a@example.com", "a@example.com", "b@example.com"} {
+			err := txManager.WithSavepoint(tx, fmt.Sprintf("user_%d", i), func() error {
+				return tx.Create(&models.User{Name: "user", Email: email, Password: "hash", Role: models.RoleMember}).Error
+			})
+			if i == 1 {
+				assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+			} else {
+				require.NoError(t, err)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	count, err := env.users.Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+// Test pinjam paralel - tanpa row lock, BEGIN IMMEDIATE tetap membuat copy terakhir
+// hanya bisa dipinjam satu member
+func TestBorrowBook_ConcurrentLastCopy(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	book, err := env.books.CreateBook(ctx, services.BookInput{Title: "Bumi Manusia", Author: "Pramoedya Ananta Toer", ISBN: "9789799731234", Stock: 1})
+	require.NoError(t, err)
+
+	const members = 5
+	userIDs := make([]uint, members)
+	for i := range userIDs {
+		userIDs[i] = env.createMember(t, string(rune('a'+i))+"member").ID
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, members)
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func(i int, userID uint) {
+			defer wg.Done()
+			_, errs[i] = env.borrows.BorrowBook(ctx, userID, book.ID)
+		}(i, userID)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, services.ErrBookOutOfStock)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	book, err = env.books.GetBookByID(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, book.Stock)
+}
+
+// Test check constraint status borrow - tetap ditegakkan di SQLite
+func TestBorrowStatusCheckConstraint(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	member := env.createMember(t, "budi")
+
+	book, err := env.books.CreateBook(ctx, services.BookInput{Title: "Ronggeng Dukuh Paruk", Author: "Ahmad Tohari", ISBN: "9789792247817", Stock: 1})
+	require.NoError(t, err)
+
+	now := time.Now()
+	err = env.db.Create(&models.Borrow{UserID: member.ID, BookID: book.ID, BorrowDate: now, DueDate: now, Status: "lost"}).Error
+	assert.ErrorContains(t, err, "CHECK constraint failed")
+
+	err = env.db.Create(&models.Borrow{UserID: member.ID, BookID: book.ID + 100, BorrowDate: now, DueDate: now, Status: models.BorrowStatusBorrowed}).Error
+	assert.ErrorIs(t, err, gorm.ErrForeignKeyViolated)
+}
+
+// Test MarkOverdueBorrows - UPDATE ... RETURNING di SQLite
+func TestMarkOverdueBorrows(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	member := env.createMember(t, "citra")
+
+	book, err := env.books.CreateBook(ctx, services.BookInput{Title: "Cantik Itu Luka", Author: "Eka Kurniawan", ISBN: "9786020312583", Stock: 1})
+	require.NoError(t, err)
+	borrow, err := env.borrows.BorrowBook(ctx, member.ID, book.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, env.db.Model(&models.Borrow{}).Where("id = ?", borrow.ID).Update("due_date", time.Now().Add(-48*time.Hour)).Error)
+
+	count, err := env.borrows.MarkOverdueBorrows(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	overdue, total, err := env.borrows.GetOverdueBorrows(1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, borrow.ID, overdue[0].ID)
+}
+
+// Test pencarian dan trash - fallback LIKE dan ISBN yang boleh dipakai lagi setelah buku dihapus
+func TestSearchAndISBNReuseAfterDelete(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	book, err := env.books.CreateBook(ctx, services.BookInput{Title: "Negeri 5 Menara", Author: "Ahmad Fuadi", ISBN: "9789792248616", Stock: 1})
+	require.NoError(t, err)
+
+	found, total, err := env.books.GetAllBooks(models.BookFilter{Query: "menara fuadi"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, book.ID, found[0].ID)
+
+	require.NoError(t, env.books.DeleteBook(ctx, book.ID))
+
+	_, err = env.books.CreateBook(ctx, services.BookInput{Title: "Negeri 5 Menara (Edisi Baru)", Author: "Ahmad Fuadi", ISBN: "9789792248616", Stock: 1})
+	require.NoError(t, err)
+
+	_, err = env.trash.RestoreBook(ctx, book.ID)
+	assert.ErrorIs(t, err, services.ErrISBNExists)
+}
+
+// Test turunkan dua admin terakhir secara paralel - hitung dan update dalam satu
+// transaction, jadi salah satu harus ditolak dan tetap ada satu admin
+func TestUpdateUserRole_ConcurrentLastAdmins(t *testing.T) {
+	env := newTestEnv(t)
+
+	adminIDs := make([]uint, 2)
+	for i := range adminIDs {
+		admin := env.createMember(t, string(rune('a'+i))+"admin")
+		_, err := env.roles.UpdateUserRole(context.Background(), admin.ID, models.RoleAdmin)
+		require.NoError(t, err)
+		adminIDs[i] = admin.ID
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(adminIDs))
+	for i, adminID := range adminIDs {
+		wg.Add(1)
+		go func(i int, adminID uint) {
+			defer wg.Done()
+			_, errs[i] = env.roles.UpdateUserRole(context.Background(), adminID, models.RoleMember)
+		}(i, adminID)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, services.ErrLastAdmin)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	admins, err := env.users.CountByRole(models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), admins)
+}
@@ -0,0 +1,135 @@
+// Package isbn - validasi, normalisasi dan konversi ISBN-10 / ISBN-13.
+// Bentuk kanonik yang disimpan di database adalah ISBN-13 tanpa pemisah.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength	= errors.New("ISBN must have 10 or 13 digits")
+	ErrInvalidCharacter	= errors.New("ISBN may only contain digits, hyphens and spaces (X only as the last ISBN-10 digit)")
+	ErrInvalidChecksum	= errors.New("ISBN check digit is wrong")
+	ErrNoISBN10			= errors.New("only 978 ISBN-13s have an ISBN-10 form")
+)
+
+// Normalize - hapus tanda hubung dan spasi, huruf x menjadi X. Tidak memvalidasi.
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '-' || r == ' ' || r == '\t':
+			continue
+		case r == 'x':
+			b.WriteRune('X')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Validate - cek panjang, karakter dan check digit setelah Normalize
+func Validate(s string) error {
+	_, err := parse(Normalize(s))
+	return err
+}
+
+// Valid - true jika s adalah ISBN-10 atau ISBN-13 yang valid
+func Valid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Canonical - ISBN-13 tanpa pemisah. ISBN-10 dikonversi dengan prefix 978.
+func Canonical(s string) (string, error) {
+	digits, err := parse(Normalize(s))
+	if err != nil {
+		return "", err
+	}
+	if len(digits) == 13 {
+		return digits, nil
+	}
+	return toISBN13(digits), nil
+}
+
+// ToISBN10 - bentuk ISBN-10 dari ISBN yang valid. ISBN-13 dengan prefix 979 tidak punya ISBN-10.
+func ToISBN10(s string) (string, error) {
+	digits, err := parse(Normalize(s))
+	if err != nil {
+		return "", err
+	}
+	if len(digits) == 10 {
+		return digits, nil
+	}
+	if !strings.HasPrefix(digits, "978") {
+		return "", ErrNoISBN10
+	}
+
+	body := digits[3:12]
+	return body + isbn10CheckDigit(body), nil
+}
+
+// parse - validasi ISBN yang sudah dinormalisasi
+func parse(s string) (string, error) {
+	switch len(s) {
+	case 10:
+		for i, r := range s {
+			if (r < '0' || r > '9') && !(r == 'X' && i == 9) {
+				return "", ErrInvalidCharacter
+			}
+		}
+		if isbn10CheckDigit(s[:9]) != s[9:] {
+			return "", ErrInvalidChecksum
+		}
+	case 13:
+		for _, r := range s {
+			if r < '0' || r > '9' {
+				return "", ErrInvalidCharacter
+			}
+		}
+		if isbn13CheckDigit(s[:12]) != s[12:] {
+			return "", ErrInvalidChecksum
+		}
+	default:
+		for _, r := range s {
+			if (r < '0' || r > '9') && r != 'X' {
+				return "", ErrInvalidCharacter
+			}
+		}
+		return "", ErrInvalidLength
+	}
+	return s, nil
+}
+
+func toISBN13(isbn10 string) string {
+	body := "978" + isbn10[:9]
+	return body + isbn13CheckDigit(body)
+}
+
+// isbn10CheckDigit - bobot 10..2, modulo 11, sisa 10 ditulis X
+func isbn10CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return "X"
+	}
+	return string(rune('0' + check))
+}
+
+// isbn13CheckDigit - bobot bergantian 1 dan 3, modulo 10
+func isbn13CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(body[i]-'0') * weight
+	}
+	return string(rune('0' + (10-sum%10)%10))
+}
@@ -0,0 +1,54 @@
+package isbn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCanonical - ISBN-10 dan ISBN-13, dengan atau tanpa pemisah, menjadi ISBN-13 yang sama
+func TestCanonical(t *testing.T) {
+	for _, input := range []string{
+		"9780132350884",
+		"978-0-13-235088-4",
+		"978 0 13 235088 4",
+		"0132350882",
+		"0-13-235088-2",
+	} {
+		got, err := Canonical(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, "9780132350884", got, input)
+	}
+
+	// Check digit X
+	got, err := Canonical("0-8044-2957-x")
+	require.NoError(t, err)
+	assert.Equal(t, "9780804429573", got)
+}
+
+// TestValidate - panjang, karakter dan checksum yang salah
+func TestValidate(t *testing.T) {
+	assert.ErrorIs(t, Validate("9780132350885"), ErrInvalidChecksum)
+	assert.ErrorIs(t, Validate("0132350881"), ErrInvalidChecksum)
+	assert.ErrorIs(t, Validate("978013235088"), ErrInvalidLength)
+	assert.ErrorIs(t, Validate("123"), ErrInvalidLength)
+	assert.ErrorIs(t, Validate("97801323508A4"), ErrInvalidCharacter)
+	assert.ErrorIs(t, Validate("080442X957"), ErrInvalidCharacter)
+	assert.ErrorIs(t, Validate("978013235088X"), ErrInvalidCharacter)
+	assert.True(t, Valid("979-10-90636-07-1"))
+}
+
+// TestToISBN10 - hanya ISBN-13 dengan prefix 978 yang punya bentuk ISBN-10
+func TestToISBN10(t *testing.T) {
+	got, err := ToISBN10("978-0-8044-2957-3")
+	require.NoError(t, err)
+	assert.Equal(t, "080442957X", got)
+
+	got, err = ToISBN10("0-13-235088-2")
+	require.NoError(t, err)
+	assert.Equal(t, "0132350882", got)
+
+	_, err = ToISBN10("9791090636071")
+	assert.ErrorIs(t, err, ErrNoISBN10)
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"book-api/internal/logging"
+)
+
+// PickupExpirer - bagian dari ReservationService yang dibutuhkan job hold expiry
+type PickupExpirer interface {
+	ExpirePickups(ctx context.Context) (int64, error)
+}
+
+// NewHoldExpiryJob - job yang meng-expire hold yang tidak diambil sampai pickup deadline
+// dan meneruskan eksemplarnya ke antrian berikutnya
+func NewHoldExpiryJob(expirer PickupExpirer, interval time.Duration) Job {
+	return Job{
+		Name:     "expire-hold-pickups",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			count, err := expirer.ExpirePickups(ctx)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				logging.FromContext(ctx).Info("expired uncollected holds", "count", count)
+			}
+			return nil
+		},
+	}
+}
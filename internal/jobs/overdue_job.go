@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"book-api/internal/logging"
+)
+
+// OverdueMarker - bagian dari BorrowService yang dibutuhkan job overdue
+type OverdueMarker interface {
+	MarkOverdueBorrows(ctx context.Context) (int64, error)
+}
+
+// NewOverdueJob - job yang menandai peminjaman lewat jatuh tempo sebagai overdue
+func NewOverdueJob(marker OverdueMarker, interval time.Duration) Job {
+	return Job{
+		Name:     "mark-overdue-borrows",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			count, err := marker.MarkOverdueBorrows(ctx)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				logging.FromContext(ctx).Info("marked borrows as overdue", "count", count)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"book-api/internal/logging"
+)
+
+// Job - tugas background yang dijalankan berkala oleh Scheduler
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler - menjalankan setiap Job di goroutine sendiri sampai Stop dipanggil
+type Scheduler struct {
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler - Interval setiap job harus positif. Durasi dari config yang kosong
+// atau tidak valid terbaca 0, dan time.NewTicker panic untuk interval <= 0.
+func NewScheduler(jobs ...Job) (*Scheduler, error) {
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			return nil, fmt.Errorf("job %s: interval must be positive, got %s", job.Name, job.Interval)
+		}
+	}
+	return &Scheduler{jobs: jobs}, nil
+}
+
+// Start - jalankan semua job. Setiap job langsung dieksekusi sekali,
+// lalu diulang setiap Interval.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Stop - hentikan semua job dan tunggu job yang sedang berjalan selesai,
+// atau sampai ctx habis.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop - ctx job membawa logger dengan nama job (logging.FromContext)
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	logger := slog.Default().With(slog.String("job", job.Name))
+	ctx = logging.WithLogger(ctx, logger)
+
+	for {
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("job failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
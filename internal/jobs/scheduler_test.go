@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test Scheduler - job dijalankan langsung dan berhenti saat Stop
+func TestScheduler_RunsJobUntilStopped(t *testing.T) {
+	var runs int32
+	scheduler, err := NewScheduler(Job{
+		Name:     "counter",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+
+	scheduler.Start(context.Background())
+	time.Sleep(35 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, scheduler.Stop(ctx))
+
+	stopped := atomic.LoadInt32(&runs)
+	assert.GreaterOrEqual(t, stopped, int32(2))
+
+	// Tidak ada eksekusi lagi setelah Stop
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&runs))
+}
+
+// Test NewScheduler - interval 0 (env kosong atau tidak valid) ditolak, bukan panic di goroutine
+func TestNewScheduler_ZeroInterval(t *testing.T) {
+	scheduler, err := NewScheduler(Job{
+		Name:     "zero",
+		Interval: 0,
+		Run:      func(ctx context.Context) error { return nil },
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, scheduler)
+}
@@ -0,0 +1,84 @@
+// Package logging - setup log/slog dari config dan logger per request yang dibawa
+// lewat context.Context, supaya log dari handler, service dan job bisa dicari
+// berdasarkan request ID dan user.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Format output log (LOG_FORMAT)
+const (
+	FormatJSON	= "json"
+	FormatText	= "text"
+)
+
+// New - logger dengan format dan level dari config, misalnya ("json", "debug")
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown LOG_LEVEL %q, use debug, info, warn or error", level)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown LOG_FORMAT %q, use %s or %s", format, FormatJSON, FormatText)
+	}
+}
+
+type contextKey struct{}
+
+// requestLog - logger milik satu request. Disimpan sebagai pointer supaya middleware
+// yang berjalan belakangan (AuthMiddleware) bisa menambahkan user ID dan middleware
+// log request tetap melihatnya saat menulis baris akhir.
+type requestLog struct {
+	logger	*slog.Logger
+	userID	uint
+}
+
+// WithLogger - context yang membawa logger untuk request ini
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestLog{logger: logger})
+}
+
+// FromContext - logger request dari ctx, atau slog.Default() di luar request
+// (job background, CLI)
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := Lookup(ctx); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// Lookup - logger request dari ctx, false jika ctx bukan dari request HTTP
+func Lookup(ctx context.Context) (*slog.Logger, bool) {
+	if entry, ok := ctx.Value(contextKey{}).(*requestLog); ok {
+		return entry.logger, true
+	}
+	return nil, false
+}
+
+// SetUser - tambahkan user_id ke logger request setelah token divalidasi
+func SetUser(ctx context.Context, userID uint) {
+	if entry, ok := ctx.Value(contextKey{}).(*requestLog); ok {
+		entry.userID = userID
+		entry.logger = entry.logger.With(slog.Uint64("user_id", uint64(userID)))
+	}
+}
+
+// UserID - user yang tercatat lewat SetUser, 0 untuk request tanpa login
+func UserID(ctx context.Context) uint {
+	if entry, ok := ctx.Value(contextKey{}).(*requestLog); ok {
+		return entry.userID
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNew - format JSON dan level dari config, nilai yang tidak dikenal ditolak
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "warn")
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("slow query", "elapsed_ms", 250)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "slow query", entry["msg"])
+	assert.Equal(t, float64(250), entry["elapsed_ms"])
+
+	_, err = New(&buf, "xml", "info")
+	assert.Error(t, err)
+	_, err = New(&buf, "text", "verbose")
+	assert.Error(t, err)
+}
+
+// TestFromContext - logger request beserta user_id dari SetUser, slog.Default di luar request
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	ctx := WithLogger(context.Background(), logger.With("request_id", "host/abc-000001"))
+	SetUser(ctx, 7)
+	FromContext(ctx).Info("book created")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "host/abc-000001", entry["request_id"])
+	assert.Equal(t, float64(7), entry["user_id"])
+	assert.Equal(t, uint(7), UserID(ctx))
+}
@@ -0,0 +1,98 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize - jumlah ISBN maksimal yang disimpan oleh cache
+const DefaultCacheSize = 1000
+
+type cacheEntry struct {
+	book	*BookMetadata	// nil = ISBN tidak dikenal provider
+	expires	time.Time
+}
+
+type cachedProvider struct {
+	next		Provider
+	ttl			time.Duration
+	maxEntries	int
+	now			func() time.Time
+
+	mu		sync.Mutex
+	entries	map[string]cacheEntry
+}
+
+// NewCache - simpan hasil lookup selama ttl, termasuk ErrNotFound. Error provider
+// (timeout, 5xx) tidak disimpan supaya request berikutnya mencoba lagi.
+func NewCache(next Provider, ttl time.Duration, maxEntries int) Provider {
+	if maxEntries < 1 {
+		maxEntries = DefaultCacheSize
+	}
+	return &cachedProvider{
+		next:		next,
+		ttl:		ttl,
+		maxEntries:	maxEntries,
+		now:		time.Now,
+		entries:	map[string]cacheEntry{},
+	}
+}
+
+func (c *cachedProvider) Lookup(ctx context.Context, isbn string) (*BookMetadata, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[isbn]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		if entry.book == nil {
+			return nil, ErrNotFound
+		}
+		return entry.book.clone(), nil
+	}
+
+	book, err := c.next.Lookup(ctx, isbn)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	entry = cacheEntry{expires: c.now().Add(c.ttl)}
+	if book != nil {
+		entry.book = book.clone()
+	}
+	c.store(isbn, entry)
+
+	if book == nil {
+		return nil, ErrNotFound
+	}
+	return book, nil
+}
+
+// store - jika cache penuh, buang entry yang sudah expired lalu yang paling cepat expired
+func (c *cachedProvider) store(isbn string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[isbn]; !ok && len(c.entries) >= c.maxEntries {
+		now := c.now()
+		for key, existing := range c.entries {
+			if !now.Before(existing.expires) {
+				delete(c.entries, key)
+			}
+		}
+
+		if len(c.entries) >= c.maxEntries {
+			var oldestKey string
+			var oldest time.Time
+			for key, existing := range c.entries {
+				if oldestKey == "" || existing.expires.Before(oldest) {
+					oldestKey, oldest = key, existing.expires
+				}
+			}
+			delete(c.entries, oldestKey)
+		}
+	}
+
+	c.entries[isbn] = entry
+}
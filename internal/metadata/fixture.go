@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type staticProvider struct {
+	books map[string]BookMetadata
+}
+
+// NewStaticProvider - provider dari data di memory, key adalah ISBN-13.
+// Dipakai di test dan untuk development tanpa akses internet.
+func NewStaticProvider(books map[string]BookMetadata) Provider {
+	return &staticProvider{books: books}
+}
+
+// LoadFixture - NewStaticProvider dari file JSON berisi object {"<isbn13>": BookMetadata}
+func LoadFixture(path string) (Provider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var books map[string]BookMetadata
+	if err := json.Unmarshal(content, &books); err != nil {
+		return nil, fmt.Errorf("invalid metadata fixture %s: %w", path, err)
+	}
+	for isbn, book := range books {
+		book.ISBN = isbn
+		if book.Source == "" {
+			book.Source = "fixture"
+		}
+		books[isbn] = book
+	}
+	return NewStaticProvider(books), nil
+}
+
+func (p *staticProvider) Lookup(ctx context.Context, isbn string) (*BookMetadata, error) {
+	book, ok := p.books[isbn]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return book.clone(), nil
+}
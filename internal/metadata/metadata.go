@@ -0,0 +1,47 @@
+// Package metadata - data bibliografis buku dari sumber luar (Open Library, fixture lokal)
+// untuk mengisi form buku berdasarkan ISBN.
+package metadata
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNotFound		= errors.New("no metadata found for this ISBN")
+	ErrUnavailable	= errors.New("metadata provider unavailable")
+)
+
+// BookMetadata - data buku dari provider. Field yang tidak diketahui dibiarkan kosong.
+type BookMetadata struct {
+	ISBN		string		`json:"isbn"`
+	Title		string		`json:"title"`
+	Subtitle	string		`json:"subtitle,omitempty"`
+	Authors		[]string	`json:"authors"`
+	Description	string		`json:"description,omitempty"`
+	Publishers	[]string	`json:"publishers,omitempty"`
+	PublishDate	string		`json:"publish_date,omitempty"`
+	PageCount	int			`json:"page_count,omitempty"`
+	CoverURL	string		`json:"cover_url,omitempty"`
+	Source		string		`json:"source"`
+}
+
+// Provider - sumber metadata. isbn selalu ISBN-13 kanonik. Return ErrNotFound jika
+// ISBN tidak dikenal dan error yang membungkus ErrUnavailable jika provider gagal/timeout.
+type Provider interface {
+	Lookup(ctx context.Context, isbn string) (*BookMetadata, error)
+}
+
+// ProviderFunc - adapter supaya fungsi biasa bisa dipakai sebagai Provider
+type ProviderFunc func(ctx context.Context, isbn string) (*BookMetadata, error)
+
+func (f ProviderFunc) Lookup(ctx context.Context, isbn string) (*BookMetadata, error) {
+	return f(ctx, isbn)
+}
+
+func (m *BookMetadata) clone() *BookMetadata {
+	c := *m
+	c.Authors = append([]string(nil), m.Authors...)
+	c.Publishers = append([]string(nil), m.Publishers...)
+	return &c
+}
@@ -0,0 +1,156 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const openLibraryResponse = `{"ISBN:9780132350884": {"bib_key": "ISBN:9780132350884", "details": {
+	"title": "Clean Code",
+	"subtitle": "A Handbook of Agile Software Craftsmanship",
+	"authors": [{"key": "/authors/OL216228A", "name": "Robert C. Martin"}],
+	"description": {"type": "/type/text", "value": "Even bad code can function."},
+	"publishers": ["Prentice Hall"],
+	"publish_date": "2008",
+	"number_of_pages": 431,
+	"covers": [8065615]
+}}}`
+
+// TestOpenLibrary - response jscmd=details dipetakan ke BookMetadata
+func TestOpenLibrary_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/books", r.URL.Path)
+		assert.Equal(t, "details", r.URL.Query().Get("jscmd"))
+		if r.URL.Query().Get("bibkeys") != "ISBN:9780132350884" {
+			w.Write([]byte("{}"))
+			return
+		}
+		w.Write([]byte(openLibraryResponse))
+	}))
+	defer server.Close()
+
+	provider := NewOpenLibrary(server.URL, time.Second)
+
+	book, err := provider.Lookup(context.Background(), "9780132350884")
+	require.NoError(t, err)
+	assert.Equal(t, "Clean Code", book.Title)
+	assert.Equal(t, []string{"Robert C. Martin"}, book.Authors)
+	assert.Equal(t, "Even bad code can function.", book.Description)
+	assert.Equal(t, 431, book.PageCount)
+	assert.Equal(t, "https://covers.openlibrary.org/b/id/8065615-L.jpg", book.CoverURL)
+
+	_, err = provider.Lookup(context.Background(), "9780201485677")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestOpenLibrary - timeout dan error server menjadi ErrUnavailable
+func TestOpenLibrary_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bibkeys") == "ISBN:9780132350884" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	provider := NewOpenLibrary(server.URL, 50*time.Millisecond)
+
+	_, err := provider.Lookup(context.Background(), "9780132350884")
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	_, err = provider.Lookup(context.Background(), "9780201485677")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Contains(t, err.Error(), "502")
+}
+
+// TestLoadFixture - file fixture di testdata
+func TestLoadFixture(t *testing.T) {
+	provider, err := LoadFixture("testdata/books.json")
+	require.NoError(t, err)
+
+	book, err := provider.Lookup(context.Background(), "9780201485677")
+	require.NoError(t, err)
+	assert.Equal(t, "Refactoring", book.Title)
+	assert.Equal(t, "9780201485677", book.ISBN)
+	assert.Equal(t, "fixture", book.Source)
+
+	_, err = provider.Lookup(context.Background(), "9791090636071")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestCache - hasil dan ErrNotFound disimpan sampai expired, error provider tidak
+func TestCache(t *testing.T) {
+	calls := map[string]int{}
+	failing := true
+	provider := ProviderFunc(func(ctx context.Context, isbn string) (*BookMetadata, error) {
+		calls[isbn]++
+		switch isbn {
+		case "9780132350884":
+			return &BookMetadata{ISBN: isbn, Title: "Clean Code"}, nil
+		case "9780201485677":
+			if failing {
+				return nil, fmt.Errorf("%w: timeout", ErrUnavailable)
+			}
+			return &BookMetadata{ISBN: isbn, Title: "Refactoring"}, nil
+		}
+		return nil, ErrNotFound
+	})
+
+	now := time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)
+	cache := NewCache(provider, time.Hour, 10).(*cachedProvider)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		book, err := cache.Lookup(ctx, "9780132350884")
+		require.NoError(t, err)
+		assert.Equal(t, "Clean Code", book.Title)
+
+		_, err = cache.Lookup(ctx, "9791090636071")
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+	assert.Equal(t, 1, calls["9780132350884"])
+	assert.Equal(t, 1, calls["9791090636071"])
+
+	// Error provider tidak disimpan
+	_, err := cache.Lookup(ctx, "9780201485677")
+	assert.Error(t, err)
+	failing = false
+	book, err := cache.Lookup(ctx, "9780201485677")
+	require.NoError(t, err)
+	assert.Equal(t, "Refactoring", book.Title)
+	assert.Equal(t, 2, calls["9780201485677"])
+
+	// Setelah ttl lewat, provider dipanggil lagi
+	now = now.Add(2 * time.Hour)
+	_, err = cache.Lookup(ctx, "9780132350884")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls["9780132350884"])
+}
+
+// TestCache - cache penuh membuang entry yang paling cepat expired
+func TestCache_Eviction(t *testing.T) {
+	provider := ProviderFunc(func(ctx context.Context, isbn string) (*BookMetadata, error) {
+		return &BookMetadata{ISBN: isbn}, nil
+	})
+
+	now := time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)
+	cache := NewCache(provider, time.Hour, 2).(*cachedProvider)
+	cache.now = func() time.Time { return now }
+
+	for _, isbn := range []string{"a", "b", "c"} {
+		_, err := cache.Lookup(context.Background(), isbn)
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+	}
+
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "a")
+}
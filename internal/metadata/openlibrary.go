@@ -0,0 +1,123 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultOpenLibraryURL - base URL Open Library publik
+const DefaultOpenLibraryURL = "https://openlibrary.org"
+
+// maxResponseBytes - batas ukuran response yang dibaca dari provider
+const maxResponseBytes = 1 << 20
+
+type openLibrary struct {
+	baseURL	string
+	client	*http.Client
+}
+
+// NewOpenLibrary - provider yang memakai Books API Open Library (/api/books?jscmd=details).
+// baseURL bisa diarahkan ke mirror atau server lain dengan API yang sama.
+func NewOpenLibrary(baseURL string, timeout time.Duration) Provider {
+	return &openLibrary{
+		baseURL:	strings.TrimRight(baseURL, "/"),
+		client:		&http.Client{Timeout: timeout},
+	}
+}
+
+type openLibraryAuthor struct {
+	Name	string	`json:"name"`
+}
+
+type openLibraryEntry struct {
+	Details struct {
+		Title			string				`json:"title"`
+		Subtitle		string				`json:"subtitle"`
+		Authors			[]openLibraryAuthor	`json:"authors"`
+		Description		openLibraryText		`json:"description"`
+		Publishers		[]string			`json:"publishers"`
+		PublishDate		string				`json:"publish_date"`
+		NumberOfPages	int					`json:"number_of_pages"`
+		Covers			[]int64				`json:"covers"`
+	} `json:"details"`
+}
+
+// openLibraryText - Open Library menulis teks sebagai string atau {"type": ..., "value": ...}
+type openLibraryText string
+
+func (t *openLibraryText) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = openLibraryText(s)
+		return nil
+	}
+
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	*t = openLibraryText(typed.Value)
+	return nil
+}
+
+func (p *openLibrary) Lookup(ctx context.Context, isbn string) (*BookMetadata, error) {
+	bibKey := "ISBN:" + isbn
+	query := url.Values{"bibkeys": {bibKey}, "format": {"json"}, "jscmd": {"details"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/books?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: open library returned %s", ErrUnavailable, resp.Status)
+	}
+
+	var entries map[string]openLibraryEntry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("%w: invalid open library response: %v", ErrUnavailable, err)
+	}
+
+	entry, ok := entries[bibKey]
+	if !ok || entry.Details.Title == "" {
+		return nil, ErrNotFound
+	}
+
+	details := entry.Details
+	book := &BookMetadata{
+		ISBN:			isbn,
+		Title:			strings.TrimSpace(details.Title),
+		Subtitle:		strings.TrimSpace(details.Subtitle),
+		Description:	strings.TrimSpace(string(details.Description)),
+		Publishers:		details.Publishers,
+		PublishDate:	details.PublishDate,
+		PageCount:		details.NumberOfPages,
+		Source:			"openlibrary",
+	}
+	for _, author := range details.Authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			book.Authors = append(book.Authors, name)
+		}
+	}
+	// id -1 berarti cover tidak ada
+	if len(details.Covers) > 0 && details.Covers[0] > 0 {
+		book.CoverURL = fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-L.jpg", details.Covers[0])
+	}
+
+	return book, nil
+}
@@ -0,0 +1,112 @@
+// Package metrics - metric Prometheus untuk endpoint /metrics: request HTTP per route,
+// statistik connection pool database dan counter peminjaman dari BorrowService.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bookapi"
+
+// Metrics - registry sendiri (bukan prometheus.DefaultRegisterer) supaya test bisa
+// membuat instance baru tanpa bentrok nama metric
+type Metrics struct {
+	registry		*prometheus.Registry
+	httpRequests	*prometheus.CounterVec
+	httpDuration	*prometheus.HistogramVec
+	borrowed		prometheus.Counter
+	returned		prometheus.Counter
+	overdue			prometheus.Counter
+	borrowFailures	*prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:	namespace,
+			Name:		"http_requests_total",
+			Help:		"HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:	namespace,
+			Name:		"http_request_duration_seconds",
+			Help:		"HTTP request latency by method, chi route pattern and status code.",
+			Buckets:	prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		borrowed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:	namespace,
+			Name:		"books_borrowed_total",
+			Help:		"Books borrowed successfully.",
+		}),
+		returned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:	namespace,
+			Name:		"books_returned_total",
+			Help:		"Books returned.",
+		}),
+		overdue: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:	namespace,
+			Name:		"borrows_overdue_total",
+			Help:		"Loans marked overdue by the background job.",
+		}),
+		borrowFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:	namespace,
+			Name:		"borrow_failures_total",
+			Help:		"Refused or failed borrow attempts by error code.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.borrowed,
+		m.returned,
+		m.overdue,
+		m.borrowFailures,
+	)
+	return m
+}
+
+// RegisterDB - statistik sql.DB.Stats() (go_sql_*) dengan label db_name
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler - endpoint /metrics dalam format Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest - dipanggil middleware RequestMetrics setelah setiap request
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// BookBorrowed, BookReturned, BorrowsOverdue dan BorrowFailed - services.BorrowMetrics
+
+func (m *Metrics) BookBorrowed() {
+	m.borrowed.Inc()
+}
+
+func (m *Metrics) BookReturned() {
+	m.returned.Inc()
+}
+
+func (m *Metrics) BorrowsOverdue(count int64) {
+	m.overdue.Add(float64(count))
+}
+
+func (m *Metrics) BorrowFailed(reason string) {
+	m.borrowFailures.WithLabelValues(reason).Inc()
+}
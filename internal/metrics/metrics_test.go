@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestObserveRequest - request dihitung per method, route dan status
+func TestObserveRequest(t *testing.T) {
+	m := New()
+	m.ObserveRequest(http.MethodGet, "/api/v1/books/{id}", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/books/{id}", http.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/books/{id}", http.StatusNotFound, time.Millisecond)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/api/v1/books/{id}", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/api/v1/books/{id}", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+}
+
+// TestBorrowCounters - counter peminjaman dan alasan gagal
+func TestBorrowCounters(t *testing.T) {
+	m := New()
+	m.BookBorrowed()
+	m.BookReturned()
+	m.BorrowsOverdue(3)
+	m.BorrowFailed("book_out_of_stock")
+	m.BorrowFailed("book_out_of_stock")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.borrowed))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.returned))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.overdue))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.borrowFailures.WithLabelValues("book_out_of_stock")))
+}
+
+// TestHandler - format Prometheus berisi metric aplikasi dan runtime Go
+func TestHandler(t *testing.T) {
+	m := New()
+	m.BookBorrowed()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "bookapi_books_borrowed_total 1")
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"book-api/internal/logging"
 	"book-api/internal/utils"
 	"context"
 	"net/http"
@@ -11,8 +12,14 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// TokenChecker - cek apakah access token (berdasarkan jti) belum dicabut. Error berarti
+// status token tidak bisa dicek (misalnya database tidak tersedia).
+type TokenChecker interface {
+	IsTokenActive(jti string) (bool, error)
+}
+
 // AuthMiddleware - middleware untuk validasi JWT
-func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
+func AuthMiddleware(jwtSecret string, tokenChecker TokenChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Ambil token dari header Authorization
@@ -35,9 +42,23 @@ func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 			claims, err := utils.ValidateToken(token, jwtSecret)
 			if err != nil {
 				utils.ErrorResponse(w, http.StatusUnauthorized, "Invalid or expired token")
+				return
+			}
+
+			// Tolak token yang sudah logout atau sudah dirotasi
+			active, err := tokenChecker.IsTokenActive(claims.ID)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("failed to check token revocation", "error", err)
+				utils.ErrorResponseWithCode(w, http.StatusServiceUnavailable, "session_check_unavailable", "Unable to verify session, try again later")
+				return
+			}
+			if !active {
+				utils.ErrorResponse(w, http.StatusUnauthorized, "Token has been revoked")
+				return
 			}
 
-			// Simpan user info ke context
+			// Simpan user info ke context, user ID juga masuk ke log request
+			logging.SetUser(r.Context(), claims.UserID)
 			ctx := context.WithValue(r.Context(), UserContextKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"book-api/internal/utils"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+// stubTokenChecker - hasil IsTokenActive yang tetap
+type stubTokenChecker struct {
+	active bool
+	err    error
+}
+
+func (s stubTokenChecker) IsTokenActive(jti string) (bool, error) {
+	return s.active, s.err
+}
+
+func serveAuth(t *testing.T, checker TokenChecker) *httptest.ResponseRecorder {
+	t.Helper()
+
+	token, err := utils.GenerateToken(1, "member@example.com", "member", "jti-1", testSecret, time.Minute)
+	require.NoError(t, err)
+
+	handler := AuthMiddleware(testSecret, checker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// Test AuthMiddleware - token aktif diteruskan ke handler
+func TestAuthMiddleware_ActiveToken(t *testing.T) {
+	rec := serveAuth(t, stubTokenChecker{active: true})
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+// Test AuthMiddleware - token yang sudah dicabut ditolak
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	rec := serveAuth(t, stubTokenChecker{active: false})
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+// Test AuthMiddleware - database bermasalah bukan berarti token dicabut
+func TestAuthMiddleware_CheckFailed(t *testing.T) {
+	rec := serveAuth(t, stubTokenChecker{err: errors.New("connection refused")})
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"session_check_unavailable"`)
+}
@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"book-api/internal/utils"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ErrorFormat - pilih format response error per request. Client yang mengirim
+// Accept: application/problem+json selalu mendapat problem+json; selain itu
+// mengikuti ERROR_FORMAT. Harus dipasang setelah middleware.RequestID karena
+// request ID dipakai sebagai instance.
+func ErrorFormat(defaultFormat utils.ErrorFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if utils.NegotiateErrorFormat(r.Header.Get("Accept"), defaultFormat) == utils.ErrorFormatProblem {
+				w = utils.WithProblemErrors(w, middleware.GetReqID(r.Context()))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
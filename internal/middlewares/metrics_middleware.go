@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestObserver - penerima metric request HTTP (metrics.Metrics)
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// unmatchedRoute - label route untuk request yang tidak cocok dengan route mana pun,
+// supaya path acak (scanner, typo) tidak membuat label baru
+const unmatchedRoute = "unmatched"
+
+// RequestMetrics - hitung request dan latency per route pattern chi dan status
+func RequestMetrics(observer RequestObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			route := unmatchedRoute
+			if routeCtx := chi.RouteContext(r.Context()); routeCtx != nil && routeCtx.RoutePattern() != "" {
+				route = routeCtx.RoutePattern()
+			}
+			observer.ObserveRequest(r.Method, route, status, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"book-api/internal/logging"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestLogger - pengganti middleware.Logger chi. Memasang logger dengan request ID
+// ke context (logging.FromContext) lalu menulis satu baris per request berisi route,
+// status, latency dan user. Harus dipasang setelah middleware.RequestID.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestLogger := logger.With(slog.String("request_id", middleware.GetReqID(r.Context())))
+			ctx := logging.WithLogger(r.Context(), requestLogger)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_ip", r.RemoteAddr),
+			}
+			// Route pattern baru terisi setelah router chi mencocokkan request
+			if routeCtx := chi.RouteContext(ctx); routeCtx != nil {
+				attrs = append(attrs, slog.String("route", routeCtx.RoutePattern()))
+			}
+			if userID := logging.UserID(ctx); userID != 0 {
+				attrs = append(attrs, slog.Uint64("user_id", uint64(userID)))
+			}
+			requestLogger.LogAttrs(ctx, level, "request completed", attrs...)
+		})
+	}
+}
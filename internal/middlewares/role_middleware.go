@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/utils"
+	"net/http"
+)
+
+// RequireRole - middleware untuk membatasi akses hanya ke role tertentu.
+// Harus dipasang setelah AuthMiddleware.
+func RequireRole(roles ...models.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetUserFromContext(r)
+			if claims == nil {
+				utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			for _, role := range roles {
+				if models.Role(claims.Role) == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			utils.ErrorResponse(w, http.StatusForbidden, "Forbidden")
+		})
+	}
+}
+
+// RequirePermission - middleware untuk membatasi akses berdasarkan permission role.
+// Harus dipasang setelah AuthMiddleware.
+func RequirePermission(permission models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetUserFromContext(r)
+			if claims == nil {
+				utils.ErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			if !models.Role(claims.Role).HasPermission(permission) {
+				utils.ErrorResponse(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoad - embedded migrations are complete and ordered
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "initial_schema", migrations[0].Name)
+	for i := 1; i < len(migrations); i++ {
+		assert.Greater(t, migrations[i].Version, migrations[i-1].Version)
+	}
+}
+
+// TestLoadDialect - every PostgreSQL migration has a SQLite counterpart with the same version and name
+func TestLoadDialect_SQLiteMatchesPostgres(t *testing.T) {
+	postgres, err := LoadDialect("postgres")
+	require.NoError(t, err)
+	sqlite, err := LoadDialect("sqlite")
+	require.NoError(t, err)
+
+	require.Len(t, sqlite, len(postgres))
+	for i := range postgres {
+		assert.Equal(t, postgres[i].Version, sqlite[i].Version)
+		assert.Equal(t, postgres[i].Name, sqlite[i].Name)
+	}
+
+	_, err = LoadDialect("mysql")
+	assert.Error(t, err)
+}
+
+// TestLoad - missing down file
+func TestLoad_MissingDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_init.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+	}
+
+	_, err := load(fsys, "sql")
+
+	assert.ErrorContains(t, err, "needs both up and down")
+}
+
+// TestLoad - invalid file name
+func TestLoad_InvalidFileName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/init.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+	}
+
+	_, err := load(fsys, "sql")
+
+	assert.ErrorContains(t, err, "invalid migration file name")
+}
+
+// TestCreate - next version number in the directory
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_init.up.sql"), []byte("SELECT 1;"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_init.down.sql"), []byte("SELECT 1;"), 0o644))
+
+	upPath, downPath, err := Create(dir, "Add book copies")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0002_add_book_copies.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "0002_add_book_copies.down.sql"), downPath)
+	assert.FileExists(t, upPath)
+	assert.FileExists(t, downPath)
+
+	_, _, err = Create(dir, "drop; table")
+	assert.Error(t, err)
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS fines;
+DROP TABLE IF EXISTS reservations;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS borrows;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,104 @@
+-- Skema awal, setara dengan hasil AutoMigrate sebelumnya.
+-- Memakai IF NOT EXISTS supaya database lama yang dibuat oleh AutoMigrate
+-- bisa langsung ditandai sebagai versi 1 tanpa error.
+
+CREATE TABLE IF NOT EXISTS users (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,
+    email       TEXT NOT NULL,
+    password    TEXT NOT NULL,
+    role        VARCHAR(20) NOT NULL DEFAULT 'member',
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    CONSTRAINT chk_users_role CHECK (role IN ('member','librarian','admin'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS books (
+    id          BIGSERIAL PRIMARY KEY,
+    title       TEXT NOT NULL,
+    author      TEXT NOT NULL,
+    isbn        TEXT,
+    description TEXT,
+    stock       INTEGER DEFAULT 0,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_books_isbn ON books (isbn);
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_books_search ON books USING GIN (
+    to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(author, '') || ' ' || coalesce(description, ''))
+);
+
+CREATE TABLE IF NOT EXISTS borrows (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL,
+    book_id       BIGINT NOT NULL,
+    borrow_date   TIMESTAMPTZ NOT NULL,
+    due_date      TIMESTAMPTZ NOT NULL,
+    return_date   TIMESTAMPTZ,
+    status        VARCHAR(20) NOT NULL,
+    renewal_count BIGINT NOT NULL DEFAULT 0,
+    late_days     BIGINT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    deleted_at    TIMESTAMPTZ,
+    CONSTRAINT chk_borrows_status CHECK (status IN ('borrowed','returned','overdue')),
+    CONSTRAINT fk_borrows_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_borrows_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE INDEX IF NOT EXISTS idx_borrows_user_id ON borrows (user_id);
+CREATE INDEX IF NOT EXISTS idx_borrows_book_id ON borrows (book_id);
+CREATE INDEX IF NOT EXISTS idx_borrows_deleted_at ON borrows (deleted_at);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL,
+    token_hash     VARCHAR(64) NOT NULL,
+    family_id      VARCHAR(64) NOT NULL,
+    access_jti     VARCHAR(64) NOT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    revoked_at     TIMESTAMPTZ,
+    replaced_by_id BIGINT,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_access_jti ON refresh_tokens (access_jti);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL,
+    book_id         BIGINT NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    ready_at        TIMESTAMPTZ,
+    pickup_deadline TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    CONSTRAINT chk_reservations_status CHECK (status IN ('waiting','ready','fulfilled','cancelled','expired')),
+    CONSTRAINT fk_reservations_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_reservations_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE INDEX IF NOT EXISTS idx_reservations_user_id ON reservations (user_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_book_id ON reservations (book_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_status ON reservations (status);
+
+CREATE TABLE IF NOT EXISTS fines (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL,
+    borrow_id      BIGINT,
+    type           VARCHAR(20) NOT NULL,
+    amount         BIGINT NOT NULL,
+    note           VARCHAR(255),
+    recorded_by_id BIGINT,
+    created_at     TIMESTAMPTZ,
+    CONSTRAINT chk_fines_type CHECK (type IN ('late','lost','damaged','payment','waiver')),
+    CONSTRAINT fk_fines_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_fines_user_id ON fines (user_id);
+CREATE INDEX IF NOT EXISTS idx_fines_borrow_id ON fines (borrow_id);
@@ -0,0 +1,4 @@
+-- stock tetap berisi jumlah copy available terakhir
+ALTER TABLE reservations DROP COLUMN IF EXISTS copy_id;
+ALTER TABLE borrows DROP COLUMN IF EXISTS copy_id;
+DROP TABLE IF EXISTS book_copies;
@@ -0,0 +1,52 @@
+-- Copy fisik per buku. books.stock sekarang adalah jumlah copy yang available
+-- dan dihitung ulang oleh aplikasi setiap kali status copy berubah.
+
+CREATE TABLE book_copies (
+    id             BIGSERIAL PRIMARY KEY,
+    book_id        BIGINT NOT NULL,
+    barcode        VARCHAR(64) NOT NULL,
+    shelf_location VARCHAR(100),
+    condition      VARCHAR(20) NOT NULL DEFAULT 'good',
+    status         VARCHAR(20) NOT NULL DEFAULT 'available',
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ,
+    CONSTRAINT chk_book_copies_condition CHECK (condition IN ('new','good','fair','poor','damaged')),
+    CONSTRAINT chk_book_copies_status CHECK (status IN ('available','on_hold','borrowed','lost','maintenance','withdrawn')),
+    CONSTRAINT fk_book_copies_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE UNIQUE INDEX idx_book_copies_barcode ON book_copies (barcode);
+CREATE INDEX idx_book_copies_book_id ON book_copies (book_id);
+CREATE INDEX idx_book_copies_deleted_at ON book_copies (deleted_at);
+
+ALTER TABLE borrows ADD COLUMN copy_id BIGINT;
+ALTER TABLE borrows ADD CONSTRAINT fk_borrows_copy FOREIGN KEY (copy_id) REFERENCES book_copies (id);
+CREATE INDEX idx_borrows_copy_id ON borrows (copy_id);
+
+ALTER TABLE reservations ADD COLUMN copy_id BIGINT;
+ALTER TABLE reservations ADD CONSTRAINT fk_reservations_copy FOREIGN KEY (copy_id) REFERENCES book_copies (id);
+
+-- Backfill: satu copy untuk setiap pinjaman aktif, setiap hold yang sedang siap diambil
+-- dan setiap unit stock yang tersisa. Barcode LEGACY-* bisa diganti lewat label baru.
+INSERT INTO book_copies (book_id, barcode, status, created_at, updated_at)
+SELECT book_id, 'LEGACY-L' || id, 'borrowed', NOW(), NOW()
+FROM borrows
+WHERE status IN ('borrowed','overdue') AND deleted_at IS NULL;
+
+UPDATE borrows SET copy_id = c.id
+FROM book_copies c
+WHERE c.barcode = 'LEGACY-L' || borrows.id;
+
+INSERT INTO book_copies (book_id, barcode, status, created_at, updated_at)
+SELECT book_id, 'LEGACY-H' || id, 'on_hold', NOW(), NOW()
+FROM reservations
+WHERE status = 'ready';
+
+UPDATE reservations SET copy_id = c.id
+FROM book_copies c
+WHERE c.barcode = 'LEGACY-H' || reservations.id;
+
+INSERT INTO book_copies (book_id, barcode, status, created_at, updated_at)
+SELECT b.id, 'LEGACY-' || b.id || '-' || n, 'available', NOW(), NOW()
+FROM books b
+CROSS JOIN LATERAL generate_series(1, GREATEST(COALESCE(b.stock, 0), 0)) AS n;
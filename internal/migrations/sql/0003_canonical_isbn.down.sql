@@ -0,0 +1,3 @@
+-- Format ISBN lama (pemisah, ISBN-10) tidak disimpan, jadi tidak bisa dikembalikan.
+-- ISBN-13 kanonik tetap valid untuk versi sebelumnya.
+SELECT 1;
@@ -0,0 +1,31 @@
+-- Simpan ISBN dalam bentuk kanonik (ISBN-13 tanpa pemisah): pemisah dihapus dan
+-- ISBN-10 dengan check digit yang benar dikonversi ke ISBN-13 (prefix 978).
+-- Baris yang hasilnya sama dengan buku lain (termasuk yang sudah di-soft delete)
+-- dibiarkan apa adanya supaya bisa dibereskan manual.
+WITH normalized AS (
+    SELECT id, upper(regexp_replace(isbn, '[\s-]', '', 'g')) AS isbn
+    FROM books
+), canonical AS (
+    SELECT id,
+        CASE WHEN isbn ~ '^[0-9]{9}[0-9X]$' THEN
+            CASE WHEN (
+                SELECT SUM((11 - i) * CASE WHEN substr(isbn, i, 1) = 'X' THEN 10 ELSE substr(isbn, i, 1)::int END)
+                FROM generate_series(1, 10) AS i
+            ) % 11 = 0 THEN
+                '978' || substr(isbn, 1, 9) || ((10 - (
+                    SELECT SUM(substr('978' || substr(isbn, 1, 9), i, 1)::int * CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END)
+                    FROM generate_series(1, 12) AS i
+                ) % 10) % 10)::text
+            ELSE isbn END
+        ELSE isbn END AS isbn
+    FROM normalized
+), counted AS (
+    SELECT id, isbn, COUNT(*) OVER (PARTITION BY isbn) AS n
+    FROM canonical
+)
+UPDATE books b
+SET isbn = c.isbn
+FROM counted c
+WHERE b.id = c.id
+  AND c.n = 1
+  AND b.isbn IS DISTINCT FROM c.isbn;
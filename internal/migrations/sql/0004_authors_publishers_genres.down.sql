@@ -0,0 +1,7 @@
+-- books.author tidak berubah, jadi nama author tetap ada setelah rollback
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS book_publishers;
+DROP TABLE IF EXISTS book_authors;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS publishers;
+DROP TABLE IF EXISTS authors;
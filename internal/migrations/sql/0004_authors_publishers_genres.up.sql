@@ -0,0 +1,91 @@
+-- Author, publisher dan genre sebagai entity dengan relasi many-to-many ke books.
+-- books.author tetap ada sebagai nama tampilan.
+--
+-- name_key adalah nama huruf kecil tanpa spasi dan tanda baca (models.NameKey),
+-- supaya "J.K. Rowling" dan "JK Rowling" menjadi satu author.
+
+CREATE TABLE authors (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(200) NOT NULL,
+    name_key   VARCHAR(200) NOT NULL,
+    bio        TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_authors_name_key ON authors (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_authors_deleted_at ON authors (deleted_at);
+
+CREATE TABLE publishers (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(200) NOT NULL,
+    name_key   VARCHAR(200) NOT NULL,
+    website    VARCHAR(255),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_publishers_name_key ON publishers (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_publishers_deleted_at ON publishers (deleted_at);
+
+CREATE TABLE genres (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    name_key    VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_genres_name_key ON genres (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_genres_deleted_at ON genres (deleted_at);
+
+CREATE TABLE book_authors (
+    book_id   BIGINT NOT NULL,
+    author_id BIGINT NOT NULL,
+    PRIMARY KEY (book_id, author_id),
+    CONSTRAINT fk_book_authors_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_authors_author FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+CREATE INDEX idx_book_authors_author_id ON book_authors (author_id);
+
+CREATE TABLE book_publishers (
+    book_id      BIGINT NOT NULL,
+    publisher_id BIGINT NOT NULL,
+    PRIMARY KEY (book_id, publisher_id),
+    CONSTRAINT fk_book_publishers_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_publishers_publisher FOREIGN KEY (publisher_id) REFERENCES publishers (id)
+);
+CREATE INDEX idx_book_publishers_publisher_id ON book_publishers (publisher_id);
+
+CREATE TABLE book_genres (
+    book_id  BIGINT NOT NULL,
+    genre_id BIGINT NOT NULL,
+    PRIMARY KEY (book_id, genre_id),
+    CONSTRAINT fk_book_genres_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_genres_genre FOREIGN KEY (genre_id) REFERENCES genres (id)
+);
+CREATE INDEX idx_book_genres_genre_id ON book_genres (genre_id);
+
+-- Backfill: pecah books.author pada ",", ";", "&" dan " and " (sama dengan
+-- services.SplitAuthorNames). Ejaan yang muncul pertama secara alfabet dipakai
+-- sebagai nama author jika beberapa ejaan punya name_key yang sama.
+CREATE TEMPORARY TABLE book_author_names ON COMMIT DROP AS
+SELECT DISTINCT
+    b.id AS book_id,
+    btrim(part) AS name,
+    lower(regexp_replace(btrim(part), '[^[:alnum:]]', '', 'g')) AS name_key
+FROM books b
+CROSS JOIN LATERAL regexp_split_to_table(b.author, '\s*(,|;|&|\sand\s)\s*', 'i') AS part;
+
+DELETE FROM book_author_names WHERE name_key = '';
+
+INSERT INTO authors (name, name_key, created_at, updated_at)
+SELECT DISTINCT ON (name_key) name, name_key, NOW(), NOW()
+FROM book_author_names
+ORDER BY name_key, name;
+
+INSERT INTO book_authors (book_id, author_id)
+SELECT DISTINCT n.book_id, a.id
+FROM book_author_names n
+JOIN authors a ON a.name_key = n.name_key;
@@ -0,0 +1,3 @@
+-- File cover di blob storage tidak ikut dihapus.
+ALTER TABLE books DROP COLUMN IF EXISTS cover_type;
+ALTER TABLE books DROP COLUMN IF EXISTS cover_key;
@@ -0,0 +1,3 @@
+-- Cover buku. File-nya ada di blob storage, di sini hanya prefix key dan MIME type.
+ALTER TABLE books ADD COLUMN cover_key VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN cover_type VARCHAR(50) NOT NULL DEFAULT '';
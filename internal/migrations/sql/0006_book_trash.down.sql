@@ -0,0 +1,3 @@
+-- Gagal jika buku di trash memakai ISBN yang sama dengan buku lain; purge buku tersebut dulu.
+DROP INDEX IF EXISTS idx_books_isbn;
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn);
@@ -0,0 +1,105 @@
+-- Versi SQLite dari sql/0001_initial_schema.up.sql. Setiap migration di sql/ punya
+-- pasangan dengan versi dan nama yang sama di sini.
+--
+-- Database SQLite selalu dibuat dari kosong (SQLite baru didukung setelah versi 8),
+-- jadi backfill data lama di versi PostgreSQL tidak ikut. Waktu memakai tipe DATETIME
+-- supaya driver mengembalikannya sebagai time.Time. Tidak ada index full-text:
+-- pencarian buku di SQLite memakai fallback LIKE.
+
+CREATE TABLE users (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT NOT NULL,
+    email       TEXT NOT NULL,
+    password    TEXT NOT NULL,
+    role        VARCHAR(20) NOT NULL DEFAULT 'member',
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME,
+    CONSTRAINT chk_users_role CHECK (role IN ('member','librarian','admin'))
+);
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE books (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    title       TEXT NOT NULL,
+    author      TEXT NOT NULL,
+    isbn        TEXT,
+    description TEXT,
+    stock       INTEGER DEFAULT 0,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME
+);
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn);
+CREATE INDEX idx_books_deleted_at ON books (deleted_at);
+
+CREATE TABLE borrows (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       INTEGER NOT NULL,
+    book_id       INTEGER NOT NULL,
+    borrow_date   DATETIME NOT NULL,
+    due_date      DATETIME NOT NULL,
+    return_date   DATETIME,
+    status        VARCHAR(20) NOT NULL,
+    renewal_count INTEGER NOT NULL DEFAULT 0,
+    late_days     INTEGER NOT NULL DEFAULT 0,
+    created_at    DATETIME,
+    updated_at    DATETIME,
+    deleted_at    DATETIME,
+    CONSTRAINT chk_borrows_status CHECK (status IN ('borrowed','returned','overdue')),
+    CONSTRAINT fk_borrows_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_borrows_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE INDEX idx_borrows_user_id ON borrows (user_id);
+CREATE INDEX idx_borrows_book_id ON borrows (book_id);
+CREATE INDEX idx_borrows_deleted_at ON borrows (deleted_at);
+
+CREATE TABLE refresh_tokens (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id        INTEGER NOT NULL,
+    token_hash     VARCHAR(64) NOT NULL,
+    family_id      VARCHAR(64) NOT NULL,
+    access_jti     VARCHAR(64) NOT NULL,
+    expires_at     DATETIME NOT NULL,
+    revoked_at     DATETIME,
+    replaced_by_id INTEGER,
+    created_at     DATETIME,
+    updated_at     DATETIME
+);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE UNIQUE INDEX idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE UNIQUE INDEX idx_refresh_tokens_access_jti ON refresh_tokens (access_jti);
+
+CREATE TABLE reservations (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL,
+    book_id         INTEGER NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    ready_at        DATETIME,
+    pickup_deadline DATETIME,
+    created_at      DATETIME,
+    updated_at      DATETIME,
+    CONSTRAINT chk_reservations_status CHECK (status IN ('waiting','ready','fulfilled','cancelled','expired')),
+    CONSTRAINT fk_reservations_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_reservations_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE INDEX idx_reservations_user_id ON reservations (user_id);
+CREATE INDEX idx_reservations_book_id ON reservations (book_id);
+CREATE INDEX idx_reservations_status ON reservations (status);
+
+CREATE TABLE fines (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id        INTEGER NOT NULL,
+    borrow_id      INTEGER,
+    type           VARCHAR(20) NOT NULL,
+    amount         INTEGER NOT NULL,
+    note           VARCHAR(255),
+    recorded_by_id INTEGER,
+    created_at     DATETIME,
+    CONSTRAINT chk_fines_type CHECK (type IN ('late','lost','damaged','payment','waiver')),
+    CONSTRAINT fk_fines_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_fines_user_id ON fines (user_id);
+CREATE INDEX idx_fines_borrow_id ON fines (borrow_id);
@@ -0,0 +1,52 @@
+-- SQLite tidak bisa DROP COLUMN yang punya foreign key, jadi borrows dan
+-- reservations dibuat ulang tanpa copy_id.
+
+CREATE TABLE borrows_old (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       INTEGER NOT NULL,
+    book_id       INTEGER NOT NULL,
+    borrow_date   DATETIME NOT NULL,
+    due_date      DATETIME NOT NULL,
+    return_date   DATETIME,
+    status        VARCHAR(20) NOT NULL,
+    renewal_count INTEGER NOT NULL DEFAULT 0,
+    late_days     INTEGER NOT NULL DEFAULT 0,
+    created_at    DATETIME,
+    updated_at    DATETIME,
+    deleted_at    DATETIME,
+    CONSTRAINT chk_borrows_status CHECK (status IN ('borrowed','returned','overdue')),
+    CONSTRAINT fk_borrows_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_borrows_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+INSERT INTO borrows_old (id, user_id, book_id, borrow_date, due_date, return_date, status, renewal_count, late_days, created_at, updated_at, deleted_at)
+SELECT id, user_id, book_id, borrow_date, due_date, return_date, status, renewal_count, late_days, created_at, updated_at, deleted_at
+FROM borrows;
+DROP TABLE borrows;
+ALTER TABLE borrows_old RENAME TO borrows;
+CREATE INDEX idx_borrows_user_id ON borrows (user_id);
+CREATE INDEX idx_borrows_book_id ON borrows (book_id);
+CREATE INDEX idx_borrows_deleted_at ON borrows (deleted_at);
+
+CREATE TABLE reservations_old (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL,
+    book_id         INTEGER NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    ready_at        DATETIME,
+    pickup_deadline DATETIME,
+    created_at      DATETIME,
+    updated_at      DATETIME,
+    CONSTRAINT chk_reservations_status CHECK (status IN ('waiting','ready','fulfilled','cancelled','expired')),
+    CONSTRAINT fk_reservations_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_reservations_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+INSERT INTO reservations_old (id, user_id, book_id, status, ready_at, pickup_deadline, created_at, updated_at)
+SELECT id, user_id, book_id, status, ready_at, pickup_deadline, created_at, updated_at
+FROM reservations;
+DROP TABLE reservations;
+ALTER TABLE reservations_old RENAME TO reservations;
+CREATE INDEX idx_reservations_user_id ON reservations (user_id);
+CREATE INDEX idx_reservations_book_id ON reservations (book_id);
+CREATE INDEX idx_reservations_status ON reservations (status);
+
+DROP TABLE IF EXISTS book_copies;
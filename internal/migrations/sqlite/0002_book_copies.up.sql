@@ -0,0 +1,25 @@
+-- Copy fisik per buku. books.stock adalah jumlah copy yang available.
+
+CREATE TABLE book_copies (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    book_id        INTEGER NOT NULL,
+    barcode        VARCHAR(64) NOT NULL,
+    shelf_location VARCHAR(100),
+    condition      VARCHAR(20) NOT NULL DEFAULT 'good',
+    status         VARCHAR(20) NOT NULL DEFAULT 'available',
+    created_at     DATETIME,
+    updated_at     DATETIME,
+    deleted_at     DATETIME,
+    CONSTRAINT chk_book_copies_condition CHECK (condition IN ('new','good','fair','poor','damaged')),
+    CONSTRAINT chk_book_copies_status CHECK (status IN ('available','on_hold','borrowed','lost','maintenance','withdrawn')),
+    CONSTRAINT fk_book_copies_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE UNIQUE INDEX idx_book_copies_barcode ON book_copies (barcode);
+CREATE INDEX idx_book_copies_book_id ON book_copies (book_id);
+CREATE INDEX idx_book_copies_deleted_at ON book_copies (deleted_at);
+
+-- SQLite tidak punya ADD CONSTRAINT, foreign key ditulis langsung di kolomnya
+ALTER TABLE borrows ADD COLUMN copy_id INTEGER REFERENCES book_copies (id);
+CREATE INDEX idx_borrows_copy_id ON borrows (copy_id);
+
+ALTER TABLE reservations ADD COLUMN copy_id INTEGER REFERENCES book_copies (id);
@@ -0,0 +1,2 @@
+-- Hanya mengubah data lama di PostgreSQL; database SQLite selalu berisi ISBN kanonik.
+SELECT 1;
@@ -0,0 +1,65 @@
+-- Author, publisher dan genre sebagai entity dengan relasi many-to-many ke books.
+-- Tanpa backfill dari books.author (lihat 0001).
+
+CREATE TABLE authors (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(200) NOT NULL,
+    name_key   VARCHAR(200) NOT NULL,
+    bio        TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME
+);
+CREATE UNIQUE INDEX idx_authors_name_key ON authors (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_authors_deleted_at ON authors (deleted_at);
+
+CREATE TABLE publishers (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(200) NOT NULL,
+    name_key   VARCHAR(200) NOT NULL,
+    website    VARCHAR(255),
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME
+);
+CREATE UNIQUE INDEX idx_publishers_name_key ON publishers (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_publishers_deleted_at ON publishers (deleted_at);
+
+CREATE TABLE genres (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        VARCHAR(100) NOT NULL,
+    name_key    VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME
+);
+CREATE UNIQUE INDEX idx_genres_name_key ON genres (name_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_genres_deleted_at ON genres (deleted_at);
+
+CREATE TABLE book_authors (
+    book_id   INTEGER NOT NULL,
+    author_id INTEGER NOT NULL,
+    PRIMARY KEY (book_id, author_id),
+    CONSTRAINT fk_book_authors_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_authors_author FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+CREATE INDEX idx_book_authors_author_id ON book_authors (author_id);
+
+CREATE TABLE book_publishers (
+    book_id      INTEGER NOT NULL,
+    publisher_id INTEGER NOT NULL,
+    PRIMARY KEY (book_id, publisher_id),
+    CONSTRAINT fk_book_publishers_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_publishers_publisher FOREIGN KEY (publisher_id) REFERENCES publishers (id)
+);
+CREATE INDEX idx_book_publishers_publisher_id ON book_publishers (publisher_id);
+
+CREATE TABLE book_genres (
+    book_id  INTEGER NOT NULL,
+    genre_id INTEGER NOT NULL,
+    PRIMARY KEY (book_id, genre_id),
+    CONSTRAINT fk_book_genres_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_genres_genre FOREIGN KEY (genre_id) REFERENCES genres (id)
+);
+CREATE INDEX idx_book_genres_genre_id ON book_genres (genre_id);
@@ -0,0 +1,3 @@
+-- File cover di blob storage tidak ikut dihapus.
+ALTER TABLE books DROP COLUMN cover_type;
+ALTER TABLE books DROP COLUMN cover_key;
@@ -0,0 +1,4 @@
+-- ISBN hanya unik di antara buku yang belum dihapus, supaya buku di trash tidak
+-- menghalangi buku baru dengan ISBN yang sama.
+DROP INDEX IF EXISTS idx_books_isbn;
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn) WHERE deleted_at IS NULL;
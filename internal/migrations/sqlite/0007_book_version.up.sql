@@ -0,0 +1,2 @@
+-- Version untuk optimistic locking, naik setiap kali buku diubah.
+ALTER TABLE books ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,17 @@
+-- Riwayat perubahan buku dan peminjaman. Tanpa foreign key ke books/borrows supaya
+-- riwayat buku yang di-purge tetap ada. changes berisi JSON sebagai TEXT.
+CREATE TABLE audit_logs (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id   INTEGER NOT NULL,
+    book_id     INTEGER,
+    action      VARCHAR(20) NOT NULL,
+    actor_id    INTEGER,
+    request_id  VARCHAR(100),
+    changes     TEXT NOT NULL DEFAULT '{}',
+    created_at  DATETIME
+);
+CREATE INDEX idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX idx_audit_logs_book_id ON audit_logs (book_id);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
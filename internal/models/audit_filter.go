@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AuditFilter - kriteria pencarian audit log. Field kosong/nil berarti tidak difilter.
+type AuditFilter struct {
+	EntityType	AuditEntity
+	EntityID	uint
+	BookID		uint
+	ActorID		uint
+	Action		AuditAction
+	RequestID	string
+	From		*time.Time	// created_at >= From
+	To			*time.Time	// created_at < To
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityBook		AuditEntity = "book"
+	AuditEntityBorrow	AuditEntity = "borrow"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate	AuditAction = "create"
+	AuditActionUpdate	AuditAction = "update"
+	AuditActionDelete	AuditAction = "delete"
+	AuditActionRestore	AuditAction = "restore"
+	AuditActionPurge	AuditAction = "purge"
+	AuditActionBorrow	AuditAction = "borrow"
+	AuditActionReturn	AuditAction = "return"
+	AuditActionRenew	AuditAction = "renew"
+	AuditActionOverdue	AuditAction = "overdue"
+)
+
+// AuditChange - nilai lama dan baru satu field. Old null pada create, New null pada delete.
+type AuditChange struct {
+	Old	interface{}	`json:"old"`
+	New	interface{}	`json:"new"`
+}
+
+// AuditChanges - field yang berubah, disimpan sebagai satu kolom JSON
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+}
+
+// AuditLog - satu perubahan data. BookID diisi untuk perubahan buku maupun peminjaman
+// buku itu, sehingga riwayat sebuah buku cukup difilter dengan book_id. ActorID nil berarti
+// perubahan dilakukan sistem (mis. job overdue).
+type AuditLog struct {
+	ID			uint			`gorm:"primarykey" json:"id"`
+	EntityType	AuditEntity		`gorm:"type:varchar(20);not null" json:"entity_type"`
+	EntityID	uint			`gorm:"not null" json:"entity_id"`
+	BookID		*uint			`gorm:"index" json:"book_id,omitempty"`
+	Action		AuditAction		`gorm:"type:varchar(20);not null" json:"action"`
+	ActorID		*uint			`gorm:"index" json:"actor_id,omitempty"`
+	RequestID	string			`gorm:"type:varchar(100)" json:"request_id,omitempty"`
+	Changes		AuditChanges	`gorm:"type:jsonb;not null" json:"changes" swaggertype:"object"`
+	CreatedAt	time.Time		`gorm:"index" json:"created_at"`
+
+	// Relations
+	Actor	*User	`gorm:"foreignKey:ActorID;references:ID" json:"actor,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Author - penulis buku. Book.Author tetap disimpan sebagai nama tampilan,
+// relasi ke entity ini lewat tabel book_authors.
+type Author struct {
+	ID			uint			`gorm:"primarykey" json:"id"`
+	Name		string			`gorm:"type:varchar(200);not null" json:"name"`
+	NameKey		string			`gorm:"type:varchar(200);not null" json:"-"`	// hasil NameKey(Name), unik di antara author yang belum dihapus
+	Bio			string			`gorm:"type:text" json:"bio"`
+	CreatedAt	time.Time		`json:"created_at"`
+	UpdatedAt	time.Time		`json:"updated_at"`
+	DeletedAt	gorm.DeletedAt	`gorm:"index" json:"-"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"path"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,10 +12,39 @@ type Book struct {
 	ID 			uint			`gorm:"primarykey" json:"id"`
 	Title		string			`gorm:"not null" json:"title"`
 	Author		string			`gorm:"not null" json:"author"`
-	ISBN		string			`gorm:"uniqueIndex" json:"isbn"`
+	ISBN		string			`gorm:"uniqueIndex:idx_books_isbn,where:deleted_at IS NULL" json:"isbn"`	// unik di antara buku yang belum dihapus
 	Description string			`gorm:"type:text" json:"description"`
-	Stock		int				`gorm:"type:integer;default:0" json:"stock"`
+	Stock		int				`gorm:"type:integer;default:0" json:"stock"`	// jumlah copy available, dihitung ulang dari book_copies
+	CoverKey	string			`gorm:"type:varchar(255)" json:"-"`			// prefix blob cover "covers/{id}/{hash}", kosong = tanpa cover
+	CoverType	string			`gorm:"type:varchar(50)" json:"-"`			// MIME type gambar cover asli
+	CoverURL	string			`gorm:"-" json:"cover_url,omitempty"`			// diisi dari CoverKey oleh AfterFind
+	Version		int64			`gorm:"not null;default:1" json:"version"`		// naik setiap kali buku diubah, untuk optimistic locking
 	CreatedAt	time.Time		`json:"created_at"`
 	UpdatedAt	time.Time		`json:"updated_at"`
 	DeletedAt 	gorm.DeletedAt	`gorm:"index" json:"-"`
-}
\ No newline at end of file
+
+	// Relations. Author di atas adalah nama tampilan, Authors adalah entity-nya.
+	Authors		[]Author		`gorm:"many2many:book_authors" json:"authors,omitempty"`
+	Publishers	[]Publisher		`gorm:"many2many:book_publishers" json:"publishers,omitempty"`
+	Genres		[]Genre			`gorm:"many2many:book_genres" json:"genres,omitempty"`
+}
+// ETag - entity tag response buku, sama dengan Version. Stock tidak ikut karena berubah
+// setiap peminjaman dan tidak bisa diubah lewat PUT/PATCH, sehingga If-Match hanya
+// gagal jika detail buku benar-benar diubah request lain.
+func (b *Book) ETag() string {
+	return fmt.Sprintf(`"%d"`, b.Version)
+}
+
+// AfterFind - URL cover memuat hash gambar supaya bisa di-cache selamanya oleh client
+func (b *Book) AfterFind(tx *gorm.DB) error {
+	b.SetCoverURL()
+	return nil
+}
+
+// SetCoverURL - hitung ulang CoverURL dari CoverKey
+func (b *Book) SetCoverURL() {
+	b.CoverURL = ""
+	if b.CoverKey != "" {
+		b.CoverURL = fmt.Sprintf("/api/v1/books/%d/cover?v=%s", b.ID, path.Base(b.CoverKey))
+	}
+}
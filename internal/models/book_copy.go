@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CopyStatus string
+
+const (
+	CopyStatusAvailable		CopyStatus = "available"
+	CopyStatusOnHold		CopyStatus = "on_hold"
+	CopyStatusBorrowed		CopyStatus = "borrowed"
+	CopyStatusLost			CopyStatus = "lost"
+	CopyStatusMaintenance	CopyStatus = "maintenance"
+	CopyStatusWithdrawn		CopyStatus = "withdrawn"
+)
+
+type CopyCondition string
+
+const (
+	CopyConditionNew		CopyCondition = "new"
+	CopyConditionGood		CopyCondition = "good"
+	CopyConditionFair		CopyCondition = "fair"
+	CopyConditionPoor		CopyCondition = "poor"
+	CopyConditionDamaged	CopyCondition = "damaged"
+)
+
+// BookCopy - satu eksemplar fisik dari sebuah buku, diidentifikasi dengan barcode.
+// Book.Stock adalah jumlah copy dengan status available.
+type BookCopy struct {
+	ID uint `gorm:"primarykey" json:"id"`
+	BookID uint `gorm:"not null;index" json:"book_id"`
+	Barcode string `gorm:"type:varchar(64);uniqueIndex;not null" json:"barcode"`
+	ShelfLocation string `gorm:"type:varchar(100)" json:"shelf_location"`
+	Condition CopyCondition `gorm:"type:varchar(20);check:condition IN ('new','good','fair','poor','damaged');not null;default:'good'" json:"condition"`
+	Status CopyStatus `gorm:"type:varchar(20);check:status IN ('available','on_hold','borrowed','lost','maintenance','withdrawn');not null;default:'available'" json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relations
+	Book *Book `gorm:"foreignKey:BookID;references:ID" json:"book,omitempty"`
+}
+
+// InCirculation - copy sedang dipinjam atau disisihkan untuk hold
+func (c *BookCopy) InCirculation() bool {
+	return c.Status == CopyStatusBorrowed || c.Status == CopyStatusOnHold
+}
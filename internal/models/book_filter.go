@@ -0,0 +1,19 @@
+package models
+
+// BookFilter - kriteria pencarian katalog buku. Field kosong/nil berarti tidak difilter.
+type BookFilter struct {
+	Query		string		// full-text search di title, author dan description
+	Author		string		// substring nama author (case-insensitive)
+	ISBN		string		// exact match
+	AuthorID	uint		// buku yang ditulis author ini
+	PublisherID	uint		// buku dari publisher ini
+	GenreID		uint		// buku dengan genre ini
+	InStock		*bool		// true = stock > 0, false = stock habis
+	Sort		[]SortField	// urutan hasil, kosong = relevance (jika ada Query) lalu id
+}
+
+// SortField - satu kolom pengurutan
+type SortField struct {
+	Field	string
+	Desc	bool
+}
@@ -18,10 +18,13 @@ type Borrow struct {
 	ID uint `gorm:"primarykey" json:"id"`
 	UserID uint `gorm:"not null;index" json:"user_id"`
 	BookID uint `gorm:"not null;index" json:"book_id"`
+	CopyID *uint `gorm:"index" json:"copy_id,omitempty"`
 	BorrowDate time.Time `gorm:"not null" json:"borrow_date"`
 	DueDate time.Time `gorm:"not null" json:"due_date"`
 	ReturnDate *time.Time `json:"return_date,omitempty"`
 	Status BorrowStatus `gorm:"type:varchar(20);check:status IN ('borrowed','returned','overdue');not null" json:"status"`
+	RenewalCount int `gorm:"not null;default:0" json:"renewal_count"`
+	LateDays int `gorm:"not null;default:0" json:"late_days"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -30,4 +33,5 @@ type Borrow struct {
 	// Relations
 	User User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
 	Book Book `gorm:"foreignKey:BookID;references:ID" json:"book,omitempty"`
+	Copy *BookCopy `gorm:"foreignKey:CopyID;references:ID" json:"copy,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// BorrowFilter - kriteria daftar peminjaman. Field kosong/nil berarti tidak difilter.
+type BorrowFilter struct {
+	UserID		uint
+	BookID		uint
+	Status		BorrowStatus
+	OverdueAt	*time.Time	// hanya yang belum dikembalikan dan DueDate sebelum waktu ini
+}
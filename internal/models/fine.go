@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+type FineType string
+
+const (
+	FineTypeLate	FineType = "late"
+	FineTypeLost	FineType = "lost"
+	FineTypeDamaged	FineType = "damaged"
+	FineTypePayment	FineType = "payment"
+	FineTypeWaiver	FineType = "waiver"
+)
+
+// Fine - satu entri ledger denda milik user. Amount dalam satuan mata uang terkecil:
+// charge (late/lost/damaged) bernilai positif, payment dan waiver bernilai negatif,
+// sehingga saldo outstanding = SUM(amount).
+type Fine struct {
+	ID uint `gorm:"primarykey" json:"id"`
+	UserID uint `gorm:"not null;index" json:"user_id"`
+	BorrowID *uint `gorm:"index" json:"borrow_id,omitempty"`
+	Type FineType `gorm:"type:varchar(20);check:type IN ('late','lost','damaged','payment','waiver');not null" json:"type"`
+	Amount int64 `gorm:"not null" json:"amount"`
+	Note string `gorm:"type:varchar(255)" json:"note,omitempty"`
+	RecordedByID *uint `json:"recorded_by_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;references:ID" json:"-"`
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Genre - kategori buku untuk browsing katalog, relasi ke buku lewat tabel book_genres
+type Genre struct {
+	ID			uint			`gorm:"primarykey" json:"id"`
+	Name		string			`gorm:"type:varchar(100);not null" json:"name"`
+	NameKey		string			`gorm:"type:varchar(100);not null" json:"-"`	// hasil NameKey(Name), unik di antara genre yang belum dihapus
+	Description	string			`gorm:"type:text" json:"description"`
+	CreatedAt	time.Time		`json:"created_at"`
+	UpdatedAt	time.Time		`json:"updated_at"`
+	DeletedAt	gorm.DeletedAt	`gorm:"index" json:"-"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameKey - kunci deduplikasi nama author, publisher dan genre: huruf kecil tanpa spasi
+// dan tanda baca, jadi "J.K. Rowling" dan "JK Rowling" dianggap nama yang sama.
+// Harus sama dengan ekspresi name_key di migration 0004.
+func NameKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Publisher - penerbit buku, relasi ke buku lewat tabel book_publishers
+type Publisher struct {
+	ID			uint			`gorm:"primarykey" json:"id"`
+	Name		string			`gorm:"type:varchar(200);not null" json:"name"`
+	NameKey		string			`gorm:"type:varchar(200);not null" json:"-"`	// hasil NameKey(Name), unik di antara publisher yang belum dihapus
+	Website		string			`gorm:"type:varchar(255)" json:"website"`
+	CreatedAt	time.Time		`json:"created_at"`
+	UpdatedAt	time.Time		`json:"updated_at"`
+	DeletedAt	gorm.DeletedAt	`gorm:"index" json:"-"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RefreshToken - refresh token yang disimpan dalam bentuk hash.
+// Setiap refresh token terikat ke satu access token (AccessJTI) dan satu
+// token family; rotasi menghasilkan token baru dalam family yang sama.
+type RefreshToken struct {
+	ID				uint		`gorm:"primarykey" json:"id"`
+	UserID			uint		`gorm:"not null;index" json:"user_id"`
+	TokenHash		string		`gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	FamilyID		string		`gorm:"type:varchar(64);not null;index" json:"family_id"`
+	AccessJTI		string		`gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt		time.Time	`gorm:"not null" json:"expires_at"`
+	RevokedAt		*time.Time	`json:"revoked_at,omitempty"`
+	ReplacedByID	*uint		`json:"replaced_by_id,omitempty"`
+	CreatedAt		time.Time	`json:"created_at"`
+	UpdatedAt		time.Time	`json:"updated_at"`
+}
+
+// IsActive - token belum dicabut dan belum kedaluwarsa
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationStatusWaiting	ReservationStatus = "waiting"
+	ReservationStatusReady		ReservationStatus = "ready"
+	ReservationStatusFulfilled	ReservationStatus = "fulfilled"
+	ReservationStatusCancelled	ReservationStatus = "cancelled"
+	ReservationStatusExpired	ReservationStatus = "expired"
+)
+
+// Reservation - hold/antrian member untuk buku yang sedang habis.
+// Antrian per buku bersifat FIFO berdasarkan urutan dibuat.
+type Reservation struct {
+	ID uint `gorm:"primarykey" json:"id"`
+	UserID uint `gorm:"not null;index" json:"user_id"`
+	BookID uint `gorm:"not null;index" json:"book_id"`
+	CopyID *uint `json:"copy_id,omitempty"` // copy yang disisihkan saat hold siap diambil
+	Status ReservationStatus `gorm:"type:varchar(20);check:status IN ('waiting','ready','fulfilled','cancelled','expired');not null;index" json:"status"`
+	ReadyAt *time.Time `json:"ready_at,omitempty"`
+	PickupDeadline *time.Time `json:"pickup_deadline,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Book Book `gorm:"foreignKey:BookID;references:ID" json:"book,omitempty"`
+}
+
+// IsActive - hold masih menunggu atau sudah siap diambil
+func (r *Reservation) IsActive() bool {
+	return r.Status == ReservationStatusWaiting || r.Status == ReservationStatusReady
+}
@@ -6,11 +6,63 @@ import (
 	"gorm.io/gorm"
 )
 
+type Role string
+
+const (
+	RoleMember		Role = "member"
+	RoleLibrarian	Role = "librarian"
+	RoleAdmin		Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermissionBorrowBooks	Permission = "books:borrow"
+	PermissionManageBooks	Permission = "books:manage"
+	PermissionManageLoans	Permission = "loans:manage"
+	PermissionManageUsers	Permission = "users:manage"
+)
+
+// rolePermissions - daftar permission yang dimiliki tiap role
+var rolePermissions = map[Role][]Permission{
+	RoleMember: {
+		PermissionBorrowBooks,
+	},
+	RoleLibrarian: {
+		PermissionBorrowBooks,
+		PermissionManageBooks,
+		PermissionManageLoans,
+	},
+	RoleAdmin: {
+		PermissionBorrowBooks,
+		PermissionManageBooks,
+		PermissionManageLoans,
+		PermissionManageUsers,
+	},
+}
+
+// IsValid - cek apakah role dikenal
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// HasPermission - cek apakah role memiliki permission tertentu
+func (r Role) HasPermission(p Permission) bool {
+	for _, perm := range rolePermissions[r] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
 type User struct {
 	ID 			uint			`gorm:"primarykey" json:"id"`
 	Name 		string			`gorm:"not null" json:"name"`
 	Email 		string			`gorm:"uniqueIndex;not null" json:"email"`
 	Password 	string			`gorm:"not null" json:"-"`
+	Role		Role			`gorm:"type:varchar(20);not null;default:'member';check:role IN ('member','librarian','admin')" json:"role"`
 	CreatedAt 	time.Time		`json:"created_at"`
 	UpdatedAt 	time.Time		`json:"updated_at"`
 	DeletedAt 	gorm.DeletedAt	`gorm:"index" json:"-"`
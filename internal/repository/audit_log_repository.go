@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"book-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	CreateWithTx(tx *gorm.DB, entry *models.AuditLog) error
+	Find(filter models.AuditFilter, limit, offset int) ([]models.AuditLog, error)
+	Count(filter models.AuditFilter) (int64, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) CreateWithTx(tx *gorm.DB, entry *models.AuditLog) error {
+	return tx.Create(entry).Error
+}
+
+// Find - entri terbaru lebih dulu, beserta user yang melakukan perubahan
+func (r *auditLogRepository) Find(filter models.AuditFilter, limit, offset int) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	err := r.filterQuery(filter).
+		Preload("Actor", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *auditLogRepository) Count(filter models.AuditFilter) (int64, error) {
+	var count int64
+	err := r.filterQuery(filter).Count(&count).Error
+	return count, err
+}
+
+func (r *auditLogRepository) filterQuery(filter models.AuditFilter) *gorm.DB {
+	query := r.db.Model(&models.AuditLog{})
+
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.BookID != 0 {
+		query = query.Where("book_id = ?", filter.BookID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
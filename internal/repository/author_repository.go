@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"book-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type AuthorRepository interface {
+	Create(author *models.Author) error
+	FindAll(query string, limit, offset int) ([]models.Author, error)
+	Count(query string) (int64, error)
+	FindByID(id uint) (*models.Author, error)
+	FindByNameKey(nameKey string) (*models.Author, error)
+	FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Author, error)
+	FindOrCreateByNamesWithTx(tx *gorm.DB, names []string) ([]models.Author, error)
+	Update(author *models.Author) error
+	Delete(id uint) error
+	CountBooks(id uint) (int64, error)
+}
+
+type authorRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorRepository(db *gorm.DB) AuthorRepository {
+	return &authorRepository{db: db}
+}
+
+func (r *authorRepository) Create(author *models.Author) error {
+	return r.db.Create(author).Error
+}
+
+func (r *authorRepository) FindAll(query string, limit, offset int) ([]models.Author, error) {
+	var authors []models.Author
+	err := r.filtered(query).Order("name ASC, id ASC").Limit(limit).Offset(offset).Find(&authors).Error
+	return authors, err
+}
+
+func (r *authorRepository) Count(query string) (int64, error) {
+	var count int64
+	err := r.filtered(query).Count(&count).Error
+	return count, err
+}
+
+// filtered - query berisi substring nama (case-insensitive)
+func (r *authorRepository) filtered(query string) *gorm.DB {
+	db := r.db.Model(&models.Author{})
+	if query != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(query)+"%")
+	}
+	return db
+}
+
+func (r *authorRepository) FindByID(id uint) (*models.Author, error) {
+	var author models.Author
+	err := r.db.First(&author, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+func (r *authorRepository) FindByNameKey(nameKey string) (*models.Author, error) {
+	var author models.Author
+	err := r.db.Where("name_key = ?", nameKey).First(&author).Error
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
+
+func (r *authorRepository) FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Author, error) {
+	var authors []models.Author
+	err := tx.Where("id IN ?", ids).Find(&authors).Error
+	return authors, err
+}
+
+// FindOrCreateByNamesWithTx - author untuk setiap nama, dibuat jika belum ada.
+// Nama dengan NameKey yang sama memakai author yang sama; urutan hasil mengikuti names.
+func (r *authorRepository) FindOrCreateByNamesWithTx(tx *gorm.DB, names []string) ([]models.Author, error) {
+	var authors []models.Author
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		key := models.NameKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		author := models.Author{Name: name, NameKey: key}
+		if err := tx.Where("name_key = ?", key).FirstOrCreate(&author).Error; err != nil {
+			return nil, err
+		}
+		authors = append(authors, author)
+	}
+
+	return authors, nil
+}
+
+func (r *authorRepository) Update(author *models.Author) error {
+	return r.db.Save(author).Error
+}
+
+func (r *authorRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Author{}, id).Error
+}
+
+// CountBooks - jumlah buku (yang belum dihapus) dari author ini
+func (r *authorRepository) CountBooks(id uint) (int64, error) {
+	var count int64
+	err := r.db.Table("book_authors").
+		Joins("JOIN books ON books.id = book_authors.book_id AND books.deleted_at IS NULL").
+		Where("book_authors.author_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"book-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type BookCopyRepository interface {
+	CreateWithTx(tx *gorm.DB, bookCopy *models.BookCopy) error
+	FindByID(id uint) (*models.BookCopy, error)
+	FindByIDWithLock(tx *gorm.DB, id uint) (*models.BookCopy, error)
+	FindByBarcode(barcode string) (*models.BookCopy, error)
+	FindByBookID(bookID uint) ([]models.BookCopy, error)
+	FindAvailableWithLock(tx *gorm.DB, bookID uint) (*models.BookCopy, error)
+	CountByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error)
+	UpdateWithTx(tx *gorm.DB, bookCopy *models.BookCopy) error
+	SyncBookStockWithTx(tx *gorm.DB, bookID uint) error
+}
+
+type bookCopyRepository struct {
+	db *gorm.DB
+}
+
+func NewBookCopyRepository(db *gorm.DB) BookCopyRepository {
+	return &bookCopyRepository{db: db}
+}
+
+func (r *bookCopyRepository) CreateWithTx(tx *gorm.DB, bookCopy *models.BookCopy) error {
+	return tx.Create(bookCopy).Error
+}
+
+func (r *bookCopyRepository) FindByID(id uint) (*models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	err := r.db.First(&bookCopy, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+func (r *bookCopyRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	err := forUpdate(tx).First(&bookCopy, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+func (r *bookCopyRepository) FindByBarcode(barcode string) (*models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	err := r.db.Preload("Book").Where("barcode = ?", barcode).First(&bookCopy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+func (r *bookCopyRepository) FindByBookID(bookID uint) ([]models.BookCopy, error) {
+	var copies []models.BookCopy
+	err := r.db.Where("book_id = ?", bookID).Order("id ASC").Find(&copies).Error
+	return copies, err
+}
+
+// FindAvailableWithLock - LOCK satu copy yang available untuk dipinjamkan
+func (r *bookCopyRepository) FindAvailableWithLock(tx *gorm.DB, bookID uint) (*models.BookCopy, error) {
+	var bookCopy models.BookCopy
+	err := forUpdate(tx).
+		Where("book_id = ? AND status = ?", bookID, models.CopyStatusAvailable).
+		Order("id ASC").
+		First(&bookCopy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bookCopy, nil
+}
+
+// CountByBookIDWithTx - jumlah semua copy sebuah buku, termasuk yang sudah dihapus,
+// dipakai untuk nomor urut barcode
+func (r *bookCopyRepository) CountByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error) {
+	var count int64
+	err := tx.Unscoped().Model(&models.BookCopy{}).Where("book_id = ?", bookID).Count(&count).Error
+	return count, err
+}
+
+func (r *bookCopyRepository) UpdateWithTx(tx *gorm.DB, bookCopy *models.BookCopy) error {
+	return tx.Save(bookCopy).Error
+}
+
+// SyncBookStockWithTx - hitung ulang books.stock dari jumlah copy yang available.
+// Dipanggil setiap kali status copy berubah, di transaction yang sama.
+func (r *bookCopyRepository) SyncBookStockWithTx(tx *gorm.DB, bookID uint) error {
+	available := tx.Session(&gorm.Session{NewDB: true}).Model(&models.BookCopy{}).
+		Select("COUNT(*)").
+		Where("book_id = ? AND status = ?", bookID, models.CopyStatusAvailable)
+	return tx.Model(&models.Book{}).Where("id = ?", bookID).Update("stock", available).Error
+}
@@ -2,38 +2,79 @@ package repository
 
 import (
 	"book-api/internal/models"
+	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ErrStaleBook - version buku di database sudah berbeda, buku diubah oleh request lain
+var ErrStaleBook = errors.New("book was modified by another request")
+
 type BookRepository interface {
 	Create(book *models.Book) error
-	FindAll(limit, offset int) ([]models.Book, error)
+	CreateWithTx(tx *gorm.DB, book *models.Book) error
+	FindAll(filter models.BookFilter, limit, offset int) ([]models.Book, error)
 	FindByID(id uint) (*models.Book, error)
 	FindByIDWithLock(tx *gorm.DB, id uint) (*models.Book, error)
 	FindByISBN(isbn string) (*models.Book, error)
+	FindByISBNsWithTx(tx *gorm.DB, isbns []string) ([]models.Book, error)
 	Update(book *models.Book) error
 	UpdateWithTx(tx *gorm.DB, book *models.Book) error
+	ReplaceRelationsWithTx(tx *gorm.DB, book *models.Book, authors []models.Author, publishers []models.Publisher, genres []models.Genre) error
+	UpdateCoverWithTx(tx *gorm.DB, id uint, coverKey, coverType string) error
 	Delete(id uint) error
-	Count() (int64, error)
+	DeleteWithTx(tx *gorm.DB, id uint) error
+	Count(filter models.BookFilter) (int64, error)
+
+	// Trash - buku yang sudah di-soft delete
+	FindDeleted(limit, offset int) ([]models.Book, error)
+	CountDeleted() (int64, error)
+	FindDeletedByIDWithLock(tx *gorm.DB, id uint) (*models.Book, error)
+	RestoreWithTx(tx *gorm.DB, id uint) error
+	PurgeWithTx(tx *gorm.DB, id uint) error
+}
+
+// bookSearchVector - dokumen full-text buku. Harus sama persis dengan ekspresi
+// index idx_books_search di migration supaya index dipakai oleh planner.
+const bookSearchVector = "to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(author, '') || ' ' || coalesce(description, ''))"
+
+// bookSortColumns - whitelist kolom yang boleh dipakai untuk sort
+var bookSortColumns = map[string]string{
+	"title":		"title",
+	"author":		"author",
+	"stock":		"stock",
+	"created_at":	"created_at",
+	"updated_at":	"updated_at",
 }
 
 type bookRepository struct {
 	db *gorm.DB
+	fullText bool
 }
 
+// NewBookRepository - di PostgreSQL pencarian memakai tsvector + ranking,
+// di database lain memakai fallback LIKE
 func NewBookRepository(db *gorm.DB) BookRepository {
-	return &bookRepository{db: db}
+	return &bookRepository{db: db, fullText: db.Dialector.Name() == "postgres"}
 }
 
+
+// Create - relasi author/publisher/genre disimpan terpisah lewat ReplaceRelationsWithTx
 func (r *bookRepository) Create(book *models.Book) error {
-	return r.db.Create(book).Error
+	return r.db.Omit(clause.Associations).Create(book).Error
+}
+
+func (r *bookRepository) CreateWithTx(tx *gorm.DB, book *models.Book) error {
+	return tx.Omit(clause.Associations).Create(book).Error
 }
 
-func (r *bookRepository) FindAll(limit, offset int) ([]models.Book, error) {
+func (r *bookRepository) FindAll(filter models.BookFilter, limit, offset int) ([]models.Book, error) {
 	var books []models.Book
-	err := r.db.Limit(limit).Offset(offset).Find(&books).Error
+	query := r.order(r.filtered(filter), filter)
+	err := withRelations(query).Limit(limit).Offset(offset).Find(&books).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,15 +83,16 @@ func (r *bookRepository) FindAll(limit, offset int) ([]models.Book, error) {
 
 func (r *bookRepository) FindByID(id uint) (*models.Book, error) {
 	var book models.Book
-	err := r.db.Where("id = ?", id).First(&book).Error
+	err := withRelations(r.db).Where("id = ?", id).First(&book).Error
 	if err != nil {
 		return nil, err
 	}
 	return &book, nil
 }
+
 func (r *bookRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.Book, error) {
 	var book models.Book
-	err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&book, id).Error
+	err := forUpdate(tx).First(&book, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -59,27 +101,230 @@ func (r *bookRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.Book, e
 
 func (r *bookRepository) FindByISBN(isbn string) (*models.Book, error) {
 	var book models.Book
-	err := r.db.Where("isbn = ?", isbn).First(&book).Error
+	err := withRelations(r.db).Where("isbn = ?", isbn).First(&book).Error
 	if err != nil {
 		return nil, err
 	}
 	return &book, nil
 }
 
+// FindByISBNsWithTx - LOCK buku yang ISBN-nya ada di daftar, dipakai oleh bulk import
+func (r *bookRepository) FindByISBNsWithTx(tx *gorm.DB, isbns []string) ([]models.Book, error) {
+	var books []models.Book
+	err := forUpdate(tx).
+		Where("isbn IN ?", isbns).
+		Find(&books).Error
+	return books, err
+}
+
+// Update - hanya title, author, isbn dan description yang disimpan. Stock dihitung dari
+// book_copies, relasi diganti lewat ReplaceRelationsWithTx dan cover lewat UpdateCoverWithTx.
 func (r *bookRepository) Update(book *models.Book) error {
-	return r.db.Save(book).Error
+	return r.UpdateWithTx(r.db, book)
 }
 
+// UpdateWithTx - optimistic locking: hanya berhasil jika version di database masih sama
+// dengan book.Version, lalu version dinaikkan. ErrStaleBook jika buku sudah diubah request lain.
 func (r *bookRepository) UpdateWithTx(tx *gorm.DB, book *models.Book) error {
-	return tx.Save(book).Error
+	now := time.Now()
+	result := tx.Model(&models.Book{}).
+		Where("id = ? AND version = ?", book.ID, book.Version).
+		Updates(map[string]interface{}{
+			"title":		book.Title,
+			"author":		book.Author,
+			"isbn":			book.ISBN,
+			"description":	book.Description,
+			"version":		gorm.Expr("version + 1"),
+			"updated_at":	now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleBook
+	}
+
+	book.Version++
+	book.UpdatedAt = now
+	return nil
+}
+
+// UpdateCoverWithTx - coverKey kosong berarti buku tidak punya cover. Version ikut naik
+// karena cover_url di response berubah.
+func (r *bookRepository) UpdateCoverWithTx(tx *gorm.DB, id uint, coverKey, coverType string) error {
+	return tx.Model(&models.Book{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"cover_key": coverKey, "cover_type": coverType, "version": gorm.Expr("version + 1")}).Error
+}
+
+// ReplaceRelationsWithTx - ganti author, publisher dan genre buku dengan daftar ini.
+// Slice nil berarti relasi tersebut tidak diubah, slice kosong menghapus semuanya.
+func (r *bookRepository) ReplaceRelationsWithTx(tx *gorm.DB, book *models.Book, authors []models.Author, publishers []models.Publisher, genres []models.Genre) error {
+	if authors != nil {
+		if err := tx.Model(book).Association("Authors").Replace(authors); err != nil {
+			return err
+		}
+	}
+	if publishers != nil {
+		if err := tx.Model(book).Association("Publishers").Replace(publishers); err != nil {
+			return err
+		}
+	}
+	if genres != nil {
+		if err := tx.Model(book).Association("Genres").Replace(genres); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *bookRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Book{}, id).Error
 }
 
-func (r *bookRepository) Count() (int64, error) {
+func (r *bookRepository) DeleteWithTx(tx *gorm.DB, id uint) error {
+	return tx.Delete(&models.Book{}, id).Error
+}
+
+// FindDeleted - isi trash, yang terakhir dihapus lebih dulu
+func (r *bookRepository) FindDeleted(limit, offset int) ([]models.Book, error) {
+	var books []models.Book
+	err := withRelations(r.db.Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC, id ASC").
+		Limit(limit).Offset(offset).
+		Find(&books).Error
+	return books, err
+}
+
+func (r *bookRepository) CountDeleted() (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Book{}).Where("deleted_at IS NOT NULL").Count(&count).Error
+	return count, err
+}
+
+// FindDeletedByIDWithLock - LOCK buku yang ada di trash, ErrRecordNotFound jika buku tidak di trash
+func (r *bookRepository) FindDeletedByIDWithLock(tx *gorm.DB, id uint) (*models.Book, error) {
+	var book models.Book
+	err := forUpdate(tx.Unscoped()).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&book).Error
+	if err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+func (r *bookRepository) RestoreWithTx(tx *gorm.DB, id uint) error {
+	return tx.Unscoped().Model(&models.Book{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// PurgeWithTx - hapus permanen buku beserta copy, hold dan relasinya. Peminjaman tidak
+// ikut dihapus, jadi pemanggil harus memastikan buku tidak punya riwayat peminjaman.
+func (r *bookRepository) PurgeWithTx(tx *gorm.DB, id uint) error {
+	for _, table := range []string{"reservations", "book_copies", "book_authors", "book_publishers", "book_genres"} {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE book_id = ?", id).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Delete(&models.Book{}, id).Error
+}
+
+func (r *bookRepository) Count(filter models.BookFilter) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Book{}).Count(&count).Error
+	err := r.filtered(filter).Count(&count).Error
 	return count, err
+}
+
+// filtered - query dengan semua kondisi filter, dipakai bersama oleh FindAll dan Count
+func (r *bookRepository) filtered(filter models.BookFilter) *gorm.DB {
+	query := r.db.Model(&models.Book{})
+
+	if filter.Query != "" {
+		if r.fullText {
+			query = query.Where(bookSearchVector+" @@ websearch_to_tsquery('simple', ?)", filter.Query)
+		} else {
+			// Fallback: setiap kata harus muncul di salah satu kolom
+			for _, term := range strings.Fields(strings.ToLower(filter.Query)) {
+				like := "%" + term + "%"
+				query = query.Where("(LOWER(title) LIKE ? OR LOWER(author) LIKE ? OR LOWER(description) LIKE ?)", like, like, like)
+			}
+		}
+	}
+	if filter.Author != "" {
+		query = query.Where("LOWER(author) LIKE ?", "%"+strings.ToLower(filter.Author)+"%")
+	}
+	if filter.ISBN != "" {
+		query = query.Where("isbn = ?", filter.ISBN)
+	}
+	if filter.AuthorID != 0 {
+		query = query.Where("id IN (?)", r.bookIDs("book_authors", "author_id", filter.AuthorID))
+	}
+	if filter.PublisherID != 0 {
+		query = query.Where("id IN (?)", r.bookIDs("book_publishers", "publisher_id", filter.PublisherID))
+	}
+	if filter.GenreID != 0 {
+		query = query.Where("id IN (?)", r.bookIDs("book_genres", "genre_id", filter.GenreID))
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			query = query.Where("stock > 0")
+		} else {
+			query = query.Where("stock <= 0")
+		}
+	}
+
+	return query
+}
+
+// bookIDs - subquery book_id dari join table relasi
+func (r *bookRepository) bookIDs(table, column string, id uint) *gorm.DB {
+	return r.db.Session(&gorm.Session{NewDB: true}).Table(table).Select("book_id").Where(column+" = ?", id)
+}
+
+// withRelations - preload author, publisher dan genre untuk response buku
+func withRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Authors").Preload("Publishers").Preload("Genres")
+}
+
+// order - urutan hasil. Tanpa sort eksplisit, hasil search diurutkan berdasarkan relevance.
+// Semua kolom digabung jadi satu ekspresi ORDER BY karena relevance butuh parameter query.
+func (r *bookRepository) order(query *gorm.DB, filter models.BookFilter) *gorm.DB {
+	var parts []string
+	var vars []interface{}
+
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = []models.SortField{{Field: "relevance", Desc: true}}
+	}
+
+	for _, field := range sort {
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+
+		if field.Field == "relevance" {
+			if filter.Query == "" {
+				continue
+			}
+			if r.fullText {
+				parts = append(parts, "ts_rank("+bookSearchVector+", websearch_to_tsquery('simple', ?)) "+direction)
+				vars = append(vars, filter.Query)
+			} else {
+				// Fallback: judul yang cocok dianggap lebih relevan
+				parts = append(parts, "CASE WHEN LOWER(title) LIKE ? THEN 1 ELSE 0 END "+direction)
+				vars = append(vars, "%"+strings.ToLower(filter.Query)+"%")
+			}
+			continue
+		}
+
+		if column, ok := bookSortColumns[field.Field]; ok {
+			parts = append(parts, column+" "+direction)
+		}
+	}
+
+	// Tie-breaker supaya pagination stabil
+	parts = append(parts, "id ASC")
+
+	return query.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(parts, ", "), Vars: vars}})
 }
\ No newline at end of file
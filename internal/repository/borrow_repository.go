@@ -2,20 +2,32 @@ package repository
 
 import (
 	"book-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// activeBorrowStatuses - peminjaman yang bukunya belum dikembalikan
+var activeBorrowStatuses = []models.BorrowStatus{models.BorrowStatusBorrowed, models.BorrowStatusOverdue}
+
 type BorrowRepository interface {
 	Create(borrow *models.Borrow) error
 	CreateWithTx(tx *gorm.DB, borrow *models.Borrow) error
 	FindByID(id uint) (*models.Borrow, error)
 	FindByIDWithLock(tx *gorm.DB ,id uint) (*models.Borrow, error)
+	FindAll(filter models.BorrowFilter, limit, offset int) ([]models.Borrow, error)
 	FindByUserID(userID uint, limit, offset int) ([]models.Borrow, error)
+	FindActiveByUserIDWithTx(tx *gorm.DB, userID uint) ([]models.Borrow, error)
+	FindActiveByCopyIDWithLock(tx *gorm.DB, copyID uint) (*models.Borrow, error)
 	Update(borrow *models.Borrow) error
 	UpdateWithTx(tx *gorm.DB, borrow *models.Borrow) error
 	CountByUserID(userID uint) (int64, error)
+	CountActiveByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error)
+	CountByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error)
+	MarkOverdueWithTx(tx *gorm.DB, now time.Time) ([]models.Borrow, error)
+	FindOverdue(now time.Time, limit, offset int) ([]models.Borrow, error)
+	CountOverdue(now time.Time) (int64, error)
 }
 
 type borrowRepository struct {
@@ -36,7 +48,7 @@ func (r *borrowRepository) CreateWithTx(tx *gorm.DB,borrow *models.Borrow) error
 
 func (r *borrowRepository) FindByID(id uint) (*models.Borrow, error) {
 	var borrow models.Borrow
-	err := r.db.Preload("User").Preload("Book").First(&borrow, id).Error
+	err := r.db.Preload("User").Preload("Book").Preload("Copy").First(&borrow, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,17 +57,48 @@ func (r *borrowRepository) FindByID(id uint) (*models.Borrow, error) {
 
 func (r *borrowRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.Borrow, error) {
 	var borrow models.Borrow
-	err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&borrow, id).Error
+	err := forUpdate(tx).First(&borrow, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &borrow, nil
 }
 
+// FindAll - peminjaman sesuai filter, urut berdasarkan id. Buku dan copy yang sudah
+// dihapus tetap di-preload supaya riwayat lama tetap lengkap.
+func (r *borrowRepository) FindAll(filter models.BorrowFilter, limit, offset int) ([]models.Borrow, error) {
+	var borrows []models.Borrow
+	query := r.db.Model(&models.Borrow{})
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.BookID != 0 {
+		query = query.Where("book_id = ?", filter.BookID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.OverdueAt != nil {
+		query = query.Where("status IN ? AND due_date < ?", activeBorrowStatuses, *filter.OverdueAt)
+	}
+
+	err := query.
+		Preload("Book", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Copy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("User").
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&borrows).Error
+	return borrows, err
+}
+
 func (r *borrowRepository) FindByUserID(userID uint, limit, offset int) ([]models.Borrow, error) {
 	var borrows []models.Borrow
 	err := r.db.Where("user_id = ?", userID).
 		Preload("Book").
+		Preload("Copy").
 		Preload("User").
 		Order("created_at DESC").
 		Limit(limit).
@@ -64,6 +107,27 @@ func (r *borrowRepository) FindByUserID(userID uint, limit, offset int) ([]model
 	return borrows, err
 }
 
+// FindActiveByUserIDWithTx - semua peminjaman user yang belum dikembalikan
+func (r *borrowRepository) FindActiveByUserIDWithTx(tx *gorm.DB, userID uint) ([]models.Borrow, error) {
+	var borrows []models.Borrow
+	err := tx.Where("user_id = ? AND status IN ?", userID, activeBorrowStatuses).
+		Order("due_date ASC").
+		Find(&borrows).Error
+	return borrows, err
+}
+
+// FindActiveByCopyIDWithLock - LOCK peminjaman yang sedang memegang sebuah copy
+func (r *borrowRepository) FindActiveByCopyIDWithLock(tx *gorm.DB, copyID uint) (*models.Borrow, error) {
+	var borrow models.Borrow
+	err := forUpdate(tx).
+		Where("copy_id = ? AND status IN ?", copyID, activeBorrowStatuses).
+		First(&borrow).Error
+	if err != nil {
+		return nil, err
+	}
+	return &borrow, nil
+}
+
 func (r *borrowRepository) Update(borrow *models.Borrow) error {
 	return r.db.Save(borrow).Error
 }
@@ -76,4 +140,55 @@ func (r *borrowRepository) CountByUserID(userID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&models.Borrow{}).Where("user_id = ?", userID).Count(&count).Error
 	return count, err
+}
+
+// CountActiveByBookIDWithTx - jumlah peminjaman buku yang belum dikembalikan
+func (r *borrowRepository) CountActiveByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Borrow{}).Where("book_id = ? AND status IN ?", bookID, activeBorrowStatuses).Count(&count).Error
+	return count, err
+}
+
+// CountByBookIDWithTx - semua riwayat peminjaman buku, termasuk yang sudah di-soft delete
+func (r *borrowRepository) CountByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error) {
+	var count int64
+	err := tx.Unscoped().Model(&models.Borrow{}).Where("book_id = ?", bookID).Count(&count).Error
+	return count, err
+}
+
+// MarkOverdueWithTx - ubah status semua peminjaman yang lewat DueDate menjadi overdue,
+// mengembalikan peminjaman yang berubah (UPDATE ... RETURNING)
+func (r *borrowRepository) MarkOverdueWithTx(tx *gorm.DB, now time.Time) ([]models.Borrow, error) {
+	var borrows []models.Borrow
+	err := tx.Model(&borrows).
+		Clauses(clause.Returning{}).
+		Where("status = ? AND due_date < ?", models.BorrowStatusBorrowed, now).
+		Update("status", models.BorrowStatusOverdue).Error
+	return borrows, err
+}
+
+// FindOverdue - peminjaman yang belum dikembalikan dan sudah lewat DueDate,
+// termasuk yang belum sempat ditandai oleh worker
+func (r *borrowRepository) FindOverdue(now time.Time, limit, offset int) ([]models.Borrow, error) {
+	var borrows []models.Borrow
+	err := r.overdueQuery(now).
+		Preload("Book").
+		Preload("Copy").
+		Preload("User").
+		Order("due_date ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&borrows).Error
+	return borrows, err
+}
+
+func (r *borrowRepository) CountOverdue(now time.Time) (int64, error) {
+	var count int64
+	err := r.overdueQuery(now).Count(&count).Error
+	return count, err
+}
+
+func (r *borrowRepository) overdueQuery(now time.Time) *gorm.DB {
+	return r.db.Model(&models.Borrow{}).
+		Where("status IN ? AND due_date < ?", activeBorrowStatuses, now)
 }
\ No newline at end of file
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"book-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type FineRepository interface {
+	CreateWithTx(tx *gorm.DB, fine *models.Fine) error
+	FindByUserID(userID uint, limit, offset int) ([]models.Fine, error)
+	CountByUserID(userID uint) (int64, error)
+	BalanceByUserID(userID uint) (int64, error)
+	BalanceByUserIDWithTx(tx *gorm.DB, userID uint) (int64, error)
+}
+
+type fineRepository struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) FineRepository {
+	return &fineRepository{db: db}
+}
+
+func (r *fineRepository) CreateWithTx(tx *gorm.DB, fine *models.Fine) error {
+	return tx.Create(fine).Error
+}
+
+func (r *fineRepository) FindByUserID(userID uint, limit, offset int) ([]models.Fine, error) {
+	var fines []models.Fine
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&fines).Error
+	return fines, err
+}
+
+func (r *fineRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Fine{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// BalanceByUserID - saldo denda outstanding (jumlah seluruh entri ledger)
+func (r *fineRepository) BalanceByUserID(userID uint) (int64, error) {
+	return r.balance(r.db, userID)
+}
+
+func (r *fineRepository) BalanceByUserIDWithTx(tx *gorm.DB, userID uint) (int64, error) {
+	return r.balance(tx, userID)
+}
+
+func (r *fineRepository) balance(db *gorm.DB, userID uint) (int64, error) {
+	var balance int64
+	err := db.Model(&models.Fine{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", userID).
+		Scan(&balance).Error
+	return balance, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"book-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type GenreRepository interface {
+	Create(genre *models.Genre) error
+	FindAll(query string, limit, offset int) ([]models.Genre, error)
+	Count(query string) (int64, error)
+	FindByID(id uint) (*models.Genre, error)
+	FindByNameKey(nameKey string) (*models.Genre, error)
+	FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Genre, error)
+	Update(genre *models.Genre) error
+	Delete(id uint) error
+	CountBooks(id uint) (int64, error)
+}
+
+type genreRepository struct {
+	db *gorm.DB
+}
+
+func NewGenreRepository(db *gorm.DB) GenreRepository {
+	return &genreRepository{db: db}
+}
+
+func (r *genreRepository) Create(genre *models.Genre) error {
+	return r.db.Create(genre).Error
+}
+
+func (r *genreRepository) FindAll(query string, limit, offset int) ([]models.Genre, error) {
+	var genres []models.Genre
+	err := r.filtered(query).Order("name ASC, id ASC").Limit(limit).Offset(offset).Find(&genres).Error
+	return genres, err
+}
+
+func (r *genreRepository) Count(query string) (int64, error) {
+	var count int64
+	err := r.filtered(query).Count(&count).Error
+	return count, err
+}
+
+// filtered - query berisi substring nama (case-insensitive)
+func (r *genreRepository) filtered(query string) *gorm.DB {
+	db := r.db.Model(&models.Genre{})
+	if query != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(query)+"%")
+	}
+	return db
+}
+
+func (r *genreRepository) FindByID(id uint) (*models.Genre, error) {
+	var genre models.Genre
+	err := r.db.First(&genre, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+func (r *genreRepository) FindByNameKey(nameKey string) (*models.Genre, error) {
+	var genre models.Genre
+	err := r.db.Where("name_key = ?", nameKey).First(&genre).Error
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+func (r *genreRepository) FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Genre, error) {
+	var genres []models.Genre
+	err := tx.Where("id IN ?", ids).Find(&genres).Error
+	return genres, err
+}
+
+func (r *genreRepository) Update(genre *models.Genre) error {
+	return r.db.Save(genre).Error
+}
+
+func (r *genreRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Genre{}, id).Error
+}
+
+// CountBooks - jumlah buku (yang belum dihapus) dengan genre ini
+func (r *genreRepository) CountBooks(id uint) (int64, error) {
+	var count int64
+	err := r.db.Table("book_genres").
+		Joins("JOIN books ON books.id = book_genres.book_id AND books.deleted_at IS NULL").
+		Where("book_genres.genre_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
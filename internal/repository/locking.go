@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// forUpdate - SELECT ... FOR UPDATE untuk baris yang akan diubah di transaction yang sama.
+// SQLite tidak punya row lock dan menolak FOR UPDATE; di sana transaction sudah memegang
+// lock tulis seluruh database sejak BEGIN IMMEDIATE (lihat database.Open), sehingga
+// urutan baca-cek-tulis tetap tidak bisa diselingi transaction lain.
+func forUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "sqlite" {
+		return tx
+	}
+	return tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
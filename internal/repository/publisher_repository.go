@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"book-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type PublisherRepository interface {
+	Create(publisher *models.Publisher) error
+	FindAll(query string, limit, offset int) ([]models.Publisher, error)
+	Count(query string) (int64, error)
+	FindByID(id uint) (*models.Publisher, error)
+	FindByNameKey(nameKey string) (*models.Publisher, error)
+	FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Publisher, error)
+	Update(publisher *models.Publisher) error
+	Delete(id uint) error
+	CountBooks(id uint) (int64, error)
+}
+
+type publisherRepository struct {
+	db *gorm.DB
+}
+
+func NewPublisherRepository(db *gorm.DB) PublisherRepository {
+	return &publisherRepository{db: db}
+}
+
+func (r *publisherRepository) Create(publisher *models.Publisher) error {
+	return r.db.Create(publisher).Error
+}
+
+func (r *publisherRepository) FindAll(query string, limit, offset int) ([]models.Publisher, error) {
+	var publishers []models.Publisher
+	err := r.filtered(query).Order("name ASC, id ASC").Limit(limit).Offset(offset).Find(&publishers).Error
+	return publishers, err
+}
+
+func (r *publisherRepository) Count(query string) (int64, error) {
+	var count int64
+	err := r.filtered(query).Count(&count).Error
+	return count, err
+}
+
+// filtered - query berisi substring nama (case-insensitive)
+func (r *publisherRepository) filtered(query string) *gorm.DB {
+	db := r.db.Model(&models.Publisher{})
+	if query != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(query)+"%")
+	}
+	return db
+}
+
+func (r *publisherRepository) FindByID(id uint) (*models.Publisher, error) {
+	var publisher models.Publisher
+	err := r.db.First(&publisher, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) FindByNameKey(nameKey string) (*models.Publisher, error) {
+	var publisher models.Publisher
+	err := r.db.Where("name_key = ?", nameKey).First(&publisher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepository) FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Publisher, error) {
+	var publishers []models.Publisher
+	err := tx.Where("id IN ?", ids).Find(&publishers).Error
+	return publishers, err
+}
+
+func (r *publisherRepository) Update(publisher *models.Publisher) error {
+	return r.db.Save(publisher).Error
+}
+
+func (r *publisherRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Publisher{}, id).Error
+}
+
+// CountBooks - jumlah buku (yang belum dihapus) dari publisher ini
+func (r *publisherRepository) CountBooks(id uint) (int64, error) {
+	var count int64
+	err := r.db.Table("book_publishers").
+		Joins("JOIN books ON books.id = book_publishers.book_id AND books.deleted_at IS NULL").
+		Where("book_publishers.publisher_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
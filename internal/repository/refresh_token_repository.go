@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"book-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository interface {
+	CreateWithTx(tx *gorm.DB, token *models.RefreshToken) error
+	FindByHashWithLock(tx *gorm.DB, tokenHash string) (*models.RefreshToken, error)
+	FindByAccessJTI(jti string) (*models.RefreshToken, error)
+	UpdateWithTx(tx *gorm.DB, token *models.RefreshToken) error
+	RevokeFamilyWithTx(tx *gorm.DB, familyID string, revokedAt time.Time) error
+	RevokeByAccessJTI(jti string, revokedAt time.Time) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) CreateWithTx(tx *gorm.DB, token *models.RefreshToken) error {
+	return tx.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHashWithLock(tx *gorm.DB, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := forUpdate(tx).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) FindByAccessJTI(jti string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Where("access_jti = ?", jti).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) UpdateWithTx(tx *gorm.DB, token *models.RefreshToken) error {
+	return tx.Save(token).Error
+}
+
+func (r *refreshTokenRepository) RevokeFamilyWithTx(tx *gorm.DB, familyID string, revokedAt time.Time) error {
+	return tx.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *refreshTokenRepository) RevokeByAccessJTI(jti string, revokedAt time.Time) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("access_jti = ? AND revoked_at IS NULL", jti).
+		Update("revoked_at", revokedAt).Error
+}
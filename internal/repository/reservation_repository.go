@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"book-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var activeReservationStatuses = []models.ReservationStatus{
+	models.ReservationStatusWaiting,
+	models.ReservationStatusReady,
+}
+
+type ReservationRepository interface {
+	CreateWithTx(tx *gorm.DB, reservation *models.Reservation) error
+	FindByIDWithLock(tx *gorm.DB, id uint) (*models.Reservation, error)
+	FindActiveByUserAndBookWithTx(tx *gorm.DB, userID, bookID uint) (*models.Reservation, error)
+	FindNextWaitingWithTx(tx *gorm.DB, bookID uint) (*models.Reservation, error)
+	FindActiveByBookWithTx(tx *gorm.DB, bookID uint) ([]models.Reservation, error)
+	FindExpiredReady(now time.Time) ([]models.Reservation, error)
+	FindByUserID(userID uint, limit, offset int) ([]models.Reservation, error)
+	UpdateWithTx(tx *gorm.DB, reservation *models.Reservation) error
+	CountActiveByBookWithTx(tx *gorm.DB, bookID, excludeUserID uint) (int64, error)
+	CountByUserID(userID uint) (int64, error)
+}
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+func (r *reservationRepository) CreateWithTx(tx *gorm.DB, reservation *models.Reservation) error {
+	return tx.Create(reservation).Error
+}
+
+func (r *reservationRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := forUpdate(tx).First(&reservation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *reservationRepository) FindActiveByUserAndBookWithTx(tx *gorm.DB, userID, bookID uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := forUpdate(tx).
+		Where("user_id = ? AND book_id = ? AND status IN ?", userID, bookID, activeReservationStatuses).
+		First(&reservation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// FindNextWaitingWithTx - hold paling awal (FIFO) yang masih menunggu untuk sebuah buku
+func (r *reservationRepository) FindNextWaitingWithTx(tx *gorm.DB, bookID uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := forUpdate(tx).
+		Where("book_id = ? AND status = ?", bookID, models.ReservationStatusWaiting).
+		Order("created_at ASC, id ASC").
+		First(&reservation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// FindActiveByBookWithTx - LOCK semua hold aktif (waiting dan ready) untuk sebuah buku
+func (r *reservationRepository) FindActiveByBookWithTx(tx *gorm.DB, bookID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := forUpdate(tx).
+		Where("book_id = ? AND status IN ?", bookID, activeReservationStatuses).
+		Order("id ASC").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// FindExpiredReady - hold yang sudah siap tapi tidak diambil sampai batas waktu
+func (r *reservationRepository) FindExpiredReady(now time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Where("status = ? AND pickup_deadline < ?", models.ReservationStatusReady, now).
+		Order("pickup_deadline ASC").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r *reservationRepository) FindByUserID(userID uint, limit, offset int) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Where("user_id = ?", userID).
+		Preload("Book").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r *reservationRepository) UpdateWithTx(tx *gorm.DB, reservation *models.Reservation) error {
+	return tx.Save(reservation).Error
+}
+
+// CountActiveByBookWithTx - jumlah hold aktif untuk buku milik user lain
+func (r *reservationRepository) CountActiveByBookWithTx(tx *gorm.DB, bookID, excludeUserID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Reservation{}).
+		Where("book_id = ? AND user_id <> ? AND status IN ?", bookID, excludeUserID, activeReservationStatuses).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *reservationRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Reservation{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
@@ -10,6 +10,13 @@ type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uint) (*models.User, error)
+	FindByIDWithLock(tx *gorm.DB, id uint) (*models.User, error)
+	FindAll(limit, offset int) ([]models.User, error)
+	Update(user *models.User) error
+	UpdateWithTx(tx *gorm.DB, user *models.User) error
+	Count() (int64, error)
+	CountByRole(role models.Role) (int64, error)
+	FindByRoleWithLock(tx *gorm.DB, role models.Role) ([]models.User, error)
 }
 
 type userRepository struct {
@@ -43,4 +50,55 @@ func (r *userRepository) FindByID(id uint) (*models.User, error){
 	}
 
 	return &user, nil
+}
+// Implement method FindByIDWithLock - lock row user (SELECT ... FOR UPDATE) di dalam transaction
+func (r *userRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.User, error) {
+	var user models.User
+	err := forUpdate(tx).First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+// Implement method FindAll
+func (r *userRepository) FindAll(limit, offset int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Order("id ASC").Limit(limit).Offset(offset).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+// Implement method Update
+func (r *userRepository) Update(user *models.User) error {
+	return r.db.Save(user).Error
+}
+// Implement method UpdateWithTx
+func (r *userRepository) UpdateWithTx(tx *gorm.DB, user *models.User) error {
+	return tx.Save(user).Error
+}
+// Implement method Count
+func (r *userRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+// Implement method CountByRole
+func (r *userRepository) CountByRole(role models.Role) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+// Implement method FindByRoleWithLock - lock semua user dengan role ini, urut ID supaya
+// transaction yang berjalan bersamaan mengambil lock dengan urutan yang sama
+func (r *userRepository) FindByRoleWithLock(tx *gorm.DB, role models.Role) ([]models.User, error) {
+	var users []models.User
+	err := forUpdate(tx).Where("role = ?", role).Order("id ASC").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
 }
\ No newline at end of file
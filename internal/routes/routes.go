@@ -1,10 +1,13 @@
 package routes
 
 import (
+	"log/slog"
 	"net/http"
 
 	"book-api/internal/handlers"
 	"book-api/internal/middlewares"
+	"book-api/internal/models"
+	"book-api/internal/utils"
 
 	_ "book-api/docs"
 
@@ -13,15 +16,20 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func SetupRoutes(authHandler *handlers.AuthHandler, bookHandler *handlers.BookHandler, borrowHandler *handlers.BorrowHandler, jwtSecret string) *chi.Mux {
+func SetupRoutes(authHandler *handlers.AuthHandler, bookHandler *handlers.BookHandler, borrowHandler *handlers.BorrowHandler, userHandler *handlers.UserHandler, reservationHandler *handlers.ReservationHandler, fineHandler *handlers.FineHandler, copyHandler *handlers.BookCopyHandler, importHandler *handlers.BookImportHandler, exportHandler *handlers.ExportHandler, authorHandler *handlers.AuthorHandler, publisherHandler *handlers.PublisherHandler, genreHandler *handlers.GenreHandler, coverHandler *handlers.BookCoverHandler, trashHandler *handlers.BookTrashHandler, auditHandler *handlers.AuditHandler, jwtSecret string, tokenChecker middlewares.TokenChecker, errorFormat utils.ErrorFormat, logger *slog.Logger, requestMetrics middlewares.RequestObserver, metricsHandler http.Handler) *chi.Mux {
 	r := chi.NewRouter()
 
+	authMiddleware := middlewares.AuthMiddleware(jwtSecret, tokenChecker)
+
 	//Middleware global
-	r.Use(middleware.Logger)		// Log semua request
-	r.Use(middleware.Recoverer)		// Recover dari semua panic
 	r.Use(middleware.RequestID)		// Add request ID untuk memberikan id pada log.
 	r.Use(middleware.RealIP)		// Get real IP
-	r.Use(middleware.AllowContentType("application/json","application/json; charset=utf-8")) // Only accept JSON
+	r.Use(middlewares.RequestLogger(logger))	// Log semua request (slog), setelah RequestID
+	if requestMetrics != nil {
+		r.Use(middlewares.RequestMetrics(requestMetrics))	// Metric Prometheus per route
+	}
+	r.Use(middleware.Recoverer)		// Recover dari semua panic, tercatat sebagai 500
+	r.Use(middlewares.ErrorFormat(errorFormat))	// problem+json atau format error lama, setelah RequestID
 
 	// Healt check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request){
@@ -29,40 +37,177 @@ func SetupRoutes(authHandler *handlers.AuthHandler, bookHandler *handlers.BookHa
 		w.Write([]byte("OK"))
 	})
 
+	// Metric Prometheus, kecuali jika dipisah ke METRICS_PORT
+	if metricsHandler != nil {
+		r.Handle("/metrics", metricsHandler)
+	}
+
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 	))
 
 	// API Routes
 	r.Route("/api/v1", func(r chi.Router) {
-		// Auth routes (public)
-		r.Post("/register", authHandler.Register)
-		r.Post("/login", authHandler.Login)
+		// Bulk import menerima CSV / JSON Lines, bukan JSON
+		r.With(
+			authMiddleware,
+			middlewares.RequirePermission(models.PermissionManageBooks),
+			middleware.AllowContentType(handlers.ImportContentTypes...),
+		).Post("/books/import", importHandler.ImportBooks)	// POST /api/v1/books/import
+
+		// Upload cover memakai multipart/form-data
+		r.With(
+			authMiddleware,
+			middlewares.RequirePermission(models.PermissionManageBooks),
+			middleware.AllowContentType("multipart/form-data"),
+		).Post("/books/{id}/cover", coverHandler.UploadCover)	// POST /api/v1/books/1/cover
+
+		// Partial update memakai JSON Merge Patch
+		r.With(
+			authMiddleware,
+			middlewares.RequirePermission(models.PermissionManageBooks),
+			middleware.AllowContentType(handlers.PatchBookContentTypes...),
+		).Patch("/books/{id}", bookHandler.PatchBook)	// PATCH /api/v1/books/1
 
-		// Book routes (akan ditambahkan auth middleware nantinya)
-		r.Route("/books", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AllowContentType("application/json","application/json; charset=utf-8")) // Only accept JSON
+
+			// Auth routes (public)
+			r.Post("/register", authHandler.Register)
+			r.Post("/login", authHandler.Login)
+			r.Post("/token/refresh", authHandler.RefreshToken)
+			r.With(authMiddleware).Post("/logout", authHandler.Logout)
+
+			// Book routes (akan ditambahkan auth middleware nantinya)
+			r.Route("/books", func(r chi.Router) {
 			
-			// Public endpoints - siapa aja bisa akses
-			r.Get("/", bookHandler.GetAllBooks)			// GET /api/v1/books
-			r.Get("/{id}", bookHandler.GetBookByID)		// GET /api/v1/books/1
+				// Public endpoints - siapa aja bisa akses
+				r.Get("/", bookHandler.GetAllBooks)			// GET /api/v1/books
+				r.Get("/{id}", bookHandler.GetBookByID)		// GET /api/v1/books/1
+				r.Get("/isbn/{isbn}", bookHandler.GetBookByISBN)	// GET /api/v1/books/isbn/978-0-13-235088-4
+				r.Get("/{id}/cover", coverHandler.GetCover)		// GET /api/v1/books/1/cover?size=small
 			
-			// Protected endpoints - harus login dulu
-			r.Group(func(r chi.Router){
-				r.Use(middlewares.AuthMiddleware(jwtSecret))
-				r.Post("/", bookHandler.CreateBook)			// POST /api/v1/books
-				r.Put("/{id}", bookHandler.UpdateBook)		// PUT /api/v1/books/1
-				r.Delete("/{id}", bookHandler.DeleteBook)	// DELETE /api/v1/books/1
+				// Protected endpoints - harus login sebagai librarian/admin
+				r.Group(func(r chi.Router){
+					r.Use(authMiddleware)
+					r.Use(middlewares.RequirePermission(models.PermissionManageBooks))
+					r.Post("/", bookHandler.CreateBook)			// POST /api/v1/books
+					r.Post("/lookup", bookHandler.LookupBook)	// POST /api/v1/books/lookup
+					r.Put("/{id}", bookHandler.UpdateBook)		// PUT /api/v1/books/1
+					r.Delete("/{id}", bookHandler.DeleteBook)	// DELETE /api/v1/books/1
+					r.Delete("/{id}/cover", coverHandler.DeleteCover)	// DELETE /api/v1/books/1/cover
+					r.Get("/trash", trashHandler.GetTrashedBooks)				// GET /api/v1/books/trash
+					r.Post("/trash/{id}/restore", trashHandler.RestoreBook)	// POST /api/v1/books/trash/1/restore
+					r.Delete("/trash/{id}", trashHandler.PurgeBook)			// DELETE /api/v1/books/trash/1
+					r.Get("/{id}/history", auditHandler.GetBookHistory)		// GET /api/v1/books/1/history
+					r.Get("/{id}/copies", copyHandler.GetBookCopies)	// GET /api/v1/books/1/copies
+					r.Post("/{id}/copies", copyHandler.AddCopy)		// POST /api/v1/books/1/copies
+				})
+
+				// Hold queue - member yang login
+				r.Group(func(r chi.Router){
+					r.Use(authMiddleware)
+					r.Use(middlewares.RequirePermission(models.PermissionBorrowBooks))
+					r.Post("/{id}/holds", reservationHandler.PlaceHold)		// POST /api/v1/books/1/holds
+					r.Delete("/{id}/holds", reservationHandler.CancelHold)	// DELETE /api/v1/books/1/holds
+				})
 			})
-		})
 
-		r.Route("/borrow", func(r chi.Router) {
-			r.Use(middlewares.AuthMiddleware(jwtSecret))
-			r.Post("/", borrowHandler.BorrowBook)
-			r.Post("/return", borrowHandler.ReturnBook)
-			r.Get("/me", borrowHandler.GetMyBorrows)
-			r.Get("/{id}", borrowHandler.GetBorrowByID)
+			// Author, publisher dan genre - baca public, ubah librarian/admin
+			r.Route("/authors", func(r chi.Router) {
+				r.Get("/", authorHandler.GetAuthors)				// GET /api/v1/authors
+				r.Get("/{id}", authorHandler.GetAuthorByID)			// GET /api/v1/authors/1
+				r.Get("/{id}/books", authorHandler.GetAuthorBooks)	// GET /api/v1/authors/1/books
+
+				r.Group(func(r chi.Router){
+					r.Use(authMiddleware)
+					r.Use(middlewares.RequirePermission(models.PermissionManageBooks))
+					r.Post("/", authorHandler.CreateAuthor)
+					r.Put("/{id}", authorHandler.UpdateAuthor)
+					r.Delete("/{id}", authorHandler.DeleteAuthor)
+				})
+			})
+
+			r.Route("/publishers", func(r chi.Router) {
+				r.Get("/", publisherHandler.GetPublishers)
+				r.Get("/{id}", publisherHandler.GetPublisherByID)
+				r.Get("/{id}/books", publisherHandler.GetPublisherBooks)
+
+				r.Group(func(r chi.Router){
+					r.Use(authMiddleware)
+					r.Use(middlewares.RequirePermission(models.PermissionManageBooks))
+					r.Post("/", publisherHandler.CreatePublisher)
+					r.Put("/{id}", publisherHandler.UpdatePublisher)
+					r.Delete("/{id}", publisherHandler.DeletePublisher)
+				})
+			})
+
+			r.Route("/genres", func(r chi.Router) {
+				r.Get("/", genreHandler.GetGenres)
+				r.Get("/{id}", genreHandler.GetGenreByID)
+				r.Get("/{id}/books", genreHandler.GetGenreBooks)
+
+				r.Group(func(r chi.Router){
+					r.Use(authMiddleware)
+					r.Use(middlewares.RequirePermission(models.PermissionManageBooks))
+					r.Post("/", genreHandler.CreateGenre)
+					r.Put("/{id}", genreHandler.UpdateGenre)
+					r.Delete("/{id}", genreHandler.DeleteGenre)
+				})
+			})
+
+			r.Route("/borrow", func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(middlewares.RequirePermission(models.PermissionBorrowBooks))
+				r.Post("/", borrowHandler.BorrowBook)
+				r.Post("/return", borrowHandler.ReturnBook)
+				r.Get("/me", borrowHandler.GetMyBorrows)
+				r.Get("/{id}", borrowHandler.GetBorrowByID)
+				r.Post("/{id}/renew", borrowHandler.RenewBorrow)
+			})
+
+			// Copy fisik - librarian/admin
+			r.Route("/copies", func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(middlewares.RequirePermission(models.PermissionManageBooks))
+				r.Get("/barcode/{barcode}", copyHandler.GetCopyByBarcode)
+				r.Put("/{id}", copyHandler.UpdateCopy)
+			})
+
+			// Data milik user yang sedang login
+			r.Route("/me", func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Get("/holds", reservationHandler.GetMyHolds)
+				r.Get("/fines", fineHandler.GetMyFines)
+			})
+
+			// Daftar pinjaman terlambat - khusus admin
+			r.Route("/borrows", func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(middlewares.RequireRole(models.RoleAdmin))
+				r.Get("/overdue", borrowHandler.GetOverdueBorrows)
+			})
+
+			// Export katalog dan riwayat peminjaman - librarian/admin
+			r.Route("/export", func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.With(middlewares.RequirePermission(models.PermissionManageBooks)).Get("/books", exportHandler.ExportBooks)		// GET /api/v1/export/books
+				r.With(middlewares.RequirePermission(models.PermissionManageLoans)).Get("/borrows", exportHandler.ExportBorrows)	// GET /api/v1/export/borrows
+			})
+
+			// Admin routes - khusus admin
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(authMiddleware)
+				r.Use(middlewares.RequireRole(models.RoleAdmin))
+				r.Get("/users", userHandler.GetAllUsers)
+				r.Put("/users/{id}/role", userHandler.UpdateUserRole)
+				r.Get("/users/{id}/fines", fineHandler.GetUserFines)
+				r.Post("/users/{id}/fines", fineHandler.ChargeFine)
+				r.Post("/users/{id}/fines/payments", fineHandler.RecordPayment)
+				r.Post("/users/{id}/fines/waivers", fineHandler.WaiveFine)
+				r.Get("/audit-logs", auditHandler.SearchAuditLogs)	// GET /api/v1/admin/audit-logs?book_id=1
+			})
 		})
 	})
-
 	return r
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package services
+
+import "book-api/internal/models"
+
+// Actor - identitas user yang melakukan aksi, dipakai untuk cek kepemilikan data
+type Actor struct {
+	UserID uint
+	Role   models.Role
+}
+
+// CanManageLoans - staff (librarian/admin) boleh melihat dan mengelola peminjaman milik siapa pun
+func (a Actor) CanManageLoans() bool {
+	return a.Role.HasPermission(models.PermissionManageLoans)
+}
+
+// canAccessBorrow - member hanya boleh mengakses peminjaman miliknya sendiri
+func (a Actor) canAccessBorrow(borrow *models.Borrow) bool {
+	return borrow.UserID == a.UserID || a.CanManageLoans()
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"context"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditInfo - siapa dan request mana yang melakukan perubahan. Dibawa lewat context
+// dari handler sampai ke service yang mencatat audit log.
+type AuditInfo struct {
+	ActorID		*uint
+	RequestID	string
+}
+
+type auditContextKey struct{}
+
+// WithAuditInfo - context turunan yang membawa info audit
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, info)
+}
+
+// auditInfoFrom - context tanpa info audit berarti perubahan dilakukan sistem
+func auditInfoFrom(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditContextKey{}).(AuditInfo)
+	return info
+}
+
+// auditTrail - pencatat audit log yang dipakai bersama oleh service yang mengubah buku
+// dan peminjaman. Repository nil berarti audit tidak dicatat.
+type auditTrail struct {
+	repo repository.AuditLogRepository
+}
+
+// record - simpan entri di transaction yang sama dengan perubahannya, sehingga
+// perubahan yang di-rollback tidak meninggalkan entri
+func (a auditTrail) record(ctx context.Context, tx *gorm.DB, entity models.AuditEntity, entityID, bookID uint, action models.AuditAction, changes models.AuditChanges) error {
+	if a.repo == nil {
+		return nil
+	}
+
+	info := auditInfoFrom(ctx)
+	entry := &models.AuditLog{
+		EntityType:	entity,
+		EntityID:	entityID,
+		BookID:		&bookID,
+		Action:		action,
+		ActorID:	info.ActorID,
+		RequestID:	info.RequestID,
+		Changes:	changes,
+	}
+	return a.repo.CreateWithTx(tx, entry)
+}
+
+// recordBook - before nil untuk buku baru, after nil untuk buku yang dihapus
+func (a auditTrail) recordBook(ctx context.Context, tx *gorm.DB, bookID uint, action models.AuditAction, before, after map[string]interface{}) error {
+	return a.record(ctx, tx, models.AuditEntityBook, bookID, bookID, action, diffSnapshots(before, after))
+}
+
+// recordStock - perubahan stock buku di luar peminjaman (copy baru, status copy diubah
+// staff, hold yang batal atau expired). Tidak ada entri jika stock tidak berubah.
+func (a auditTrail) recordStock(ctx context.Context, tx *gorm.DB, bookID uint, stock models.AuditChanges) error {
+	if stock == nil {
+		return nil
+	}
+	return a.record(ctx, tx, models.AuditEntityBook, bookID, bookID, models.AuditActionUpdate, stock)
+}
+
+// recordBorrow - perubahan peminjaman dicatat juga di riwayat bukunya
+func (a auditTrail) recordBorrow(ctx context.Context, tx *gorm.DB, borrow *models.Borrow, action models.AuditAction, before map[string]interface{}, extra models.AuditChanges) error {
+	changes := diffSnapshots(before, borrowSnapshot(borrow))
+	for field, change := range extra {
+		changes[field] = change
+	}
+	return a.record(ctx, tx, models.AuditEntityBorrow, borrow.ID, borrow.BookID, action, changes)
+}
+
+// bookSnapshot - field buku yang dicatat di audit log. Relasi hanya ikut jika sudah di-load.
+func bookSnapshot(book *models.Book) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"title":		book.Title,
+		"author":		book.Author,
+		"isbn":			book.ISBN,
+		"description":	book.Description,
+		"stock":		book.Stock,
+		"version":		book.Version,
+		"cover_url":	book.CoverURL,
+	}
+	if book.Authors != nil {
+		ids := make([]uint, len(book.Authors))
+		for i, author := range book.Authors {
+			ids[i] = author.ID
+		}
+		snapshot["author_ids"] = ids
+	}
+	if book.Publishers != nil {
+		ids := make([]uint, len(book.Publishers))
+		for i, publisher := range book.Publishers {
+			ids[i] = publisher.ID
+		}
+		snapshot["publisher_ids"] = ids
+	}
+	if book.Genres != nil {
+		ids := make([]uint, len(book.Genres))
+		for i, genre := range book.Genres {
+			ids[i] = genre.ID
+		}
+		snapshot["genre_ids"] = ids
+	}
+	return snapshot
+}
+
+// borrowSnapshot - waktu disimpan sebagai string RFC 3339 supaya bisa dibandingkan
+// tanpa terpengaruh zona waktu dari database
+func borrowSnapshot(borrow *models.Borrow) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"user_id":			borrow.UserID,
+		"copy_id":			nil,
+		"status":			string(borrow.Status),
+		"due_date":			borrow.DueDate.UTC().Format(time.RFC3339),
+		"return_date":		nil,
+		"renewal_count":	borrow.RenewalCount,
+		"late_days":		borrow.LateDays,
+	}
+	if borrow.CopyID != nil {
+		snapshot["copy_id"] = *borrow.CopyID
+	}
+	if borrow.ReturnDate != nil {
+		snapshot["return_date"] = borrow.ReturnDate.UTC().Format(time.RFC3339)
+	}
+	return snapshot
+}
+
+// diffSnapshots - field yang nilainya berbeda antara dua snapshot. Field yang tidak ada
+// di salah satu snapshot dianggap null.
+func diffSnapshots(before, after map[string]interface{}) models.AuditChanges {
+	changes := models.AuditChanges{}
+	for field, old := range before {
+		if !reflect.DeepEqual(old, after[field]) {
+			changes[field] = models.AuditChange{Old: old, New: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok && value != nil {
+			changes[field] = models.AuditChange{Old: nil, New: value}
+		}
+	}
+	return changes
+}
+
+// stockChange - perubahan stock buku akibat peminjaman, pengembalian atau perubahan copy
+func stockChange(before, after int) models.AuditChanges {
+	if before == after {
+		return nil
+	}
+	return models.AuditChanges{"stock": {Old: before, New: after}}
+}
+
+// stockChangeWithTx - stock buku yang sudah di-LOCK setelah SyncBookStockWithTx,
+// dibandingkan dengan stock sebelum perubahan
+func stockChangeWithTx(tx *gorm.DB, bookRepo repository.BookRepository, bookID uint, before int) (models.AuditChanges, error) {
+	book, err := bookRepo.FindByIDWithLock(tx, bookID)
+	if err != nil {
+		return nil, bookNotFound(err)
+	}
+	return stockChange(before, book.Stock), nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/repository"
+)
+
+// AuditService - baca audit log. Entri ditulis oleh service yang melakukan perubahan
+// (lihat auditTrail), bukan lewat service ini.
+type AuditService interface {
+	GetBookHistory(bookID uint, page, pageSize int) ([]models.AuditLog, int64, error)
+	SearchAuditLogs(filter models.AuditFilter, page, pageSize int) ([]models.AuditLog, int64, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditService(auditRepo repository.AuditLogRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// GetBookHistory - perubahan buku dan peminjamannya, termasuk setelah buku di-purge
+func (s *auditService) GetBookHistory(bookID uint, page, pageSize int) ([]models.AuditLog, int64, error) {
+	return s.SearchAuditLogs(models.AuditFilter{BookID: bookID}, page, pageSize)
+}
+
+func (s *auditService) SearchAuditLogs(filter models.AuditFilter, page, pageSize int) ([]models.AuditLog, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	entries, err := s.auditRepo.Find(filter, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.auditRepo.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockAuditLogRepository
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) CreateWithTx(tx *gorm.DB, entry *models.AuditLog) error {
+	args := m.Called(tx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) Find(filter models.AuditFilter, limit, offset int) ([]models.AuditLog, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.AuditLog), args.Error(1)
+}
+
+func (m *MockAuditLogRepository) Count(filter models.AuditFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Test GetBookHistory - filter book_id dan pagination default
+func TestGetBookHistory(t *testing.T) {
+	mockRepo := new(MockAuditLogRepository)
+	service := NewAuditService(mockRepo)
+
+	entries := []models.AuditLog{{ID: 2, EntityType: models.AuditEntityBorrow, Action: models.AuditActionBorrow}}
+	mockRepo.On("Find", models.AuditFilter{BookID: 4}, 10, 0).Return(entries, nil)
+	mockRepo.On("Count", models.AuditFilter{BookID: 4}).Return(int64(1), nil)
+
+	result, total, err := service.GetBookHistory(4, 0, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entries, result)
+	assert.Equal(t, int64(1), total)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test diffSnapshots - hanya field yang berubah, field yang hilang dianggap null
+func TestDiffSnapshots(t *testing.T) {
+	before := map[string]interface{}{"title": "Old", "stock": 5, "author_ids": []uint{1, 2}}
+	after := map[string]interface{}{"title": "New", "stock": 5, "author_ids": []uint{1, 2}, "cover_url": "/c"}
+
+	changes := diffSnapshots(before, after)
+
+	assert.Equal(t, models.AuditChanges{
+		"title":		{Old: "Old", New: "New"},
+		"cover_url":	{Old: nil, New: "/c"},
+	}, changes)
+
+	deleted := diffSnapshots(before, nil)
+	assert.Len(t, deleted, 3)
+	assert.Nil(t, deleted["stock"].New)
+}
+
+// Test auditTrail - actor dan request ID diambil dari context
+func TestAuditTrail_RecordsActorAndRequestID(t *testing.T) {
+	mockRepo := new(MockAuditLogRepository)
+	trail := auditTrail{repo: mockRepo}
+
+	actorID := uint(9)
+	ctx := WithAuditInfo(context.Background(), AuditInfo{ActorID: &actorID, RequestID: "host/abc-000001"})
+
+	mockRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.EntityType == models.AuditEntityBook &&
+			entry.EntityID == 3 && *entry.BookID == 3 &&
+			*entry.ActorID == 9 &&
+			entry.RequestID == "host/abc-000001" &&
+			entry.Changes["stock"] == models.AuditChange{Old: 5, New: 0}
+	})).Return(nil)
+
+	err := trail.recordBook(ctx, nil, 3, models.AuditActionUpdate, map[string]interface{}{"stock": 5}, map[string]interface{}{"stock": 0})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
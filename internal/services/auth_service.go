@@ -1,30 +1,69 @@
 package services
 
 import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
 	"book-api/internal/models"
 	"book-api/internal/repository"
 	"book-api/internal/utils"
+	"context"
 	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken	= apperrors.Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+	ErrRefreshTokenReused	= apperrors.Unauthorized("refresh_token_reused", "refresh token reuse detected, session revoked")
+	ErrEmailRegistered		= apperrors.Conflict("email_registered", "email already registered")
+	ErrInvalidCredentials	= apperrors.Unauthorized("invalid_credentials", "invalid email or password")
 )
 
 type AuthService interface {
 	Register(name, email, password string) (*models.User, error)
-	Login(email, password, jwtSecret string) (string, error)
+	Login(ctx context.Context, email, password, jwtSecret string) (*TokenPair, error)
+	RefreshToken(ctx context.Context, refreshToken, jwtSecret string) (*TokenPair, error)
+	Logout(jti string) error
+	IsTokenActive(jti string) (bool, error)
+}
+
+// TokenPair - access token (JWT) dan refresh token yang diberikan ke client
+type TokenPair struct {
+	AccessToken		string
+	RefreshToken	string
+	ExpiresIn		int64
 }
 
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo	repository.UserRepository
+	tokenRepo	repository.RefreshTokenRepository
+	txManager	database.TransactionManager
+	accessTTL	time.Duration
+	refreshTTL	time.Duration
 }
 
-func NewAuthService(userRepo repository.UserRepository) AuthService {
-	return &authService{userRepo: userRepo}
+func NewAuthService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.RefreshTokenRepository,
+	txManager database.TransactionManager,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+) AuthService {
+	return &authService{
+		userRepo:	userRepo,
+		tokenRepo:	tokenRepo,
+		txManager:	txManager,
+		accessTTL:	accessTTL,
+		refreshTTL:	refreshTTL,
+	}
 }
 
 func (s *authService) Register(name, email, password string) (*models.User, error) {
 	// Validasi cek email sudah terdaftar
 	existingUser, _ := s.userRepo.FindByEmail(email)
 	if existingUser != nil {
-		return nil, errors.New("email already registered")
+		return nil, ErrEmailRegistered
 	}
 
 	// Hash password
@@ -38,6 +77,7 @@ func (s *authService) Register(name, email, password string) (*models.User, erro
 		Name: name,
 		Email: email,
 		Password: hashedPassword,
+		Role: models.RoleMember,
 	}
 
 	//Simpan user ke repository
@@ -48,23 +88,155 @@ func (s *authService) Register(name, email, password string) (*models.User, erro
 	return &newUser, nil
 }
 
-func (s *authService) Login(email, password, jwtSecret string) (string, error) {
+func (s *authService) Login(ctx context.Context, email, password, jwtSecret string) (*TokenPair, error) {
 	// Cari user berdasarkan email
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 
 	// Cek password
 	if !utils.CheckHashPassword(password, user.Password) {
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
+	}
+
+	// Login baru = token family baru
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate token JWT
-	token, err := utils.GenerateToken(user.ID, user.Email, jwtSecret)
+	var pair *TokenPair
+	err = s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		issued, _, err := s.issueTokens(tx, user, familyID, jwtSecret)
+		if err != nil {
+			return err
+		}
+		pair = issued
+		return nil
+	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+func (s *authService) RefreshToken(ctx context.Context, refreshToken, jwtSecret string) (*TokenPair, error) {
+	var pair *TokenPair
+	reused := false
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// 1. Cari dan LOCK refresh token
+		current, err := s.tokenRepo.FindByHashWithLock(tx, utils.HashToken(refreshToken))
+		if err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		now := time.Now()
+
+		// 2. Token yang sudah dirotasi dipakai lagi -> cabut seluruh family.
+		// Return nil supaya pencabutan tetap di-commit.
+		if current.RevokedAt != nil && current.ReplacedByID != nil {
+			if err := s.tokenRepo.RevokeFamilyWithTx(tx, current.FamilyID, now); err != nil {
+				return err
+			}
+			reused = true
+			return nil
+		}
+
+		if !current.IsActive(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		// 3. Ambil data user terbaru (role bisa saja berubah)
+		user, err := s.userRepo.FindByIDWithLock(tx, current.UserID)
+		if err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		// 4. Rotasi: terbitkan token baru dalam family yang sama
+		issued, next, err := s.issueTokens(tx, user, current.FamilyID, jwtSecret)
+		if err != nil {
+			return err
+		}
+
+		current.RevokedAt = &now
+		current.ReplacedByID = &next.ID
+		if err := s.tokenRepo.UpdateWithTx(tx, current); err != nil {
+			return err
+		}
+
+		pair = issued
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+
+	return pair, nil
+}
+
+func (s *authService) Logout(jti string) error {
+	return s.tokenRepo.RevokeByAccessJTI(jti, time.Now())
+}
+
+// IsTokenActive - access token hanya valid selama sesi (refresh token) pasangannya
+// belum dicabut atau dirotasi. Hanya sesi yang tidak ditemukan yang dianggap tidak
+// aktif; error database lain dikembalikan supaya user tidak ter-logout saat DB bermasalah.
+func (s *authService) IsTokenActive(jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+
+	token, err := s.tokenRepo.FindByAccessJTI(jti)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return token.IsActive(time.Now()), nil
+}
+
+// issueTokens - buat access token + refresh token dan simpan hash refresh token
+func (s *authService) issueTokens(tx *gorm.DB, user *models.User, familyID, jwtSecret string) (*TokenPair, *models.RefreshToken, error) {
+	jti, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(user.ID, user.Email, string(user.Role), jti, jwtSecret, s.accessTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := &models.RefreshToken{
+		UserID:		user.ID,
+		TokenHash:	utils.HashToken(refreshToken),
+		FamilyID:	familyID,
+		AccessJTI:	jti,
+		ExpiresAt:	time.Now().Add(s.refreshTTL),
+	}
+	if err := s.tokenRepo.CreateWithTx(tx, record); err != nil {
+		return nil, nil, err
+	}
+
+	pair := &TokenPair{
+		AccessToken:	accessToken,
+		RefreshToken:	refreshToken,
+		ExpiresIn:		int64(s.accessTTL.Seconds()),
 	}
 
-	return token, nil
+	return pair, record, nil
 }
\ No newline at end of file
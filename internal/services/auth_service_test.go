@@ -2,11 +2,15 @@ package services
 
 import (
 	"book-api/internal/models"
+	"book-api/internal/utils"
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // MockUserRepository
@@ -30,15 +34,98 @@ func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
 func (m *MockUserRepository) FindByID(id uint) (*models.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
-		return nil, args.Error(0)
+		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.User), nil
 }
+// FindByIDWithLock
+func (m *MockUserRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.User, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+// FindAll
+func (m *MockUserRepository) FindAll(limit, offset int) ([]models.User, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+// Update
+func (m *MockUserRepository) Update(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+// UpdateWithTx
+func (m *MockUserRepository) UpdateWithTx(tx *gorm.DB, user *models.User) error {
+	args := m.Called(tx, user)
+	return args.Error(0)
+}
+// Count
+func (m *MockUserRepository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+// CountByRole
+func (m *MockUserRepository) CountByRole(role models.Role) (int64, error) {
+	args := m.Called(role)
+	return args.Get(0).(int64), args.Error(1)
+}
+// FindByRoleWithLock
+func (m *MockUserRepository) FindByRoleWithLock(tx *gorm.DB, role models.Role) ([]models.User, error) {
+	args := m.Called(tx, role)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+// MockRefreshTokenRepository
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+// CreateWithTx
+func (m *MockRefreshTokenRepository) CreateWithTx(tx *gorm.DB, token *models.RefreshToken) error {
+	args := m.Called(tx, token)
+	return args.Error(0)
+}
+// FindByHashWithLock
+func (m *MockRefreshTokenRepository) FindByHashWithLock(tx *gorm.DB, tokenHash string) (*models.RefreshToken, error) {
+	args := m.Called(tx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+// FindByAccessJTI
+func (m *MockRefreshTokenRepository) FindByAccessJTI(jti string) (*models.RefreshToken, error) {
+	args := m.Called(jti)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+// UpdateWithTx
+func (m *MockRefreshTokenRepository) UpdateWithTx(tx *gorm.DB, token *models.RefreshToken) error {
+	args := m.Called(tx, token)
+	return args.Error(0)
+}
+// RevokeFamilyWithTx
+func (m *MockRefreshTokenRepository) RevokeFamilyWithTx(tx *gorm.DB, familyID string, revokedAt time.Time) error {
+	args := m.Called(tx, familyID, revokedAt)
+	return args.Error(0)
+}
+// RevokeByAccessJTI
+func (m *MockRefreshTokenRepository) RevokeByAccessJTI(jti string, revokedAt time.Time) error {
+	args := m.Called(jti, revokedAt)
+	return args.Error(0)
+}
+
+func newTestAuthService(userRepo *MockUserRepository, tokenRepo *MockRefreshTokenRepository) AuthService {
+	return NewAuthService(userRepo, tokenRepo, new(MockTransactionManager), 15*time.Minute, 24*time.Hour)
+}
 
 // Test Register - Success
 func TestRegister_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	service := NewAuthService(mockRepo)
+	service := newTestAuthService(mockRepo, new(MockRefreshTokenRepository))
 
 	// Setup mock expectation
 	mockRepo.On("FindByEmail", "test@example.com").Return(nil, errors.New("not found"))
@@ -53,13 +140,14 @@ func TestRegister_Success(t *testing.T) {
 	assert.Equal(t, "Test User", user.Name)
 	assert.Equal(t, "test@example.com", user.Email)
 	assert.NotEmpty(t, user.Password)
+	assert.Equal(t, models.RoleMember, user.Role)
 	mockRepo.AssertExpectations(t)
 }
 
 // Test Register - Email Already Exist
 func TestRegister_EmailAlreadyExist(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	service := NewAuthService(mockRepo)
+	service := newTestAuthService(mockRepo, new(MockRefreshTokenRepository))
 
 	existingUser := &models.User{
 		ID: 1,
@@ -75,6 +163,7 @@ func TestRegister_EmailAlreadyExist(t *testing.T) {
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, user)
+	assert.ErrorIs(t, err, ErrEmailRegistered)
 	assert.Equal(t, "email already registered", err.Error())
 	mockRepo.AssertExpectations(t)
 }
@@ -82,7 +171,8 @@ func TestRegister_EmailAlreadyExist(t *testing.T) {
 // Test Login - Success
 func TestLogin_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	service := NewAuthService(mockRepo)
+	mockTokenRepo := new(MockRefreshTokenRepository)
+	service := newTestAuthService(mockRepo, mockTokenRepo)
 
 	// Buat user dengan password yang sudah di-hash
 	// Password asli: "password123"
@@ -95,20 +185,24 @@ func TestLogin_Success(t *testing.T) {
 
 	// Setup mock
 	mockRepo.On("FindByEmail", "test@example.com").Return(existingUser, nil)
+	mockTokenRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
 	// Execute
-	token, err := service.Login("test@example.com", "password123", "secret-key")
+	pair, err := service.Login(context.Background(), "test@example.com", "password123", "secret-key")
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, int64(900), pair.ExpiresIn)
 	mockRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
 }
 
 // Test Login - Invalid Password
 func TestLogin_InvalidPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	service := NewAuthService(mockRepo)
+	service := newTestAuthService(mockRepo, new(MockRefreshTokenRepository))
 
 	hashedPassword := "$2a$12$Vobb3BoaYxoJKIwDkGX7kuqNSs/Jr61HdBR7GEr5yD.OhMJBDzAmS"
 	existingUser := &models.User{
@@ -121,11 +215,11 @@ func TestLogin_InvalidPassword(t *testing.T) {
 	mockRepo.On("FindByEmail", "test@example.com").Return(existingUser, nil)
 
 	// Execute dengan password salah
-	token, err := service.Login("test@example.com", "wrongpassword", "secret-key")
+	pair, err := service.Login(context.Background(), "test@example.com", "wrongpassword", "secret-key")
 
 	// Assert
 	assert.Error(t, err)
-	assert.Empty(t, token)
+	assert.Nil(t, pair)
 	assert.Equal(t, "invalid email or password", err.Error())
 	mockRepo.AssertExpectations(t)
 }
@@ -133,17 +227,141 @@ func TestLogin_InvalidPassword(t *testing.T) {
 // Test Login - User Not Found
 func TestLogin_UserNotFoud(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	service := NewAuthService(mockRepo)
+	service := newTestAuthService(mockRepo, new(MockRefreshTokenRepository))
 
 	// Setup mock - user tidak ditemukan
 	mockRepo.On("FindByEmail", "notfound@example.com").Return(nil, errors.New("not found"))
 
 	// Execute
-	token, err := service.Login("notfound@example.com", "password123", "secret-key")
+	pair, err := service.Login(context.Background(), "notfound@example.com", "password123", "secret-key")
 
 	// Assert
 	assert.Error(t, err)
-	assert.Empty(t, token)
+	assert.Nil(t, pair)
 	assert.Equal(t, "invalid email or password", err.Error())
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+// Test RefreshToken - Success (rotasi)
+func TestRefreshToken_Success(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokenRepo := new(MockRefreshTokenRepository)
+	service := newTestAuthService(mockRepo, mockTokenRepo)
+
+	current := &models.RefreshToken{
+		ID:        1,
+		UserID:    1,
+		FamilyID:  "family-1",
+		AccessJTI: "old-jti",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	user := &models.User{ID: 1, Email: "test@example.com", Role: models.RoleMember}
+
+	// Setup mock
+	mockTokenRepo.On("FindByHashWithLock", mock.Anything, utils.HashToken("refresh-token")).Return(current, nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(user, nil)
+	mockTokenRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(t *models.RefreshToken) bool {
+		t.ID = 2
+		return t.FamilyID == "family-1" && t.AccessJTI != "old-jti"
+	})).Return(nil)
+	mockTokenRepo.On("UpdateWithTx", mock.Anything, current).Return(nil)
+
+	// Execute
+	pair, err := service.RefreshToken(context.Background(), "refresh-token", "secret-key")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.NotEqual(t, "refresh-token", pair.RefreshToken)
+	assert.NotNil(t, current.RevokedAt)
+	assert.Equal(t, uint(2), *current.ReplacedByID)
+	mockRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+// Test RefreshToken - Reuse Detection
+func TestRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokenRepo := new(MockRefreshTokenRepository)
+	service := newTestAuthService(mockRepo, mockTokenRepo)
+
+	revokedAt := time.Now().Add(-time.Minute)
+	replacedBy := uint(2)
+	rotated := &models.RefreshToken{
+		ID:           1,
+		UserID:       1,
+		FamilyID:     "family-1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		RevokedAt:    &revokedAt,
+		ReplacedByID: &replacedBy,
+	}
+
+	// Setup mock - token lama (sudah dirotasi) dipakai lagi
+	mockTokenRepo.On("FindByHashWithLock", mock.Anything, utils.HashToken("stolen-token")).Return(rotated, nil)
+	mockTokenRepo.On("RevokeFamilyWithTx", mock.Anything, "family-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Execute
+	pair, err := service.RefreshToken(context.Background(), "stolen-token", "secret-key")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	assert.Nil(t, pair)
+	mockTokenRepo.AssertExpectations(t)
+	mockTokenRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// Test RefreshToken - Expired
+func TestRefreshToken_Expired(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokenRepo := new(MockRefreshTokenRepository)
+	service := newTestAuthService(mockRepo, mockTokenRepo)
+
+	expired := &models.RefreshToken{
+		ID:        1,
+		UserID:    1,
+		FamilyID:  "family-1",
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+
+	// Setup mock
+	mockTokenRepo.On("FindByHashWithLock", mock.Anything, utils.HashToken("expired-token")).Return(expired, nil)
+
+	// Execute
+	pair, err := service.RefreshToken(context.Background(), "expired-token", "secret-key")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Nil(t, pair)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+// Test IsTokenActive
+func TestIsTokenActive(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokenRepo := new(MockRefreshTokenRepository)
+	service := newTestAuthService(mockRepo, mockTokenRepo)
+
+	revokedAt := time.Now()
+	mockTokenRepo.On("FindByAccessJTI", "active").Return(&models.RefreshToken{ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockTokenRepo.On("FindByAccessJTI", "revoked").Return(&models.RefreshToken{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	mockTokenRepo.On("FindByAccessJTI", "unknown").Return(nil, gorm.ErrRecordNotFound)
+
+	for jti, want := range map[string]bool{"active": true, "revoked": false, "unknown": false, "": false} {
+		active, err := service.IsTokenActive(jti)
+		assert.NoError(t, err, jti)
+		assert.Equal(t, want, active, jti)
+	}
+}
+
+// Test IsTokenActive - error database bukan berarti token dicabut
+func TestIsTokenActive_DatabaseError(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokenRepo := new(MockRefreshTokenRepository)
+	service := newTestAuthService(mockRepo, mockTokenRepo)
+
+	mockTokenRepo.On("FindByAccessJTI", "active").Return(nil, errors.New("connection refused"))
+
+	active, err := service.IsTokenActive("active")
+	assert.Error(t, err)
+	assert.False(t, active)
+}
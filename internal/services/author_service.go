@@ -0,0 +1,149 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuthorNotFound	= apperrors.NotFound("author_not_found", "author not found")
+	ErrAuthorExists		= apperrors.Conflict("author_exists", "an author with this name already exists")
+	ErrAuthorInUse		= apperrors.Conflict("author_in_use", "author still has books, remove it from those books first")
+)
+
+// AuthorInput - data author untuk create dan update
+type AuthorInput struct {
+	Name	string	`json:"name" validate:"required,min=1,max=200"`
+	Bio		string	`json:"bio" validate:"max=2000"`
+}
+
+type AuthorService interface {
+	CreateAuthor(input AuthorInput) (*models.Author, error)
+	GetAuthors(query string, page, pageSize int) ([]models.Author, int64, error)
+	GetAuthorByID(id uint) (*models.Author, error)
+	UpdateAuthor(id uint, input AuthorInput) (*models.Author, error)
+	DeleteAuthor(id uint) error
+}
+
+type authorService struct {
+	authorRepo repository.AuthorRepository
+}
+
+func NewAuthorService(authorRepo repository.AuthorRepository) AuthorService {
+	return &authorService{authorRepo: authorRepo}
+}
+
+// CreateAuthor - nama yang hanya beda ejaan (spasi, titik, huruf besar) dianggap author yang sama
+func (s *authorService) CreateAuthor(input AuthorInput) (*models.Author, error) {
+	name := strings.TrimSpace(input.Name)
+	key := models.NameKey(name)
+	if key == "" {
+		return nil, ErrInvalidName
+	}
+
+	existing, err := s.authorRepo.FindByNameKey(key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAuthorExists
+	}
+
+	author := &models.Author{Name: name, NameKey: key, Bio: input.Bio}
+	// Index unik name_key tetap menjaga create yang berbarengan
+	if err := s.authorRepo.Create(author); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAuthorExists
+		}
+		return nil, err
+	}
+	return author, nil
+}
+
+func (s *authorService) GetAuthors(query string, page, pageSize int) ([]models.Author, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	authors, err := s.authorRepo.FindAll(query, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.authorRepo.Count(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return authors, total, nil
+}
+
+func (s *authorService) GetAuthorByID(id uint) (*models.Author, error) {
+	author, err := s.authorRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAuthorNotFound
+	}
+	return author, nil
+}
+
+// UpdateAuthor - Book.Author di buku yang sudah ada tidak ikut diubah, karena itu
+// nama tampilan seperti yang tertulis di buku
+func (s *authorService) UpdateAuthor(id uint, input AuthorInput) (*models.Author, error) {
+	author, err := s.authorRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAuthorNotFound
+	}
+
+	name := strings.TrimSpace(input.Name)
+	key := models.NameKey(name)
+	if key == "" {
+		return nil, ErrInvalidName
+	}
+
+	existing, err := s.authorRepo.FindByNameKey(key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ID != author.ID {
+		return nil, ErrAuthorExists
+	}
+
+	author.Name = name
+	author.NameKey = key
+	author.Bio = input.Bio
+
+	if err := s.authorRepo.Update(author); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAuthorExists
+		}
+		return nil, err
+	}
+	return author, nil
+}
+
+// DeleteAuthor - author yang masih punya buku tidak boleh dihapus
+func (s *authorService) DeleteAuthor(id uint) error {
+	if _, err := s.authorRepo.FindByID(id); err != nil {
+		return ErrAuthorNotFound
+	}
+
+	count, err := s.authorRepo.CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAuthorInUse
+	}
+
+	return s.authorRepo.Delete(id)
+}
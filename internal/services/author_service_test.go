@@ -0,0 +1,204 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockAuthorRepository
+type MockAuthorRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuthorRepository) Create(author *models.Author) error {
+	args := m.Called(author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) FindAll(query string, limit, offset int) ([]models.Author, error) {
+	args := m.Called(query, limit, offset)
+	return args.Get(0).([]models.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) Count(query string) (int64, error) {
+	args := m.Called(query)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAuthorRepository) FindByID(id uint) (*models.Author, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) FindByNameKey(nameKey string) (*models.Author, error) {
+	args := m.Called(nameKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Author, error) {
+	args := m.Called(tx, ids)
+	return args.Get(0).([]models.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) FindOrCreateByNamesWithTx(tx *gorm.DB, names []string) ([]models.Author, error) {
+	args := m.Called(tx, names)
+	return args.Get(0).([]models.Author), args.Error(1)
+}
+
+func (m *MockAuthorRepository) Update(author *models.Author) error {
+	args := m.Called(author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAuthorRepository) CountBooks(id uint) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// TestCreateAuthor - Success, nama disimpan dengan name key
+func TestCreateAuthor_Success(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByNameKey", "jkrowling").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.MatchedBy(func(a *models.Author) bool {
+		return a.Name == "J.K. Rowling" && a.NameKey == "jkrowling"
+	})).Return(nil)
+
+	// Execute
+	author, err := service.CreateAuthor(AuthorInput{Name: "  J.K. Rowling ", Bio: "British author"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "J.K. Rowling", author.Name)
+	assert.Equal(t, "British author", author.Bio)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCreateAuthor - ejaan lain dari author yang sudah ada ditolak
+func TestCreateAuthor_AlreadyExists(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByNameKey", "jkrowling").Return(&models.Author{ID: 1, Name: "J.K. Rowling"}, nil)
+
+	// Execute
+	author, err := service.CreateAuthor(AuthorInput{Name: "JK Rowling"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAuthorExists)
+	assert.Nil(t, author)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCreateAuthor - nama tanpa huruf maupun angka
+func TestCreateAuthor_InvalidName(t *testing.T) {
+	service := NewAuthorService(new(MockAuthorRepository))
+
+	author, err := service.CreateAuthor(AuthorInput{Name: "..."})
+
+	assert.ErrorIs(t, err, ErrInvalidName)
+	assert.Nil(t, author)
+}
+
+// TestUpdateAuthor - nama yang sama dengan author sendiri boleh dipakai lagi
+func TestUpdateAuthor_SameAuthor(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	existing := &models.Author{ID: 1, Name: "JK Rowling", NameKey: "jkrowling"}
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(1)).Return(existing, nil)
+	mockRepo.On("FindByNameKey", "jkrowling").Return(existing, nil)
+	mockRepo.On("Update", existing).Return(nil)
+
+	// Execute
+	author, err := service.UpdateAuthor(1, AuthorInput{Name: "J. K. Rowling"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "J. K. Rowling", author.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestDeleteAuthor - author yang masih punya buku tidak boleh dihapus
+func TestDeleteAuthor_InUse(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(1)).Return(&models.Author{ID: 1}, nil)
+	mockRepo.On("CountBooks", uint(1)).Return(int64(3), nil)
+
+	// Execute
+	err := service.DeleteAuthor(1)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAuthorInUse)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+// TestDeleteAuthor - Not Found
+func TestDeleteAuthor_NotFound(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	mockRepo.On("FindByID", uint(9)).Return(nil, errors.New("record not found"))
+
+	assert.ErrorIs(t, service.DeleteAuthor(9), ErrAuthorNotFound)
+}
+
+// TestCreateAuthor - error database saat cek nama tidak dianggap "belum ada"
+func TestCreateAuthor_LookupError(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	dbErr := errors.New("connection reset")
+
+	// Setup mock
+	mockRepo.On("FindByNameKey", "jkrowling").Return(nil, dbErr)
+
+	// Execute
+	author, err := service.CreateAuthor(AuthorInput{Name: "JK Rowling"})
+
+	// Assert
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, author)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCreateAuthor - create berbarengan yang kalah di index unik menjadi ErrAuthorExists
+func TestCreateAuthor_DuplicateKey(t *testing.T) {
+	mockRepo := new(MockAuthorRepository)
+	service := NewAuthorService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByNameKey", "jkrowling").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Author")).Return(gorm.ErrDuplicatedKey)
+
+	// Execute
+	author, err := service.CreateAuthor(AuthorInput{Name: "JK Rowling"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAuthorExists)
+	assert.Nil(t, author)
+}
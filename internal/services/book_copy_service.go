@@ -0,0 +1,242 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCopyNotFound			= apperrors.NotFound("copy_not_found", "copy not found")
+	ErrBarcodeExists		= apperrors.Conflict("barcode_exists", "a copy with this barcode already exists")
+	ErrCopyInCirculation	= apperrors.Conflict("copy_in_circulation", "copy is on loan or on hold, check it in first")
+	ErrInvalidCopyStatus	= apperrors.Validation("invalid_copy_status", "invalid copy status")
+)
+
+// manualCopyStatuses - status yang boleh di-set langsung oleh staff.
+// borrowed dan on_hold hanya diatur oleh alur borrow/return/hold.
+var manualCopyStatuses = map[models.CopyStatus]bool{
+	models.CopyStatusAvailable:		true,
+	models.CopyStatusLost:			true,
+	models.CopyStatusMaintenance:	true,
+	models.CopyStatusWithdrawn:		true,
+}
+
+type BookCopyService interface {
+	GetCopies(bookID uint) ([]models.BookCopy, error)
+	GetCopyByBarcode(barcode string) (*models.BookCopy, error)
+	AddCopy(ctx context.Context, bookID uint, barcode, shelfLocation string, condition models.CopyCondition) (*models.BookCopy, error)
+	UpdateCopy(ctx context.Context, copyID uint, shelfLocation *string, condition models.CopyCondition, status models.CopyStatus) (*models.BookCopy, error)
+}
+
+type bookCopyService struct {
+	copyRepo	repository.BookCopyRepository
+	bookRepo	repository.BookRepository
+	txManager	database.TransactionManager
+	holds		holdQueue
+	audit		auditTrail
+}
+
+func NewBookCopyService(
+	copyRepo repository.BookCopyRepository,
+	bookRepo repository.BookRepository,
+	reservationRepo repository.ReservationRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager database.TransactionManager,
+	pickupWindow time.Duration,
+) BookCopyService {
+	return &bookCopyService{
+		copyRepo:	copyRepo,
+		bookRepo:	bookRepo,
+		txManager:	txManager,
+		holds:		holdQueue{reservationRepo: reservationRepo, copyRepo: copyRepo, pickupWindow: pickupWindow},
+		audit:		auditTrail{repo: auditRepo},
+	}
+}
+
+func (s *bookCopyService) GetCopies(bookID uint) ([]models.BookCopy, error) {
+	if _, err := s.bookRepo.FindByID(bookID); err != nil {
+		return nil, ErrBookNotFound
+	}
+	return s.copyRepo.FindByBookID(bookID)
+}
+
+func (s *bookCopyService) GetCopyByBarcode(barcode string) (*models.BookCopy, error) {
+	bookCopy, err := s.copyRepo.FindByBarcode(barcode)
+	if err != nil {
+		return nil, ErrCopyNotFound
+	}
+	return bookCopy, nil
+}
+
+// AddCopy - daftarkan copy baru. Barcode kosong akan dibuatkan otomatis.
+// Copy baru langsung diserahkan ke antrian hold jika ada yang menunggu.
+func (s *bookCopyService) AddCopy(ctx context.Context, bookID uint, barcode, shelfLocation string, condition models.CopyCondition) (*models.BookCopy, error) {
+	if barcode != "" {
+		if existing, _ := s.copyRepo.FindByBarcode(barcode); existing != nil {
+			return nil, ErrBarcodeExists
+		}
+	}
+	if condition == "" {
+		condition = models.CopyConditionGood
+	}
+
+	var result *models.BookCopy
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		book, err := s.bookRepo.FindByIDWithLock(tx, bookID)
+		if err != nil {
+			return ErrBookNotFound
+		}
+
+		if barcode == "" {
+			generated, err := nextBarcode(tx, s.copyRepo, bookID)
+			if err != nil {
+				return err
+			}
+			barcode = generated
+		}
+
+		bookCopy := &models.BookCopy{
+			BookID:			bookID,
+			Barcode:		barcode,
+			ShelfLocation:	shelfLocation,
+			Condition:		condition,
+			Status:			models.CopyStatusAvailable,
+		}
+		if err := s.copyRepo.CreateWithTx(tx, bookCopy); err != nil {
+			return err
+		}
+
+		if _, err := s.holds.releaseCopy(tx, bookCopy); err != nil {
+			return err
+		}
+		if err := s.recordStockWithTx(ctx, tx, bookID, book.Stock); err != nil {
+			return err
+		}
+
+		result = bookCopy
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateCopy - ubah lokasi rak, kondisi dan status copy; shelfLocation nil dan condition/status
+// kosong berarti tidak diubah. Copy yang sedang dipinjam
+// atau disisihkan untuk hold tidak boleh diubah statusnya. Perubahan stock buku
+// dicatat di audit log.
+func (s *bookCopyService) UpdateCopy(ctx context.Context, copyID uint, shelfLocation *string, condition models.CopyCondition, status models.CopyStatus) (*models.BookCopy, error) {
+	current, err := s.copyRepo.FindByID(copyID)
+	if err != nil {
+		return nil, ErrCopyNotFound
+	}
+
+	var result *models.BookCopy
+
+	err = s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// LOCK buku dulu, baru copy (urutan lock sama dengan borrow/return)
+		book, err := s.bookRepo.FindByIDWithLock(tx, current.BookID)
+		if err != nil {
+			return err
+		}
+		bookCopy, err := s.copyRepo.FindByIDWithLock(tx, copyID)
+		if err != nil {
+			return ErrCopyNotFound
+		}
+
+		if shelfLocation != nil {
+			bookCopy.ShelfLocation = *shelfLocation
+		}
+		if condition != "" {
+			bookCopy.Condition = condition
+		}
+
+		if status != "" && status != bookCopy.Status {
+			if !manualCopyStatuses[status] {
+				return ErrInvalidCopyStatus
+			}
+			if bookCopy.InCirculation() {
+				return ErrCopyInCirculation
+			}
+
+			// Copy yang kembali tersedia diteruskan ke antrian hold
+			if status == models.CopyStatusAvailable {
+				if _, err := s.holds.releaseCopy(tx, bookCopy); err != nil {
+					return err
+				}
+				result = bookCopy
+				return s.recordStockWithTx(ctx, tx, book.ID, book.Stock)
+			}
+			bookCopy.Status = status
+		}
+
+		if err := s.copyRepo.UpdateWithTx(tx, bookCopy); err != nil {
+			return err
+		}
+		if err := s.copyRepo.SyncBookStockWithTx(tx, bookCopy.BookID); err != nil {
+			return err
+		}
+		if err := s.recordStockWithTx(ctx, tx, book.ID, book.Stock); err != nil {
+			return err
+		}
+
+		result = bookCopy
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// recordStockWithTx - catat perubahan stock buku setelah SyncBookStockWithTx
+func (s *bookCopyService) recordStockWithTx(ctx context.Context, tx *gorm.DB, bookID uint, before int) error {
+	stock, err := stockChangeWithTx(tx, s.bookRepo, bookID, before)
+	if err != nil {
+		return err
+	}
+	return s.audit.recordStock(ctx, tx, bookID, stock)
+}
+
+// createInitialCopies - copy awal untuk buku baru, masing-masing dengan barcode otomatis
+func createInitialCopies(tx *gorm.DB, copyRepo repository.BookCopyRepository, bookID uint, count int) error {
+	for i := 1; i <= count; i++ {
+		bookCopy := &models.BookCopy{
+			BookID:		bookID,
+			Barcode:	copyBarcode(bookID, int64(i)),
+			Condition:	models.CopyConditionGood,
+			Status:		models.CopyStatusAvailable,
+		}
+		if err := copyRepo.CreateWithTx(tx, bookCopy); err != nil {
+			return err
+		}
+	}
+
+	return copyRepo.SyncBookStockWithTx(tx, bookID)
+}
+
+// nextBarcode - barcode otomatis dengan format BK<book id>-<nomor urut copy>
+func nextBarcode(tx *gorm.DB, copyRepo repository.BookCopyRepository, bookID uint) (string, error) {
+	count, err := copyRepo.CountByBookIDWithTx(tx, bookID)
+	if err != nil {
+		return "", err
+	}
+	return copyBarcode(bookID, count+1), nil
+}
+
+func copyBarcode(bookID uint, seq int64) string {
+	return fmt.Sprintf("BK%06d-%03d", bookID, seq)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockBookCopyRepository
+type MockBookCopyRepository struct {
+	mock.Mock
+}
+
+func (m *MockBookCopyRepository) CreateWithTx(tx *gorm.DB, bookCopy *models.BookCopy) error {
+	args := m.Called(tx, bookCopy)
+	return args.Error(0)
+}
+
+func (m *MockBookCopyRepository) FindByID(id uint) (*models.BookCopy, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookCopy), args.Error(1)
+}
+
+func (m *MockBookCopyRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.BookCopy, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookCopy), args.Error(1)
+}
+
+func (m *MockBookCopyRepository) FindByBarcode(barcode string) (*models.BookCopy, error) {
+	args := m.Called(barcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookCopy), args.Error(1)
+}
+
+func (m *MockBookCopyRepository) FindByBookID(bookID uint) ([]models.BookCopy, error) {
+	args := m.Called(bookID)
+	return args.Get(0).([]models.BookCopy), args.Error(1)
+}
+
+func (m *MockBookCopyRepository) FindAvailableWithLock(tx *gorm.DB, bookID uint) (*models.BookCopy, error) {
+	args := m.Called(tx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookCopy), args.Error(1)
+}
+
+func (m *MockBookCopyRepository) CountByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error) {
+	args := m.Called(tx, bookID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBookCopyRepository) UpdateWithTx(tx *gorm.DB, bookCopy *models.BookCopy) error {
+	args := m.Called(tx, bookCopy)
+	return args.Error(0)
+}
+
+func (m *MockBookCopyRepository) SyncBookStockWithTx(tx *gorm.DB, bookID uint) error {
+	args := m.Called(tx, bookID)
+	return args.Error(0)
+}
+
+func newTestCopyService(copyRepo *MockBookCopyRepository, bookRepo *MockBookRepository, reservationRepo *MockReservationRepository) BookCopyService {
+	return NewBookCopyService(copyRepo, bookRepo, reservationRepo, nil, new(MockTransactionManager), 48*time.Hour)
+}
+
+// TestAddCopy - barcode otomatis dan copy langsung available
+func TestAddCopy_GeneratedBarcode(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	service := newTestCopyService(mockCopyRepo, mockBookRepo, mockReservationRepo)
+
+	// Expectations
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockCopyRepo.On("CountByBookIDWithTx", mock.Anything, uint(1)).Return(int64(2), nil)
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	bookCopy, err := service.AddCopy(context.Background(), uint(1), "", "A-01", "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "BK000001-003", bookCopy.Barcode)
+	assert.Equal(t, models.CopyConditionGood, bookCopy.Condition)
+	assert.Equal(t, models.CopyStatusAvailable, bookCopy.Status)
+	mockCopyRepo.AssertExpectations(t)
+}
+
+// TestAddCopy - copy baru langsung disisihkan untuk hold yang menunggu
+func TestAddCopy_AssignsWaitingHold(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	service := newTestCopyService(mockCopyRepo, mockBookRepo, mockReservationRepo)
+
+	waiting := &models.Reservation{ID: 5, UserID: 3, BookID: 1, Status: models.ReservationStatusWaiting}
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "LIB-0001").Return(nil, gorm.ErrRecordNotFound)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.BookCopy).ID = 9
+	}).Return(nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(waiting, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, waiting).Return(nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	bookCopy, err := service.AddCopy(context.Background(), uint(1), "LIB-0001", "", models.CopyConditionNew)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.CopyStatusOnHold, bookCopy.Status)
+	assert.Equal(t, models.ReservationStatusReady, waiting.Status)
+	assert.Equal(t, uint(9), *waiting.CopyID)
+}
+
+// TestAddCopy - barcode sudah dipakai
+func TestAddCopy_BarcodeExists(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	service := newTestCopyService(mockCopyRepo, new(MockBookRepository), new(MockReservationRepository))
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "LIB-0001").Return(&models.BookCopy{ID: 2, Barcode: "LIB-0001"}, nil)
+
+	// Execute
+	bookCopy, err := service.AddCopy(context.Background(), uint(1), "LIB-0001", "", "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrBarcodeExists)
+	assert.Nil(t, bookCopy)
+	mockCopyRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestUpdateCopy - tandai hilang, stock dihitung ulang
+func TestUpdateCopy_MarkLost(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockBookRepo := new(MockBookRepository)
+	service := newTestCopyService(mockCopyRepo, mockBookRepo, new(MockReservationRepository))
+
+	bookCopy := &models.BookCopy{ID: 4, BookID: 1, Barcode: "LIB-0004", Status: models.CopyStatusAvailable, Condition: models.CopyConditionGood}
+
+	// Expectations
+	mockCopyRepo.On("FindByID", uint(4)).Return(bookCopy, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(4)).Return(bookCopy, nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	shelf := "B-02"
+	result, err := service.UpdateCopy(context.Background(), uint(4), &shelf, "", models.CopyStatusLost)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.CopyStatusLost, result.Status)
+	assert.Equal(t, "B-02", result.ShelfLocation)
+	mockCopyRepo.AssertExpectations(t)
+}
+
+// TestUpdateCopy - copy yang sedang dipinjam tidak boleh diubah statusnya
+func TestUpdateCopy_InCirculation(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockBookRepo := new(MockBookRepository)
+	service := newTestCopyService(mockCopyRepo, mockBookRepo, new(MockReservationRepository))
+
+	bookCopy := &models.BookCopy{ID: 4, BookID: 1, Status: models.CopyStatusBorrowed}
+
+	// Expectations
+	mockCopyRepo.On("FindByID", uint(4)).Return(bookCopy, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(4)).Return(bookCopy, nil)
+
+	// Execute
+	result, err := service.UpdateCopy(context.Background(), uint(4), nil, "", models.CopyStatusMaintenance)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrCopyInCirculation)
+	assert.Nil(t, result)
+	mockCopyRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestGetCopyByBarcode - Not found
+func TestGetCopyByBarcode_NotFound(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	service := newTestCopyService(mockCopyRepo, new(MockBookRepository), new(MockReservationRepository))
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "missing").Return(nil, errors.New("record not found"))
+
+	// Execute
+	bookCopy, err := service.GetCopyByBarcode("missing")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrCopyNotFound)
+	assert.Nil(t, bookCopy)
+}
+
+// TestUpdateCopy - shelf location yang tidak dikirim tidak ikut dikosongkan
+func TestUpdateCopy_KeepsShelfLocation(t *testing.T) {
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockBookRepo := new(MockBookRepository)
+	service := newTestCopyService(mockCopyRepo, mockBookRepo, new(MockReservationRepository))
+
+	bookCopy := &models.BookCopy{ID: 4, BookID: 1, ShelfLocation: "A-01", Status: models.CopyStatusAvailable, Condition: models.CopyConditionGood}
+
+	// Expectations
+	mockCopyRepo.On("FindByID", uint(4)).Return(bookCopy, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(4)).Return(bookCopy, nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	result, err := service.UpdateCopy(context.Background(), uint(4), nil, "", models.CopyStatusMaintenance)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.CopyStatusMaintenance, result.Status)
+	assert.Equal(t, "A-01", result.ShelfLocation)
+	mockCopyRepo.AssertExpectations(t)
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/imaging"
+	"book-api/internal/logging"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"book-api/internal/storage"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"path"
+	"time"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/gabriel-vasile/mimetype"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCoverNotFound		= apperrors.NotFound("cover_not_found", "book has no cover")
+	ErrUnsupportedCoverType	= apperrors.UnsupportedMedia("unsupported_cover_type", "cover must be a JPEG, PNG or GIF image")
+	ErrInvalidCoverImage	= apperrors.Validation("invalid_cover_image", "cover image is corrupt or cannot be decoded")
+	ErrCoverDimensions		= apperrors.Validation("cover_too_large", "cover image is larger than 40 megapixels")
+	ErrInvalidCoverSize		= apperrors.Validation("invalid_cover_size", "invalid cover size, use original, small or medium")
+)
+
+// maxCoverPixels - batas dimensi sebelum decode, supaya file kecil yang mengaku
+// berukuran raksasa tidak menghabiskan memory
+const maxCoverPixels = 40_000_000
+
+type CoverSize string
+
+const (
+	CoverOriginal	CoverSize = "original"
+	CoverMedium		CoverSize = "medium"
+	CoverSmall		CoverSize = "small"
+)
+
+// coverThumbnails - thumbnail yang dibuat saat upload, sisi terpanjang dalam pixel
+var coverThumbnails = []struct {
+	size	CoverSize
+	pixels	int
+}{
+	{CoverMedium, 480},
+	{CoverSmall, 160},
+}
+
+// coverTypes - MIME type yang bisa di-decode library standar, beserta ekstensi file-nya
+var coverTypes = map[string]string{
+	"image/jpeg":	".jpg",
+	"image/png":	".png",
+	"image/gif":	".gif",
+}
+
+// Cover - file cover yang siap dikirim. Body harus di-Close oleh pemanggil.
+type Cover struct {
+	Body		io.ReadCloser
+	ContentType	string
+	Size		int64
+	ModTime		time.Time
+	ETag		string
+	Version		string // hash gambar, sama dengan parameter v di Book.CoverURL
+}
+
+type BookCoverService interface {
+	UploadCover(ctx context.Context, bookID uint, data []byte) (*models.Book, error)
+	GetCover(ctx context.Context, bookID uint, size CoverSize) (*Cover, error)
+	DeleteCover(ctx context.Context, bookID uint) error
+}
+
+type bookCoverService struct {
+	bookRepo	repository.BookRepository
+	txManager	database.TransactionManager
+	store		storage.BlobStore
+	audit		auditTrail
+}
+
+func NewBookCoverService(bookRepo repository.BookRepository, auditRepo repository.AuditLogRepository, txManager database.TransactionManager, store storage.BlobStore) BookCoverService {
+	return &bookCoverService{
+		bookRepo:	bookRepo,
+		txManager:	txManager,
+		store:		store,
+		audit:		auditTrail{repo: auditRepo},
+	}
+}
+
+// UploadCover - tipe file ditentukan dari isinya, bukan dari nama file atau header.
+// Gambar asli disimpan apa adanya, thumbnail dibuat ulang sebagai JPEG. Key blob memuat
+// hash gambar, jadi cover lama dihapus setelah buku menunjuk ke cover baru.
+func (s *bookCoverService) UploadCover(ctx context.Context, bookID uint, data []byte) (*models.Book, error) {
+	book, err := s.bookRepo.FindByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := mimetype.Detect(data).String()
+	ext, ok := coverTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedCoverType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidCoverImage
+	}
+	if config.Width*config.Height > maxCoverPixels {
+		return nil, ErrCoverDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidCoverImage
+	}
+
+	sum := sha256.Sum256(data)
+	coverKey := fmt.Sprintf("covers/%d/%s", bookID, hex.EncodeToString(sum[:8]))
+
+	blobs := map[string][]byte{coverKey + ext: data}
+	for _, thumb := range coverThumbnails {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, imaging.Fit(img, thumb.pixels), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		blobs[thumbnailKey(coverKey, thumb.size)] = buf.Bytes()
+	}
+	for key, blob := range blobs {
+		if err := s.store.Put(ctx, key, bytes.NewReader(blob)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Lock buku supaya dua upload bersamaan tidak saling menghapus cover yang baru
+	var oldKey, oldType string
+	var version int64
+	err = s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		locked, err := s.bookRepo.FindByIDWithLock(tx, bookID)
+		if err != nil {
+			return err
+		}
+		oldKey, oldType, version = locked.CoverKey, locked.CoverType, locked.Version
+		if err := s.bookRepo.UpdateCoverWithTx(tx, bookID, coverKey, contentType); err != nil {
+			return err
+		}
+		return s.recordCover(ctx, tx, locked, coverKey, contentType)
+	})
+	if err != nil {
+		// Upload ulang gambar yang sama memakai key yang masih dirujuk buku
+		if coverKey != book.CoverKey && coverKey != oldKey {
+			deleteCoverBlobs(ctx, s.store, coverKey, contentType)
+		}
+		return nil, err
+	}
+	if oldKey != "" && oldKey != coverKey {
+		deleteCoverBlobs(ctx, s.store, oldKey, oldType)
+	}
+
+	book.CoverKey = coverKey
+	book.CoverType = contentType
+	book.Version = version + 1
+	book.SetCoverURL()
+	return book, nil
+}
+
+// GetCover - size kosong berarti gambar asli
+func (s *bookCoverService) GetCover(ctx context.Context, bookID uint, size CoverSize) (*Cover, error) {
+	if size == "" {
+		size = CoverOriginal
+	}
+
+	book, err := s.bookRepo.FindByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if book.CoverKey == "" {
+		return nil, ErrCoverNotFound
+	}
+
+	key, contentType := book.CoverKey+coverTypes[book.CoverType], book.CoverType
+	if size != CoverOriginal {
+		if !validThumbnail(size) {
+			return nil, ErrInvalidCoverSize
+		}
+		key, contentType = thumbnailKey(book.CoverKey, size), "image/jpeg"
+	}
+
+	body, info, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrCoverNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	version := path.Base(book.CoverKey)
+	return &Cover{
+		Body:			body,
+		ContentType:	contentType,
+		Size:			info.Size,
+		ModTime:		info.ModTime,
+		ETag:			fmt.Sprintf(`"%s-%s"`, version, size),
+		Version:		version,
+	}, nil
+}
+
+func (s *bookCoverService) DeleteCover(ctx context.Context, bookID uint) error {
+	var oldKey, oldType string
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		book, err := s.bookRepo.FindByIDWithLock(tx, bookID)
+		if err != nil {
+			return err
+		}
+		if book.CoverKey == "" {
+			return ErrCoverNotFound
+		}
+		oldKey, oldType = book.CoverKey, book.CoverType
+		if err := s.bookRepo.UpdateCoverWithTx(tx, bookID, "", ""); err != nil {
+			return err
+		}
+		return s.recordCover(ctx, tx, book, "", "")
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteCoverBlobs(ctx, s.store, oldKey, oldType)
+	return nil
+}
+
+// recordCover - catat perubahan cover_url dan version buku yang sudah di-LOCK
+func (s *bookCoverService) recordCover(ctx context.Context, tx *gorm.DB, locked *models.Book, coverKey, contentType string) error {
+	before := bookSnapshot(locked)
+	locked.CoverKey = coverKey
+	locked.CoverType = contentType
+	locked.Version++
+	locked.SetCoverURL()
+	return s.audit.recordBook(ctx, tx, locked.ID, models.AuditActionUpdate, before, bookSnapshot(locked))
+}
+
+// deleteCoverBlobs - hapus gambar asli dan thumbnail. Gagal hapus hanya menyisakan file
+// yang tidak dirujuk buku mana pun, jadi cukup dicatat di log request.
+func deleteCoverBlobs(ctx context.Context, store storage.BlobStore, coverKey, contentType string) {
+	keys := []string{coverKey + coverTypes[contentType]}
+	for _, thumb := range coverThumbnails {
+		keys = append(keys, thumbnailKey(coverKey, thumb.size))
+	}
+
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			logging.FromContext(ctx).Warn("failed to delete cover blob", "key", key, "error", err)
+		}
+	}
+}
+
+func thumbnailKey(coverKey string, size CoverSize) string {
+	return coverKey + "_" + string(size) + ".jpg"
+}
+
+func validThumbnail(size CoverSize) bool {
+	for _, thumb := range coverThumbnails {
+		if thumb.size == size {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/storage"
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testCoverPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func newTestCoverService(t *testing.T) (BookCoverService, *MockBookRepository, storage.BlobStore) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	mockRepo := new(MockBookRepository)
+	return NewBookCoverService(mockRepo, nil, new(MockTransactionManager), store), mockRepo, store
+}
+
+// TestUploadCover - gambar asli dan thumbnail disimpan, cover lama dihapus
+func TestUploadCover_Success(t *testing.T) {
+	service, mockRepo, store := newTestCoverService(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "covers/1/old.png", bytes.NewReader([]byte("old"))))
+	oldBook := &models.Book{ID: 1, CoverKey: "covers/1/old", CoverType: "image/png"}
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(oldBook, nil)
+	mockRepo.On("UpdateCoverWithTx", mock.Anything, uint(1), mock.AnythingOfType("string"), "image/png").Return(nil)
+
+	book, err := service.UploadCover(ctx, 1, testCoverPNG(t, 600, 900))
+
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", book.CoverType)
+	assert.Contains(t, book.CoverURL, "/api/v1/books/1/cover?v=")
+
+	_, _, err = store.Get(ctx, "covers/1/old.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	mockRepo.On("FindByID", uint(1)).Unset()
+	mockRepo.On("FindByID", uint(1)).Return(book, nil)
+
+	cover, err := service.GetCover(ctx, 1, CoverMedium)
+	require.NoError(t, err)
+	defer cover.Body.Close()
+	assert.Equal(t, "image/jpeg", cover.ContentType)
+
+	data, _ := io.ReadAll(cover.Body)
+	thumb, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 320, thumb.Width)
+	assert.Equal(t, 480, thumb.Height)
+}
+
+// TestUploadCover - tipe file dari isinya, bukan dari nama file
+func TestUploadCover_UnsupportedType(t *testing.T) {
+	service, mockRepo, _ := newTestCoverService(t)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1}, nil)
+
+	_, err := service.UploadCover(context.Background(), 1, []byte("%PDF-1.4 not an image"))
+
+	assert.ErrorIs(t, err, ErrUnsupportedCoverType)
+	mockRepo.AssertNotCalled(t, "UpdateCoverWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUploadCover - header PNG dengan isi rusak
+func TestUploadCover_Corrupt(t *testing.T) {
+	service, mockRepo, _ := newTestCoverService(t)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1}, nil)
+
+	data := testCoverPNG(t, 10, 10)
+	_, err := service.UploadCover(context.Background(), 1, data[:40])
+
+	assert.ErrorIs(t, err, ErrInvalidCoverImage)
+}
+
+// TestGetCover - buku tanpa cover dan ukuran yang tidak dikenal
+func TestGetCover_Errors(t *testing.T) {
+	service, mockRepo, _ := newTestCoverService(t)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockRepo.On("FindByID", uint(2)).Return(&models.Book{ID: 2, CoverKey: "covers/2/abc", CoverType: "image/png"}, nil)
+
+	_, err := service.GetCover(context.Background(), 1, CoverOriginal)
+	assert.ErrorIs(t, err, ErrCoverNotFound)
+
+	_, err = service.GetCover(context.Background(), 2, "huge")
+	assert.ErrorIs(t, err, ErrInvalidCoverSize)
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/isbn"
+	"book-api/internal/logging"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"book-api/internal/utils"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedImportFormat	= apperrors.Validation("unsupported_import_format", "unsupported import format, use csv or jsonl")
+	ErrInvalidImportHeader		= apperrors.Validation("invalid_import_header", "CSV header must contain title, author and isbn columns")
+)
+
+// errImportDryRun - dipakai untuk rollback batch pada dry run
+var errImportDryRun = errors.New("dry run")
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV		ImportFormat = "csv"
+	ImportFormatJSONL	ImportFormat = "jsonl"
+)
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated	ImportRowStatus = "created"
+	ImportRowUpdated	ImportRowStatus = "updated"
+	ImportRowFailed		ImportRowStatus = "failed"
+)
+
+// ImportRowResult - hasil satu baris. Row adalah nomor baris di file (header CSV = baris 1).
+type ImportRowResult struct {
+	Row		int				`json:"row"`
+	ISBN	string			`json:"isbn,omitempty"`
+	Status	ImportRowStatus	`json:"status"`
+	BookID	uint			`json:"book_id,omitempty"`
+	Error	string			`json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun	bool				`json:"dry_run"`
+	Total	int					`json:"total"`
+	Created	int					`json:"created"`
+	Updated	int					`json:"updated"`
+	Failed	int					`json:"failed"`
+	Rows	[]ImportRowResult	`json:"rows"`
+}
+
+type BookImportService interface {
+	Import(ctx context.Context, format ImportFormat, r io.Reader, dryRun bool) (*ImportReport, error)
+}
+
+type bookImportService struct {
+	bookRepo	repository.BookRepository
+	copyRepo	repository.BookCopyRepository
+	txManager	database.TransactionManager
+	relations	bookRelations
+	audit		auditTrail
+	batchSize	int
+}
+
+func NewBookImportService(
+	bookRepo repository.BookRepository,
+	copyRepo repository.BookCopyRepository,
+	authorRepo repository.AuthorRepository,
+	publisherRepo repository.PublisherRepository,
+	genreRepo repository.GenreRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager database.TransactionManager,
+	batchSize int,
+) BookImportService {
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	return &bookImportService{
+		bookRepo:	bookRepo,
+		copyRepo:	copyRepo,
+		txManager:	txManager,
+		relations:	bookRelations{authorRepo: authorRepo, publisherRepo: publisherRepo, genreRepo: genreRepo},
+		audit:		auditTrail{repo: auditRepo},
+		batchSize:	batchSize,
+	}
+}
+
+// importRow - satu baris yang sudah di-parse, beserta hasilnya
+type importRow struct {
+	input	BookInput
+	result	ImportRowResult
+}
+
+// Import - upsert buku berdasarkan ISBN. Buku baru mendapat copy sebanyak stock,
+// buku yang sudah ada hanya diperbarui title, author, description dan relasinya.
+// String author dipecah menjadi entity author seperti pada CreateBook.
+// Baris valid diproses per batch dalam transaction dan setiap baris dalam savepoint
+// sendiri; baris yang gagal di database hanya membatalkan baris itu. Dry run
+// menjalankan alur yang sama lalu me-rollback setiap batch.
+func (s *bookImportService) Import(ctx context.Context, format ImportFormat, r io.Reader, dryRun bool) (*ImportReport, error) {
+	var rows []*importRow
+	var err error
+
+	switch format {
+	case ImportFormatCSV:
+		rows, err = parseCSVRows(r)
+	case ImportFormatJSONL:
+		rows, err = parseJSONLRows(r)
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Validasi dengan rule yang sama seperti CreateBookRequest, lalu ISBN diubah ke
+	// ISBN-13 dan ISBN yang muncul dua kali di file (dalam bentuk apa pun) ditolak
+	seen := map[string]int{}
+	var valid []*importRow
+	for _, row := range rows {
+		if row.result.Status == ImportRowFailed {
+			continue
+		}
+		if err := utils.ValidateStruct(row.input); err != nil {
+			row.fail(err.Error())
+			continue
+		}
+		row.input.ISBN, _ = isbn.Canonical(row.input.ISBN)
+		row.result.ISBN = row.input.ISBN
+
+		if first, ok := seen[row.input.ISBN]; ok {
+			row.fail(fmt.Sprintf("duplicate ISBN, already on row %d", first))
+			continue
+		}
+		seen[row.input.ISBN] = row.result.Row
+		valid = append(valid, row)
+	}
+
+	for start := 0; start < len(valid); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+			if err := s.importBatch(ctx, tx, batch); err != nil {
+				return err
+			}
+			if dryRun {
+				return errImportDryRun
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errImportDryRun) {
+			logging.FromContext(ctx).Error("import batch rolled back", "first_row", batch[0].result.Row, "error", err)
+			for _, row := range batch {
+				row.fail("import failed, try again")
+			}
+		}
+	}
+
+	report := &ImportReport{DryRun: dryRun, Total: len(rows), Rows: make([]ImportRowResult, 0, len(rows))}
+	for _, row := range rows {
+		if dryRun {
+			// ID dari batch yang di-rollback tidak pernah ada
+			row.result.BookID = 0
+		}
+		switch row.result.Status {
+		case ImportRowCreated:
+			report.Created++
+		case ImportRowUpdated:
+			report.Updated++
+		case ImportRowFailed:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, row.result)
+	}
+
+	return report, nil
+}
+
+func (s *bookImportService) importBatch(ctx context.Context, tx *gorm.DB, batch []*importRow) error {
+	isbns := make([]string, len(batch))
+	for i, row := range batch {
+		isbns[i] = row.input.ISBN
+	}
+
+	existing, err := s.bookRepo.FindByISBNsWithTx(tx, isbns)
+	if err != nil {
+		return err
+	}
+	byISBN := make(map[string]*models.Book, len(existing))
+	for i := range existing {
+		byISBN[existing[i].ISBN] = &existing[i]
+	}
+
+	for _, row := range batch {
+		savepoint := fmt.Sprintf("import_row_%d", row.result.Row)
+		err := s.txManager.WithSavepoint(tx, savepoint, func() error {
+			return s.importRow(ctx, tx, row, byISBN[row.input.ISBN])
+		})
+		if err != nil {
+			logging.FromContext(ctx).Warn("import row failed", "row", row.result.Row, "isbn", row.input.ISBN, "error", err)
+			row.fail(importFailureReason(err))
+		}
+	}
+
+	return nil
+}
+
+// importFailureReason - alasan gagal yang dikirim ke client. Error database mentah
+// (SQL state, nama constraint) hanya dicatat di log.
+func importFailureReason(err error) string {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return "isbn already exists"
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return "related record does not exist"
+	}
+	if domainErr := apperrors.As(err); domainErr != nil {
+		return domainErr.Message
+	}
+	return "database error"
+}
+
+// importRow - perbarui book jika sudah ada, jika nil buat buku baru beserta copy-nya
+func (s *bookImportService) importRow(ctx context.Context, tx *gorm.DB, row *importRow, book *models.Book) error {
+	isNew := book == nil
+	var before map[string]interface{}
+	if isNew {
+		book = &models.Book{ISBN: row.input.ISBN}
+	} else {
+		before = bookSnapshot(book)
+	}
+
+	links, err := s.relations.resolve(tx, row.input, isNew || row.input.Author != book.Author)
+	if err != nil {
+		return err
+	}
+
+	book.Title = row.input.Title
+	book.Author = row.input.Author
+	book.Description = row.input.Description
+	if links.authorName != "" {
+		book.Author = links.authorName
+	}
+
+	if isNew {
+		if err := s.bookRepo.CreateWithTx(tx, book); err != nil {
+			return err
+		}
+		if err := createInitialCopies(tx, s.copyRepo, book.ID, row.input.Stock); err != nil {
+			return err
+		}
+		book.Stock = row.input.Stock
+		row.result.Status = ImportRowCreated
+	} else {
+		if err := s.bookRepo.UpdateWithTx(tx, book); err != nil {
+			return err
+		}
+		row.result.Status = ImportRowUpdated
+	}
+	row.result.BookID = book.ID
+
+	if err := s.bookRepo.ReplaceRelationsWithTx(tx, book, links.authors, links.publishers, links.genres); err != nil {
+		return err
+	}
+
+	action := models.AuditActionUpdate
+	if isNew {
+		action = models.AuditActionCreate
+	}
+	return s.audit.recordBook(ctx, tx, book.ID, action, before, bookSnapshot(book))
+}
+
+func (row *importRow) fail(reason string) {
+	row.result.Status = ImportRowFailed
+	row.result.BookID = 0
+	row.result.Error = reason
+}
+
+// parseCSVRows - baris pertama adalah header. Kolom dikenali dari namanya
+// (title, author, isbn, description, stock), kolom lain diabaikan.
+func parseCSVRows(r io.Reader) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrInvalidImportHeader
+		}
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, required := range []string{"title", "author", "isbn"} {
+		if _, ok := columns[required]; !ok {
+			return nil, ErrInvalidImportHeader
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			row := &importRow{result: ImportRowResult{Row: parseErr.StartLine}}
+			row.fail(parseErr.Err.Error())
+			rows = append(rows, row)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &importRow{result: ImportRowResult{Row: line}}
+		rows = append(rows, row)
+
+		row.input = BookInput{
+			Title:			field(record, "title"),
+			Author:			field(record, "author"),
+			ISBN:			field(record, "isbn"),
+			Description:	field(record, "description"),
+		}
+		row.result.ISBN = row.input.ISBN
+
+		if stock := field(record, "stock"); stock != "" {
+			n, err := strconv.Atoi(stock)
+			if err != nil {
+				row.fail("stock must be a whole number")
+				continue
+			}
+			row.input.Stock = n
+		}
+	}
+
+	return rows, nil
+}
+
+// parseJSONLRows - satu object JSON per baris dengan field yang sama seperti
+// CreateBookRequest. Baris kosong dilewati.
+func parseJSONLRows(r io.Reader) ([]*importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []*importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := &importRow{result: ImportRowResult{Row: line}}
+		rows = append(rows, row)
+
+		if err := json.Unmarshal([]byte(text), &row.input); err != nil {
+			row.fail("invalid JSON: " + err.Error())
+			continue
+		}
+		row.input.Title = strings.TrimSpace(row.input.Title)
+		row.input.Author = strings.TrimSpace(row.input.Author)
+		row.input.ISBN = strings.TrimSpace(row.input.ISBN)
+		row.result.ISBN = row.input.ISBN
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
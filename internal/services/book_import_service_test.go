@@ -0,0 +1,188 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const importCSV = `title,author,isbn,description,stock
+Clean Code,Robert C. Martin,9780132350884,Handbook,2
+Refactoring,Martin Fowler,9780201485677,,0
+`
+
+// TestImport - CSV, buku baru dibuat dan buku lama diperbarui
+func TestImport_CSVCreateAndUpdate(t *testing.T) {
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	service := NewBookImportService(mockBookRepo, mockCopyRepo, mockAuthorRepo, nil, nil, nil, new(MockTransactionManager), 100)
+
+	existing := models.Book{ID: 3, Title: "Refactoring (1st)", Author: "Fowler", ISBN: "9780201485677", Stock: 4}
+
+	// Expectations
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, []string{"Robert C. Martin"}).Return([]models.Author{{ID: 1, Name: "Robert C. Martin"}}, nil)
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, []string{"Martin Fowler"}).Return([]models.Author{{ID: 2, Name: "Martin Fowler"}}, nil)
+	mockBookRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockBookRepo.On("FindByISBNsWithTx", mock.Anything, []string{"9780132350884", "9780201485677"}).Return([]models.Book{existing}, nil)
+	mockBookRepo.On("UpdateWithTx", mock.Anything, mock.MatchedBy(func(b *models.Book) bool {
+		return b.ID == 3 && b.Title == "Refactoring" && b.Author == "Martin Fowler"
+	})).Return(nil)
+	mockBookRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Book).ID = 8
+	}).Return(nil)
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil).Times(2)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(8)).Return(nil)
+
+	// Execute
+	report, err := service.Import(context.Background(), ImportFormatCSV, strings.NewReader(importCSV), false)
+
+	// Asserts
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, ImportRowResult{Row: 2, ISBN: "9780132350884", Status: ImportRowCreated, BookID: 8}, report.Rows[0])
+	assert.Equal(t, ImportRowResult{Row: 3, ISBN: "9780201485677", Status: ImportRowUpdated, BookID: 3}, report.Rows[1])
+	mockBookRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+// TestImport - baris tidak valid dan ISBN duplikat (juga ISBN-10 dari buku yang sama)
+// dilaporkan, baris lain tetap diimport
+func TestImport_InvalidRows(t *testing.T) {
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	service := NewBookImportService(mockBookRepo, mockCopyRepo, mockAuthorRepo, nil, nil, nil, new(MockTransactionManager), 100)
+
+	input := `isbn,title,author,stock
+9780132350884,Clean Code,Robert C. Martin,1
+123,Too Short,Someone,1
+0-13-235088-2,Clean Code Again,Robert C. Martin,1
+9780201485677,Refactoring,Martin Fowler,many
+`
+
+	// Expectations
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, mock.Anything).Return([]models.Author{}, nil)
+	mockBookRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockBookRepo.On("FindByISBNsWithTx", mock.Anything, []string{"9780132350884"}).Return([]models.Book{}, nil)
+	mockBookRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil).Once()
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil).Once()
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, mock.Anything).Return(nil)
+
+	// Execute
+	report, err := service.Import(context.Background(), ImportFormatCSV, strings.NewReader(input), false)
+
+	// Asserts
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 3, report.Failed)
+	assert.Equal(t, ImportRowFailed, report.Rows[1].Status)
+	assert.Equal(t, "ISBN must be a valid ISBN-10 or ISBN-13", report.Rows[1].Error)
+	assert.Equal(t, "duplicate ISBN, already on row 2", report.Rows[2].Error)
+	assert.Equal(t, "9780132350884", report.Rows[2].ISBN)
+	assert.Equal(t, "stock must be a whole number", report.Rows[3].Error)
+	mockBookRepo.AssertExpectations(t)
+}
+
+// TestImport - baris yang gagal di database hanya membatalkan baris itu sendiri,
+// error mentah tidak dikirim ke client
+func TestImport_RowFailureKeepsBatch(t *testing.T) {
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	service := NewBookImportService(mockBookRepo, mockCopyRepo, mockAuthorRepo, nil, nil, nil, new(MockTransactionManager), 100)
+
+	// Expectations
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, mock.Anything).Return([]models.Author{}, nil)
+	mockBookRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockBookRepo.On("FindByISBNsWithTx", mock.Anything, mock.Anything).Return([]models.Book{}, nil)
+	mockBookRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Book).ID = 7
+	}).Return(nil).Once()
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, mock.Anything).Return(nil)
+	mockBookRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(gorm.ErrDuplicatedKey).Once()
+
+	// Execute
+	report, err := service.Import(context.Background(), ImportFormatCSV, strings.NewReader(importCSV), false)
+
+	// Asserts
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, ImportRowCreated, report.Rows[0].Status)
+	assert.Equal(t, uint(7), report.Rows[0].BookID)
+	assert.Equal(t, ImportRowFailed, report.Rows[1].Status)
+	assert.Equal(t, "isbn already exists", report.Rows[1].Error)
+	assert.Zero(t, report.Rows[1].BookID)
+}
+
+// TestImport - error database lain tidak bocor ke report
+func TestImport_RowDatabaseError(t *testing.T) {
+	assert.Equal(t, "database error", importFailureReason(errors.New(`pq: relation "books" does not exist`)))
+	assert.Equal(t, ErrInvalidName.Message, importFailureReason(ErrInvalidName))
+}
+
+// TestImport - dry run, JSON Lines
+func TestImport_DryRunJSONL(t *testing.T) {
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	service := NewBookImportService(mockBookRepo, mockCopyRepo, mockAuthorRepo, nil, nil, nil, new(MockTransactionManager), 1)
+
+	input := `{"title":"Clean Code","author":"Robert C. Martin","isbn":"9780132350884","stock":1}
+
+{"title":"Refactoring","author":"Martin Fowler","isbn":"9780201485677"}
+{"title":
+`
+
+	// Expectations, satu batch per baris
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, mock.Anything).Return([]models.Author{}, nil)
+	mockBookRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockBookRepo.On("FindByISBNsWithTx", mock.Anything, []string{"9780132350884"}).Return([]models.Book{}, nil).Once()
+	mockBookRepo.On("FindByISBNsWithTx", mock.Anything, []string{"9780201485677"}).Return([]models.Book{{ID: 3, ISBN: "9780201485677"}}, nil).Once()
+	mockBookRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Book).ID = 8
+	}).Return(nil)
+	mockBookRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.BookCopy")).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(8)).Return(nil)
+
+	// Execute
+	report, err := service.Import(context.Background(), ImportFormatJSONL, strings.NewReader(input), true)
+
+	// Asserts
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 3, report.Rows[1].Row)
+	assert.Zero(t, report.Rows[0].BookID)
+	assert.Equal(t, 4, report.Rows[2].Row)
+	assert.Contains(t, report.Rows[2].Error, "invalid JSON")
+	mockBookRepo.AssertExpectations(t)
+}
+
+// TestImport - header CSV tanpa kolom wajib dan format tidak dikenal
+func TestImport_InvalidInput(t *testing.T) {
+	service := NewBookImportService(new(MockBookRepository), new(MockBookCopyRepository), nil, nil, nil, nil, new(MockTransactionManager), 100)
+
+	_, err := service.Import(context.Background(), ImportFormatCSV, strings.NewReader("name,writer\nA,B\n"), false)
+	assert.ErrorIs(t, err, ErrInvalidImportHeader)
+
+	_, err = service.Import(context.Background(), ImportFormat("xml"), strings.NewReader(""), false)
+	assert.ErrorIs(t, err, ErrUnsupportedImportFormat)
+}
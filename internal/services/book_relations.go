@@ -0,0 +1,125 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidName - nama author, publisher atau genre tanpa huruf maupun angka
+var ErrInvalidName = apperrors.Validation("invalid_name", "name must contain at least one letter or digit")
+
+// ErrInvalidRelation - author_ids, publisher_ids atau genre_ids berisi ID yang tidak ada.
+// Dibungkus bersama ErrAuthorNotFound dan sejenisnya supaya menjadi 400, bukan 404.
+var ErrInvalidRelation = apperrors.Validation("invalid_relation", "invalid book relation")
+
+// authorSeparator - pemisah beberapa author dalam satu string, sama dengan
+// pola regexp_split_to_table di migration 0004
+var authorSeparator = regexp.MustCompile(`(?i)\s*(,|;|&|\sand\s)\s*`)
+
+// SplitAuthorNames - "Martin Fowler & Kent Beck" menjadi ["Martin Fowler", "Kent Beck"]
+func SplitAuthorNames(author string) []string {
+	var names []string
+	for _, name := range authorSeparator.Split(author, -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// bookRelations - mengubah AuthorIDs, PublisherIDs, GenreIDs dan string author
+// dari BookInput menjadi entity yang dihubungkan ke buku
+type bookRelations struct {
+	authorRepo		repository.AuthorRepository
+	publisherRepo	repository.PublisherRepository
+	genreRepo		repository.GenreRepository
+}
+
+// bookLinks - hasil resolve. Slice nil berarti relasi tersebut tidak diubah.
+type bookLinks struct {
+	authors		[]models.Author
+	publishers	[]models.Publisher
+	genres		[]models.Genre
+	authorName	string	// nama tampilan dari AuthorIDs, kosong = pakai BookInput.Author
+}
+
+// resolve - AuthorIDs diutamakan; tanpa AuthorIDs dan dengan parseAuthor = true,
+// string author dipecah lalu dicari atau dibuatkan author-nya
+func (r bookRelations) resolve(tx *gorm.DB, input BookInput, parseAuthor bool) (*bookLinks, error) {
+	links := &bookLinks{}
+
+	switch {
+	case input.AuthorIDs != nil:
+		ids := uniqueIDs(input.AuthorIDs)
+		authors, err := r.authorRepo.FindByIDsWithTx(tx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(authors) != len(ids) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRelation, ErrAuthorNotFound)
+		}
+
+		// Urutan author mengikuti request, bukan urutan dari database
+		byID := make(map[uint]models.Author, len(authors))
+		for _, author := range authors {
+			byID[author.ID] = author
+		}
+		names := make([]string, len(ids))
+		links.authors = make([]models.Author, len(ids))
+		for i, id := range ids {
+			links.authors[i] = byID[id]
+			names[i] = byID[id].Name
+		}
+		links.authorName = strings.Join(names, ", ")
+	case parseAuthor:
+		authors, err := r.authorRepo.FindOrCreateByNamesWithTx(tx, SplitAuthorNames(input.Author))
+		if err != nil {
+			return nil, err
+		}
+		links.authors = append([]models.Author{}, authors...)
+	}
+
+	if input.PublisherIDs != nil {
+		ids := uniqueIDs(input.PublisherIDs)
+		publishers, err := r.publisherRepo.FindByIDsWithTx(tx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(publishers) != len(ids) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRelation, ErrPublisherNotFound)
+		}
+		links.publishers = append([]models.Publisher{}, publishers...)
+	}
+
+	if input.GenreIDs != nil {
+		ids := uniqueIDs(input.GenreIDs)
+		genres, err := r.genreRepo.FindByIDsWithTx(tx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(genres) != len(ids) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRelation, ErrGenreNotFound)
+		}
+		links.genres = append([]models.Genre{}, genres...)
+	}
+
+	return links, nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
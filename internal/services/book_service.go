@@ -1,55 +1,171 @@
 package services
 
 import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	isbnpkg "book-api/internal/isbn"
+	"book-api/internal/metadata"
 	"book-api/internal/models"
 	"book-api/internal/repository"
+	"book-api/internal/utils"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSort			= apperrors.Validation("invalid_sort", "invalid sort field")
+	ErrISBNExists			= apperrors.Conflict("isbn_exists", "book with this ISBN already exists")
+	ErrMetadataDisabled		= apperrors.Unavailable("metadata_disabled", "metadata lookup is disabled")
+	ErrBookHasActiveBorrows	= apperrors.Conflict("book_has_active_borrows", "book has copies on loan, return them before deleting")
+	ErrBookVersionMismatch	= apperrors.PreconditionFailed("book_version_mismatch", "book has changed since it was read, reload it and try again")
+	ErrBookModified			= apperrors.Conflict("book_modified", "book was modified by another request, reload it and try again")
+	ErrInvalidBookPatch		= apperrors.Validation("invalid_patch", "invalid merge patch")
+	ErrNegativeStock		= apperrors.Validation("negative_stock", "stock cannot be negative")
 )
 
+// bookPatchRelations - field relasi yang bisa dikirim di PATCH; null menghapus semua link
+var bookPatchRelations = []string{"author_ids", "publisher_ids", "genre_ids"}
+
+// bookSortFields - field yang boleh dipakai di parameter sort
+var bookSortFields = map[string]bool{
+	"title":		true,
+	"author":		true,
+	"stock":		true,
+	"created_at":	true,
+	"updated_at":	true,
+	"relevance":	true,
+}
+
+// BookInput - data buku dari request API maupun baris import. Rule validasi di sini
+// dipakai oleh CreateBookRequest dan BookImportService.
+type BookInput struct {
+	Title string `json:"title" validate:"required,min=1,max=200"`
+	Author string `json:"author" validate:"required_without=AuthorIDs,max=100"` // nama tampilan; tanpa AuthorIDs dipecah menjadi entity author
+	ISBN string `json:"isbn" validate:"required,isbn"` // ISBN-10 atau ISBN-13, disimpan sebagai ISBN-13
+	Description string `json:"description" validate:"max=1000"`
+	Stock int `json:"stock" validate:"gte=0,lte=500"` // jumlah copy awal, barcode dibuat otomatis
+	AuthorIDs []uint `json:"author_ids,omitempty" validate:"omitempty,min=1,max=20,dive,gt=0"` // jika diisi, Author diganti gabungan nama author ini
+	PublisherIDs []uint `json:"publisher_ids,omitempty" validate:"omitempty,max=20,dive,gt=0"`
+	GenreIDs []uint `json:"genre_ids,omitempty" validate:"omitempty,max=20,dive,gt=0"`
+}
+
+// BookLookup - field CreateBookRequest yang sudah diisi dari metadata provider,
+// beserta data lengkap dari provider
+type BookLookup struct {
+	BookInput
+	Metadata *metadata.BookMetadata `json:"metadata"`
+}
+
 type BookService interface {
-	CreateBook(title, author, isbn, description string, stock int) (*models.Book, error)
-	GetAllBooks(page, pageSize int) ([]models.Book, int64, error)
+	CreateBook(ctx context.Context, input BookInput) (*models.Book, error)
+	GetAllBooks(filter models.BookFilter, page, pageSize int) ([]models.Book, int64, error)
 	GetBookByID(id uint) (*models.Book, error)
-	UpdateBook(id uint, title, author, isbn, description string, stock int) (*models.Book, error)
-	DeleteBook(id uint) error
+	GetBookByISBN(isbn string) (*models.Book, error)
+	LookupMetadata(ctx context.Context, isbn string) (*BookLookup, error)
+	FillMissing(ctx context.Context, input *BookInput) error
+	UpdateBook(ctx context.Context, id uint, input BookInput, ifMatch string) (*models.Book, error)
+	PatchBook(ctx context.Context, id uint, patch []byte, ifMatch string) (*models.Book, error)
+	DeleteBook(ctx context.Context, id uint) error
 }
 
 type bookService struct {
 	bookRepo repository.BookRepository
+	copyRepo repository.BookCopyRepository
+	borrowRepo repository.BorrowRepository
+	reservationRepo repository.ReservationRepository
+	txManager database.TransactionManager
+	metadata metadata.Provider
+	relations bookRelations
+	audit auditTrail
 }
 
-func NewBookService(bookRepo repository.BookRepository) BookService {
-	return &bookService{bookRepo: bookRepo}
+// NewBookService - metadataProvider boleh nil jika lookup metadata dimatikan
+func NewBookService(
+	bookRepo repository.BookRepository,
+	copyRepo repository.BookCopyRepository,
+	borrowRepo repository.BorrowRepository,
+	reservationRepo repository.ReservationRepository,
+	authorRepo repository.AuthorRepository,
+	publisherRepo repository.PublisherRepository,
+	genreRepo repository.GenreRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager database.TransactionManager,
+	metadataProvider metadata.Provider,
+) BookService {
+	return &bookService{
+		bookRepo: bookRepo,
+		copyRepo: copyRepo,
+		borrowRepo: borrowRepo,
+		reservationRepo: reservationRepo,
+		txManager: txManager,
+		metadata: metadataProvider,
+		relations: bookRelations{authorRepo: authorRepo, publisherRepo: publisherRepo, genreRepo: genreRepo},
+		audit: auditTrail{repo: auditRepo},
+	}
 }
 
-func (s *bookService) CreateBook(title, author, isbn, description string, stock int) (*models.Book, error) {
+// CreateBook - stock adalah jumlah copy awal, masing-masing dibuatkan barcode otomatis.
+// Author diambil dari AuthorIDs, atau dari string author yang dipecah per nama.
+func (s *bookService) CreateBook(ctx context.Context, input BookInput) (*models.Book, error) {
 	// Validasi stock tidak boleh negatif
-	if stock < 0 {
-		return nil, errors.New("stock cannot be nagtive")
+	if input.Stock < 0 {
+		return nil, ErrNegativeStock
+	}
+
+	// ISBN-10 dan ISBN-13 dari buku yang sama harus dianggap sama
+	canonical, err := isbnpkg.Canonical(input.ISBN)
+	if err != nil {
+		return nil, err
 	}
 
 	// Cek apakah ISBN sudah ada
-	existingBook, _ := s.bookRepo.FindByISBN(isbn)
+	existingBook, _ := s.bookRepo.FindByISBN(canonical)
 	if existingBook != nil {
-		return nil, errors.New("book with this ISBN already exists")
+		return nil, ErrISBNExists
 	}
 
 	newBook := models.Book{
-		Title: title,
-		Author: author,
-		ISBN: isbn,
-		Description: description,
-		Stock: stock,
+		Title: input.Title,
+		Author: input.Author,
+		ISBN: canonical,
+		Description: input.Description,
 	}
 
-	if err := s.bookRepo.Create(&newBook); err != nil {
+	err = s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		links, err := s.relations.resolve(tx, input, true)
+		if err != nil {
+			return err
+		}
+		if links.authorName != "" {
+			newBook.Author = links.authorName
+		}
+
+		if err := s.bookRepo.CreateWithTx(tx, &newBook); err != nil {
+			return err
+		}
+		if err := s.bookRepo.ReplaceRelationsWithTx(tx, &newBook, links.authors, links.publishers, links.genres); err != nil {
+			return err
+		}
+		if err := createInitialCopies(tx, s.copyRepo, newBook.ID, input.Stock); err != nil {
+			return err
+		}
+
+		newBook.Stock = input.Stock
+		return s.audit.recordBook(ctx, tx, newBook.ID, models.AuditActionCreate, nil, bookSnapshot(&newBook))
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &newBook, nil
 }
 
-func (s *bookService) GetAllBooks(page, pageSize int) ([]models.Book, int64, error) {
+func (s *bookService) GetAllBooks(filter models.BookFilter, page, pageSize int) ([]models.Book, int64, error) {
 	// Default pagination
 	if page < 1 {
 		page = 1
@@ -60,12 +176,13 @@ func (s *bookService) GetAllBooks(page, pageSize int) ([]models.Book, int64, err
 
 	offset := (page - 1) * pageSize
 
-	books, err := s.bookRepo.FindAll(pageSize, offset)
+	books, err := s.bookRepo.FindAll(filter, pageSize, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	total, err := s.bookRepo.Count()
+	// Total harus memakai filter yang sama supaya pagination benar
+	total, err := s.bookRepo.Count(filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -73,45 +190,320 @@ func (s *bookService) GetAllBooks(page, pageSize int) ([]models.Book, int64, err
 	return books, total, nil
 }
 
+// bookNotFound - ErrBookNotFound untuk buku yang tidak ada, error lain diteruskan apa adanya
+func bookNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrBookNotFound
+	}
+	return err
+}
+
 func (s *bookService) GetBookByID(id uint) (*models.Book, error) {
 	book, err := s.bookRepo.FindByID(id)
+	if err != nil {
+		return nil, bookNotFound(err)
+	}
+	return book, nil
+}
+
+// GetBookByISBN - cari dengan ISBN-10 atau ISBN-13, dengan atau tanpa pemisah
+func (s *bookService) GetBookByISBN(isbn string) (*models.Book, error) {
+	canonical, err := isbnpkg.Canonical(isbn)
 	if err != nil {
 		return nil, err
 	}
+	book, err := s.bookRepo.FindByISBN(canonical)
+	if err != nil {
+		return nil, bookNotFound(err)
+	}
 	return book, nil
 }
 
-func (s *bookService) UpdateBook(id uint, title, author, isbn, description string, stock int) (*models.Book, error) {
+// LookupMetadata - field buku untuk ISBN ini dari metadata provider
+func (s *bookService) LookupMetadata(ctx context.Context, isbn string) (*BookLookup, error) {
+	canonical, err := isbnpkg.Canonical(isbn)
+	if err != nil {
+		return nil, err
+	}
+	if s.metadata == nil {
+		return nil, ErrMetadataDisabled
+	}
+
+	book, err := s.metadata.Lookup(ctx, canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := &BookLookup{BookInput: BookInput{ISBN: canonical}, Metadata: book}
+	fillFromMetadata(&lookup.BookInput, book)
+	return lookup, nil
+}
+
+// FillMissing - isi title, author dan description yang masih kosong dari metadata provider.
+// Field yang sudah diisi user tidak pernah ditimpa.
+func (s *bookService) FillMissing(ctx context.Context, input *BookInput) error {
+	if input.Title != "" && input.Author != "" && input.Description != "" {
+		return nil
+	}
+
+	lookup, err := s.LookupMetadata(ctx, input.ISBN)
+	if err != nil {
+		return err
+	}
+
+	if input.Title == "" {
+		input.Title = lookup.Title
+	}
+	if input.Author == "" {
+		input.Author = lookup.Author
+	}
+	if input.Description == "" {
+		input.Description = lookup.Description
+	}
+	return nil
+}
+
+// fillFromMetadata - potong sesuai batas validasi BookInput supaya hasilnya langsung bisa disimpan
+func fillFromMetadata(input *BookInput, book *metadata.BookMetadata) {
+	input.Title = truncateRunes(book.Title, 200)
+	input.Author = truncateRunes(strings.Join(book.Authors, ", "), 100)
+	input.Description = truncateRunes(book.Description, 1000)
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max]))
+}
+
+// UpdateBook - stock tidak ikut diubah, jumlahnya mengikuti copy yang available.
+// AuthorIDs, PublisherIDs dan GenreIDs yang nil tidak mengubah relasi; tanpa AuthorIDs,
+// author hanya dipecah ulang jika string author berubah. ifMatch adalah isi header
+// If-Match; jika diisi, harus sama dengan ETag buku saat ini.
+func (s *bookService) UpdateBook(ctx context.Context, id uint, input BookInput, ifMatch string) (*models.Book, error) {
 	// Cek apakah buku ada
 	book, err := s.bookRepo.FindByID(id)
+	if err != nil {
+		return nil, bookNotFound(err)
+	}
+
+	return s.update(ctx, book, input, ifMatch)
+}
+
+// PatchBook - JSON Merge Patch (RFC 7396) atas title, author, isbn dan description.
+// author_ids, publisher_ids dan genre_ids di patch mengganti relasi seperti pada UpdateBook,
+// dengan null menghapus semua link. Hasil patch divalidasi dengan rule yang sama dengan PUT.
+func (s *bookService) PatchBook(ctx context.Context, id uint, patch []byte, ifMatch string) (*models.Book, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("%w: body must be a JSON object", ErrInvalidBookPatch)
+	}
+
+	book, err := s.bookRepo.FindByID(id)
+	if err != nil {
+		return nil, bookNotFound(err)
+	}
+
+	doc, err := json.Marshal(map[string]string{
+		"title":		book.Title,
+		"author":		book.Author,
+		"isbn":			book.ISBN,
+		"description":	book.Description,
+	})
 	if err != nil {
 		return nil, err
 	}
+	merged, err := utils.MergePatch(doc, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBookPatch, err)
+	}
 
-	// Validasi stock tidak boleh negatif
-	if stock < 0 {
-		return nil, errors.New("stock cannot be negative")
+	var input BookInput
+	if err := json.Unmarshal(merged, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBookPatch, err)
+	}
+	// Stock mengikuti copy, tidak bisa diubah lewat PATCH
+	input.Stock = 0
+
+	// Merge patch menghapus field yang null, di sini artinya relasinya dikosongkan
+	for _, name := range bookPatchRelations {
+		if raw, ok := fields[name]; ok && string(raw) == "null" {
+			switch name {
+			case "author_ids":
+				input.AuthorIDs = []uint{}
+			case "publisher_ids":
+				input.PublisherIDs = []uint{}
+			case "genre_ids":
+				input.GenreIDs = []uint{}
+			}
+		}
+	}
+
+	if err := utils.ValidateStruct(input); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBookPatch, err)
+	}
+
+	return s.update(ctx, book, input, ifMatch)
+}
+
+// update - simpan input ke book yang sudah dibaca. Jika buku diubah request lain setelah
+// dibaca, UpdateWithTx gagal dan tidak ada perubahan yang tersimpan.
+func (s *bookService) update(ctx context.Context, book *models.Book, input BookInput, ifMatch string) (*models.Book, error) {
+	if ifMatch != "" && !utils.MatchETag(ifMatch, book.ETag(), false) {
+		return nil, ErrBookVersionMismatch
+	}
+
+	canonical, err := isbnpkg.Canonical(input.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	// ISBN baru tidak boleh dipakai buku lain
+	if canonical != book.ISBN {
+		if existingBook, _ := s.bookRepo.FindByISBN(canonical); existingBook != nil {
+			return nil, ErrISBNExists
+		}
 	}
 
-	// Update fileds
-	book.Title = title
-	book.Author = author
-	book.ISBN = isbn
-	book.Description = description
-	book.Stock = stock
+	err = s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		links, err := s.relations.resolve(tx, input, input.Author != book.Author)
+		if err != nil {
+			return err
+		}
+		before := bookSnapshot(book)
 
-	if err := s.bookRepo.Update(book); err != nil {
+		// Update fileds
+		book.Title = input.Title
+		book.Author = input.Author
+		book.ISBN = canonical
+		book.Description = input.Description
+		if links.authorName != "" {
+			book.Author = links.authorName
+		}
+
+		if err := s.bookRepo.UpdateWithTx(tx, book); err != nil {
+			return err
+		}
+		if err := s.bookRepo.ReplaceRelationsWithTx(tx, book, links.authors, links.publishers, links.genres); err != nil {
+			return err
+		}
+
+		return s.audit.recordBook(ctx, tx, book.ID, models.AuditActionUpdate, before, bookSnapshot(book))
+	})
+	if errors.Is(err, repository.ErrStaleBook) {
+		if ifMatch != "" {
+			return nil, ErrBookVersionMismatch
+		}
+		return nil, ErrBookModified
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	return book, nil
 }
 
-func (s *bookService) DeleteBook(id uint) error {
-	// Cek apakah buku ada
-	if _, err := s.bookRepo.FindByID(id); err != nil {
-		return err
+// DeleteBook - buku dipindah ke trash (soft delete) dan bisa di-restore lewat BookTrashService.
+// Ditolak selama masih ada copy yang dipinjam; buku di-LOCK supaya tidak ada peminjaman baru di tengah jalan.
+// Hold yang masih aktif dibatalkan di transaction yang sama.
+func (s *bookService) DeleteBook(ctx context.Context, id uint) error {
+	return s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// Cek apakah buku ada
+		book, err := s.bookRepo.FindByIDWithLock(tx, id)
+		if err != nil {
+			return bookNotFound(err)
+		}
+
+		active, err := s.borrowRepo.CountActiveByBookIDWithTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrBookHasActiveBorrows
+		}
+
+		before := book.Stock
+		released, err := s.cancelHoldsWithTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if released > 0 {
+			if book, err = s.bookRepo.FindByIDWithLock(tx, id); err != nil {
+				return bookNotFound(err)
+			}
+			if err := s.audit.recordStock(ctx, tx, id, stockChange(before, book.Stock)); err != nil {
+				return err
+			}
+		}
+
+		if err := s.bookRepo.DeleteWithTx(tx, id); err != nil {
+			return err
+		}
+		return s.audit.recordBook(ctx, tx, id, models.AuditActionDelete, bookSnapshot(book), nil)
+	})
+}
+
+// cancelHoldsWithTx - batalkan hold aktif sebuah buku dan kembalikan copy yang disisihkan
+// (on_hold) menjadi available. Mengembalikan jumlah copy yang dilepas.
+func (s *bookService) cancelHoldsWithTx(tx *gorm.DB, bookID uint) (int, error) {
+	holds, err := s.reservationRepo.FindActiveByBookWithTx(tx, bookID)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range holds {
+		hold := &holds[i]
+		hold.Status = models.ReservationStatusCancelled
+		if err := s.reservationRepo.UpdateWithTx(tx, hold); err != nil {
+			return 0, err
+		}
+		if hold.CopyID == nil {
+			continue
+		}
+
+		bookCopy, err := s.copyRepo.FindByIDWithLock(tx, *hold.CopyID)
+		if err != nil {
+			return 0, err
+		}
+		if bookCopy.Status != models.CopyStatusOnHold {
+			continue
+		}
+		bookCopy.Status = models.CopyStatusAvailable
+		if err := s.copyRepo.UpdateWithTx(tx, bookCopy); err != nil {
+			return 0, err
+		}
+		released++
+	}
+
+	if released > 0 {
+		if err := s.copyRepo.SyncBookStockWithTx(tx, bookID); err != nil {
+			return 0, err
+		}
+	}
+	return released, nil
+}
+
+// ParseBookSort - parse parameter sort seperti "title,-created_at" (prefix "-" = descending)
+func ParseBookSort(raw string) ([]models.SortField, error) {
+	var fields []models.SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := models.SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = models.SortField{Field: strings.TrimPrefix(part, "-"), Desc: true}
+		}
+
+		if !bookSortFields[field.Field] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSort, field.Field)
+		}
+		fields = append(fields, field)
 	}
-	
-	return s.bookRepo.Delete(id)
+	return fields, nil
 }
\ No newline at end of file
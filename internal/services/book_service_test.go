@@ -1,8 +1,13 @@
 package services
 
 import (
+	"book-api/internal/isbn"
+	"book-api/internal/metadata"
 	"book-api/internal/models"
+	"book-api/internal/repository"
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,8 +25,13 @@ func (m *MockBookRepository) Create(book *models.Book) error {
 	return args.Error(0)
 }
 
-func (m *MockBookRepository) FindAll(limit, offset int) ([]models.Book, error) {
-	args := m.Called(limit, offset)
+func (m *MockBookRepository) CreateWithTx(tx *gorm.DB, book *models.Book) error {
+	args := m.Called(tx, book)
+	return args.Error(0)
+}
+
+func (m *MockBookRepository) FindAll(filter models.BookFilter, limit, offset int) ([]models.Book, error) {
+	args := m.Called(filter, limit, offset)
 	return args.Get(0).([]models.Book), args.Error(1)
 }
 
@@ -49,6 +59,11 @@ func (m *MockBookRepository) FindByISBN(isbn string) (*models.Book, error) {
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
+func (m *MockBookRepository) FindByISBNsWithTx(tx *gorm.DB, isbns []string) ([]models.Book, error) {
+	args := m.Called(tx, isbns)
+	return args.Get(0).([]models.Book), args.Error(1)
+}
+
 func (m *MockBookRepository) Update(book *models.Book) error {
 	args := m.Called(book)
 	return args.Error(0)
@@ -59,78 +74,152 @@ func (m *MockBookRepository) UpdateWithTx(tx *gorm.DB, book *models.Book) error
 	return args.Error(0)
 }
 
+func (m *MockBookRepository) ReplaceRelationsWithTx(tx *gorm.DB, book *models.Book, authors []models.Author, publishers []models.Publisher, genres []models.Genre) error {
+	args := m.Called(tx, book, authors, publishers, genres)
+	return args.Error(0)
+}
+
+func (m *MockBookRepository) UpdateCoverWithTx(tx *gorm.DB, id uint, coverKey, coverType string) error {
+	args := m.Called(tx, id, coverKey, coverType)
+	return args.Error(0)
+}
+
 func (m *MockBookRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockBookRepository) Count() (int64, error) {
+func (m *MockBookRepository) DeleteWithTx(tx *gorm.DB, id uint) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
+func (m *MockBookRepository) FindDeleted(limit, offset int) ([]models.Book, error) {
+	args := m.Called(limit, offset)
+	return args.Get(0).([]models.Book), args.Error(1)
+}
+
+func (m *MockBookRepository) CountDeleted() (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
-// Test CreateBook - Success
+func (m *MockBookRepository) FindDeletedByIDWithLock(tx *gorm.DB, id uint) (*models.Book, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Book), args.Error(1)
+}
+
+func (m *MockBookRepository) RestoreWithTx(tx *gorm.DB, id uint) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
+func (m *MockBookRepository) PurgeWithTx(tx *gorm.DB, id uint) error {
+	args := m.Called(tx, id)
+	return args.Error(0)
+}
+
+func (m *MockBookRepository) Count(filter models.BookFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Test CreateBook - Success, stock menjadi copy dengan barcode otomatis dan ISBN-10 disimpan sebagai ISBN-13
 func TestCreateBook_Success(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	service := NewBookService(mockRepo, mockCopyRepo, nil, nil, mockAuthorRepo, nil, nil, nil, new(MockTransactionManager), nil)
+
+	author := models.Author{ID: 3, Name: "Test Author", NameKey: "testauthor"}
 
 	// Setup mock
-	mockRepo.On("FindByISBN", "123456").Return(nil, errors.New("Not Found"))
-	mockRepo.On("Create", mock.AnythingOfType("*models.Book")).Return(nil)
+	mockRepo.On("FindByISBN", "9780132350884").Return(nil, errors.New("Not Found"))
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, []string{"Test Author"}).Return([]models.Author{author}, nil)
+	mockRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.AnythingOfType("*models.Book"), []models.Author{author}, []models.Publisher(nil), []models.Genre(nil)).Return(nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Book).ID = 7
+	}).Return(nil)
+	mockCopyRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(c *models.BookCopy) bool {
+		return c.BookID == 7 && c.Status == models.CopyStatusAvailable
+	})).Return(nil).Times(10)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(7)).Return(nil)
 
 	// Execute
-	book, err := service.CreateBook("Test Book", "Test Author", "123456", "Description", 10)
+	book, err := service.CreateBook(context.Background(), BookInput{Title: "Test Book", Author: "Test Author", ISBN: "0-13-235088-2", Description: "Description", Stock: 10})
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, book)
 	assert.Equal(t, "Test Book", book.Title)
 	assert.Equal(t, 10, book.Stock)
+	assert.Equal(t, "9780132350884", book.ISBN)
 	mockRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
+	mockCopyRepo.AssertCalled(t, "CreateWithTx", mock.Anything, mock.MatchedBy(func(c *models.BookCopy) bool {
+		return c.Barcode == "BK000007-010"
+	}))
 }
 
 // Test CreateBook - ISBN Already Exists
 func TestCreateBook_ISBNAlreadyExists(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
 
 	existingBook := &models.Book{
 		ID: 1,
-		ISBN: "123456",
+		ISBN: "9780132350884",
 	}
 
 	// Setup mock
-	mockRepo.On("FindByISBN", "123456").Return(existingBook, nil)
+	mockRepo.On("FindByISBN", "9780132350884").Return(existingBook, nil)
 
 	// Execute
-	book, err := service.CreateBook("Test Book", "Test Author", "123456", "Description", 10)
+	book, err := service.CreateBook(context.Background(), BookInput{Title: "Test Book", Author: "Test Author", ISBN: "978-0-13-235088-4", Description: "Description", Stock: 10})
 
 	// Assert
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrISBNExists)
 	assert.Nil(t, book)
 	assert.Equal(t, "book with this ISBN already exists", err.Error())
 	mockRepo.AssertExpectations(t)
 }
 
+// Test CreateBook - Invalid ISBN checksum
+func TestCreateBook_InvalidISBN(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	// Execute
+	book, err := service.CreateBook(context.Background(), BookInput{Title: "Test Book", Author: "Test Author", ISBN: "978-0-13-235088-5", Description: "Description", Stock: 1})
+
+	// Assert
+	assert.ErrorIs(t, err, isbn.ErrInvalidChecksum)
+	assert.Nil(t, book)
+	mockRepo.AssertNotCalled(t, "FindByISBN", mock.Anything)
+}
+
 // Test CreateBook - Negative Stock
 func TestCreateBook_NegativeStock(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
 
 	// Execute dengan stock negatif
-	book, err := service.CreateBook("Test Book", "Test Author", "123456", "Description", -5)
+	book, err := service.CreateBook(context.Background(), BookInput{Title: "Test Book", Author: "Test Author", ISBN: "123456", Description: "Description", Stock: -5})
 
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, book)
-	assert.Equal(t, "stock cannot be nagtive", err.Error())
+	assert.ErrorIs(t, err, ErrNegativeStock)
 	// Tidak perlu cek mock karena validation gagal sebelum hit repository
 }
 
 // Test GetAllBooks - Success
 func TestGetAllBooks_Success(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
 
 	mockBooks := []models.Book{
 		{ID: 1, Title: "Book 1"},
@@ -138,11 +227,11 @@ func TestGetAllBooks_Success(t *testing.T) {
 	}
 
 	// Setup mock
-	mockRepo.On("FindAll", 10, 0).Return(mockBooks, nil)
-	mockRepo.On("Count").Return(int64(2), nil)
+	mockRepo.On("FindAll", models.BookFilter{}, 10, 0).Return(mockBooks, nil)
+	mockRepo.On("Count", models.BookFilter{}).Return(int64(2), nil)
 
 	// Execute
-	books, total, err := service.GetAllBooks(0, 10)
+	books, total, err := service.GetAllBooks(models.BookFilter{}, 0, 10)
 
 	// Assert
 	assert.NoError(t, err)
@@ -151,10 +240,51 @@ func TestGetAllBooks_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// Test GetAllBooks - Count uses the same filter
+func TestGetAllBooks_WithFilter(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	inStock := true
+	filter := models.BookFilter{
+		Query: "golang",
+		Author: "pike",
+		InStock: &inStock,
+		Sort: []models.SortField{{Field: "title"}},
+	}
+
+	// Setup mock
+	mockRepo.On("FindAll", filter, 5, 5).Return([]models.Book{{ID: 6, Title: "Go"}}, nil)
+	mockRepo.On("Count", filter).Return(int64(6), nil)
+
+	// Execute
+	books, total, err := service.GetAllBooks(filter, 2, 5)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(books))
+	assert.Equal(t, int64(6), total)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test ParseBookSort
+func TestParseBookSort(t *testing.T) {
+	fields, err := ParseBookSort("title, -created_at")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SortField{{Field: "title"}, {Field: "created_at", Desc: true}}, fields)
+
+	fields, err = ParseBookSort("")
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+
+	_, err = ParseBookSort("title,password")
+	assert.ErrorIs(t, err, ErrInvalidSort)
+}
+
 // Test GetBookByID - Success
 func TestGetBookByID_Success(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
 
 	mockBook := &models.Book{
 		ID: 1,
@@ -177,7 +307,7 @@ func TestGetBookByID_Success(t *testing.T) {
 // Test GetBookByID - Not Found
 func TestGetBookByID_NotFound(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
 
 	// Setup mock
 	mockRepo.On("FindByID", uint(999)).Return(nil, errors.New("book not found"))
@@ -192,10 +322,322 @@ func TestGetBookByID_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-// Test DeleteBook - Success
+// Test GetBookByISBN - ISBN-10 dan ISBN-13 menemukan buku yang sama
+func TestGetBookByISBN_EitherForm(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	expectedBook := &models.Book{ID: 1, ISBN: "9780132350884"}
+
+	// Setup mock
+	mockRepo.On("FindByISBN", "9780132350884").Return(expectedBook, nil).Twice()
+
+	// Execute
+	byISBN10, err10 := service.GetBookByISBN("0-13-235088-2")
+	byISBN13, err13 := service.GetBookByISBN("9780132350884")
+
+	// Assert
+	assert.NoError(t, err10)
+	assert.NoError(t, err13)
+	assert.Equal(t, expectedBook, byISBN10)
+	assert.Equal(t, expectedBook, byISBN13)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test UpdateBook - ISBN sudah dipakai buku lain
+func TestUpdateBook_ISBNTaken(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, ISBN: "9780201485677"}, nil)
+	mockRepo.On("FindByISBN", "9780132350884").Return(&models.Book{ID: 2, ISBN: "9780132350884"}, nil)
+
+	// Execute
+	book, err := service.UpdateBook(context.Background(), uint(1), BookInput{Title: "Title", Author: "Author", ISBN: "0132350882"}, "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrISBNExists)
+	assert.Nil(t, book)
+	mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// Test PatchBook - hanya field yang dikirim yang berubah, null mengosongkan description
+func TestPatchBook_Success(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockGenreRepo := new(MockGenreRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, mockGenreRepo, nil, new(MockTransactionManager), nil)
+
+	existing := &models.Book{ID: 1, Title: "Clean Cod", Author: "Robert C. Martin", ISBN: "9780132350884", Description: "Old", Version: 3, Stock: 2}
+	mockRepo.On("FindByID", uint(1)).Return(existing, nil)
+	mockGenreRepo.On("FindByIDsWithTx", mock.Anything, []uint{}).Return([]models.Genre{}, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+	mockRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.AnythingOfType("*models.Book"), []models.Author(nil), []models.Publisher(nil), []models.Genre{}).Return(nil)
+
+	// Execute
+	book, err := service.PatchBook(context.Background(), uint(1), []byte(`{"title": "Clean Code", "description": null, "genre_ids": null}`), `"3"`)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Clean Code", book.Title)
+	assert.Equal(t, "Robert C. Martin", book.Author)
+	assert.Equal(t, "9780132350884", book.ISBN)
+	assert.Equal(t, "", book.Description)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test PatchBook - If-Match tidak sama dengan ETag buku saat ini
+func TestPatchBook_VersionMismatch(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, Title: "Title", Author: "Author", ISBN: "9780132350884", Version: 4}, nil)
+
+	_, err := service.PatchBook(context.Background(), uint(1), []byte(`{"title": "New"}`), `"3"`)
+
+	assert.ErrorIs(t, err, ErrBookVersionMismatch)
+	mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// Test PatchBook - stock berubah (peminjaman) antara GET dan PATCH, If-Match tetap cocok
+func TestPatchBook_StockChanged(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockGenreRepo := new(MockGenreRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, mockGenreRepo, nil, new(MockTransactionManager), nil)
+
+	read := &models.Book{ID: 1, Title: "Title", Author: "Author", ISBN: "9780132350884", Version: 3, Stock: 10}
+	ifMatch := read.ETag()
+
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, Title: "Title", Author: "Author", ISBN: "9780132350884", Version: 3, Stock: 9}, nil)
+	mockGenreRepo.On("FindByIDsWithTx", mock.Anything, []uint{}).Return([]models.Genre{}, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+	mockRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.AnythingOfType("*models.Book"), []models.Author(nil), []models.Publisher(nil), []models.Genre{}).Return(nil)
+
+	book, err := service.PatchBook(context.Background(), uint(1), []byte(`{"title": "New", "genre_ids": null}`), ifMatch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", book.Title)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test PatchBook - hasil patch divalidasi seperti PUT
+func TestPatchBook_Invalid(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, Title: "Title", Author: "Author", ISBN: "9780132350884", Version: 1}, nil)
+
+	for _, patch := range []string{`{"title": null}`, `{"isbn": "123"}`, `{"title": 5}`, `["title"]`, `not json`} {
+		_, err := service.PatchBook(context.Background(), uint(1), []byte(patch), "")
+		assert.ErrorIs(t, err, ErrInvalidBookPatch, patch)
+	}
+	mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// Test UpdateBook - buku diubah request lain setelah dibaca
+func TestUpdateBook_Stale(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, Author: "Author", ISBN: "9780132350884", Version: 1}, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(repository.ErrStaleBook)
+
+	_, err := service.UpdateBook(context.Background(), uint(1), BookInput{Title: "Title", Author: "Author", ISBN: "9780132350884"}, "")
+	assert.ErrorIs(t, err, ErrBookModified)
+
+	_, err = service.UpdateBook(context.Background(), uint(1), BookInput{Title: "Title", Author: "Author", ISBN: "9780132350884"}, `"1"`)
+	assert.ErrorIs(t, err, ErrBookVersionMismatch)
+}
+
+// Test UpdateBook - field yang berubah dicatat di audit log dengan nilai lama dan baru
+func TestUpdateBook_RecordsAudit(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockAuditRepo := new(MockAuditLogRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, nil, nil, nil, mockAuditRepo, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, Title: "Clean Cod", Author: "Robert C. Martin", ISBN: "9780132350884", Version: 2, Stock: 3}, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Book).Version++
+	})
+	mockRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, []models.Author(nil), []models.Publisher(nil), []models.Genre(nil)).Return(nil)
+	mockAuditRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.EntityType == models.AuditEntityBook &&
+			entry.Action == models.AuditActionUpdate &&
+			len(entry.Changes) == 2 &&
+			entry.Changes["title"] == models.AuditChange{Old: "Clean Cod", New: "Clean Code"} &&
+			entry.Changes["version"] == models.AuditChange{Old: int64(2), New: int64(3)}
+	})).Return(nil)
+
+	_, err := service.UpdateBook(context.Background(), uint(1), BookInput{Title: "Clean Code", Author: "Robert C. Martin", ISBN: "9780132350884"}, "")
+
+	assert.NoError(t, err)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+// Test CreateBook - AuthorIDs menggantikan string author, urutan mengikuti request
+func TestCreateBook_WithAuthorIDs(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	mockGenreRepo := new(MockGenreRepository)
+	service := NewBookService(mockRepo, mockCopyRepo, nil, nil, mockAuthorRepo, nil, mockGenreRepo, nil, new(MockTransactionManager), nil)
+
+	fowler := models.Author{ID: 4, Name: "Martin Fowler"}
+	beck := models.Author{ID: 9, Name: "Kent Beck"}
+	genre := models.Genre{ID: 2, Name: "Software"}
+
+	// Setup mock
+	mockRepo.On("FindByISBN", "9780201485677").Return(nil, errors.New("Not Found"))
+	mockAuthorRepo.On("FindByIDsWithTx", mock.Anything, []uint{4, 9}).Return([]models.Author{beck, fowler}, nil)
+	mockGenreRepo.On("FindByIDsWithTx", mock.Anything, []uint{2}).Return([]models.Genre{genre}, nil)
+	mockRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(b *models.Book) bool {
+		return b.Author == "Martin Fowler, Kent Beck"
+	})).Return(nil)
+	mockRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, []models.Author{fowler, beck}, []models.Publisher(nil), []models.Genre{genre}).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, mock.Anything).Return(nil)
+
+	// Execute
+	book, err := service.CreateBook(context.Background(), BookInput{Title: "Refactoring", ISBN: "0201485672", AuthorIDs: []uint{4, 9, 4}, GenreIDs: []uint{2}})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Martin Fowler, Kent Beck", book.Author)
+	mockRepo.AssertExpectations(t)
+	mockAuthorRepo.AssertExpectations(t)
+	mockAuthorRepo.AssertNotCalled(t, "FindOrCreateByNamesWithTx", mock.Anything, mock.Anything)
+}
+
+// Test CreateBook - genre yang tidak ada membatalkan transaction
+func TestCreateBook_UnknownGenre(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	mockGenreRepo := new(MockGenreRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, mockAuthorRepo, nil, mockGenreRepo, nil, new(MockTransactionManager), nil)
+
+	// Setup mock
+	mockRepo.On("FindByISBN", "9780201485677").Return(nil, errors.New("Not Found"))
+	mockAuthorRepo.On("FindOrCreateByNamesWithTx", mock.Anything, []string{"Martin Fowler"}).Return([]models.Author{{ID: 4}}, nil)
+	mockGenreRepo.On("FindByIDsWithTx", mock.Anything, []uint{2, 77}).Return([]models.Genre{{ID: 2}}, nil)
+
+	// Execute
+	book, err := service.CreateBook(context.Background(), BookInput{Title: "Refactoring", Author: "Martin Fowler", ISBN: "0201485672", GenreIDs: []uint{2, 77}})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrGenreNotFound)
+	assert.Nil(t, book)
+	mockRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// Test UpdateBook - author tidak berubah dan tanpa *_ids, relasi tidak disentuh
+func TestUpdateBook_KeepsRelations(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockAuthorRepo := new(MockAuthorRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), nil, nil, mockAuthorRepo, nil, nil, nil, new(MockTransactionManager), nil)
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, Author: "Martin Fowler", ISBN: "9780201485677"}, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.MatchedBy(func(b *models.Book) bool {
+		return b.Title == "Refactoring, 2nd Edition"
+	})).Return(nil)
+	mockRepo.On("ReplaceRelationsWithTx", mock.Anything, mock.Anything, []models.Author(nil), []models.Publisher(nil), []models.Genre(nil)).Return(nil)
+
+	// Execute
+	book, err := service.UpdateBook(context.Background(), uint(1), BookInput{Title: "Refactoring, 2nd Edition", Author: "Martin Fowler", ISBN: "9780201485677"}, "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Martin Fowler", book.Author)
+	mockRepo.AssertExpectations(t)
+	mockAuthorRepo.AssertNotCalled(t, "FindOrCreateByNamesWithTx", mock.Anything, mock.Anything)
+}
+
+// TestSplitAuthorNames - pemisah yang sama dengan backfill di migration
+func TestSplitAuthorNames(t *testing.T) {
+	assert.Equal(t, []string{"Martin Fowler", "Kent Beck"}, SplitAuthorNames("Martin Fowler & Kent Beck"))
+	assert.Equal(t, []string{"Gamma", "Helm", "Johnson", "Vlissides"}, SplitAuthorNames("Gamma, Helm, Johnson AND Vlissides"))
+	assert.Equal(t, []string{"Alexander Anderson"}, SplitAuthorNames(" Alexander Anderson "))
+	assert.Empty(t, SplitAuthorNames(" ; "))
+}
+
+var testMetadata = metadata.NewStaticProvider(map[string]metadata.BookMetadata{
+	"9780132350884": {
+		ISBN:        "9780132350884",
+		Title:       "Clean Code",
+		Authors:     []string{"Robert C. Martin"},
+		Description: "A handbook of agile software craftsmanship",
+		Source:      "fixture",
+	},
+	"9780201485677": {
+		ISBN:    "9780201485677",
+		Title:   "Refactoring",
+		Authors: []string{"Martin Fowler", "Kent Beck"},
+		Source:  "fixture",
+	},
+})
+
+// Test LookupMetadata - ISBN-10 dicari sebagai ISBN-13, author digabung
+func TestLookupMetadata_Success(t *testing.T) {
+	service := NewBookService(new(MockBookRepository), new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), testMetadata)
+
+	// Execute
+	lookup, err := service.LookupMetadata(context.Background(), "0-201-48567-2")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "9780201485677", lookup.ISBN)
+	assert.Equal(t, "Refactoring", lookup.Title)
+	assert.Equal(t, "Martin Fowler, Kent Beck", lookup.Author)
+	assert.Equal(t, "fixture", lookup.Metadata.Source)
+}
+
+// Test LookupMetadata - provider dimatikan atau ISBN tidak dikenal
+func TestLookupMetadata_Unavailable(t *testing.T) {
+	disabled := NewBookService(new(MockBookRepository), new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+	_, err := disabled.LookupMetadata(context.Background(), "9780132350884")
+	assert.ErrorIs(t, err, ErrMetadataDisabled)
+
+	service := NewBookService(new(MockBookRepository), new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), testMetadata)
+	_, err = service.LookupMetadata(context.Background(), "9791090636071")
+	assert.ErrorIs(t, err, metadata.ErrNotFound)
+}
+
+// Test FillMissing - hanya field kosong yang diisi
+func TestFillMissing_KeepsUserInput(t *testing.T) {
+	service := NewBookService(new(MockBookRepository), new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), testMetadata)
+	input := &BookInput{ISBN: "9780132350884", Title: "Clean Code (2nd printing)"}
+
+	// Execute
+	err := service.FillMissing(context.Background(), input)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Clean Code (2nd printing)", input.Title)
+	assert.Equal(t, "Robert C. Martin", input.Author)
+	assert.Equal(t, "A handbook of agile software craftsmanship", input.Description)
+}
+
+// Test FillMissing - provider timeout, input tidak berubah
+func TestFillMissing_ProviderTimeout(t *testing.T) {
+	slow := metadata.ProviderFunc(func(ctx context.Context, isbn string) (*metadata.BookMetadata, error) {
+		return nil, fmt.Errorf("%w: context deadline exceeded", metadata.ErrUnavailable)
+	})
+	service := NewBookService(new(MockBookRepository), new(MockBookCopyRepository), nil, nil, nil, nil, nil, nil, new(MockTransactionManager), slow)
+	input := &BookInput{ISBN: "9780132350884", Author: "Robert C. Martin"}
+
+	// Execute
+	err := service.FillMissing(context.Background(), input)
+
+	// Assert
+	assert.ErrorIs(t, err, metadata.ErrUnavailable)
+	assert.Equal(t, BookInput{ISBN: "9780132350884", Author: "Robert C. Martin"}, *input)
+}
+
+// Test DeleteBook - Success, buku dipindah ke trash
 func TestDeleteBook_Success(t *testing.T) {
 	mockRepo := new(MockBookRepository)
-	service := NewBookService(mockRepo)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), mockBorrowRepo, mockReservationRepo, nil, nil, nil, nil, new(MockTransactionManager), nil)
 
 	mockBook := &models.Book{
 		ID: 1,
@@ -203,13 +645,69 @@ func TestDeleteBook_Success(t *testing.T) {
 	}
 
 	// Setup mock
-	mockRepo.On("FindByID", uint(1)).Return(mockBook, nil)
-	mockRepo.On("Delete", uint(1)).Return(nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(mockBook, nil)
+	mockBorrowRepo.On("CountActiveByBookIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
+	mockReservationRepo.On("FindActiveByBookWithTx", mock.Anything, uint(1)).Return([]models.Reservation{}, nil)
+	mockRepo.On("DeleteWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	err := service.DeleteBook(context.Background(), uint(1))
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test DeleteBook - masih ada copy yang dipinjam
+func TestDeleteBook_ActiveBorrows(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := NewBookService(mockRepo, new(MockBookCopyRepository), mockBorrowRepo, nil, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockBorrowRepo.On("CountActiveByBookIDWithTx", mock.Anything, uint(1)).Return(int64(2), nil)
+
+	err := service.DeleteBook(context.Background(), uint(1))
+
+	assert.ErrorIs(t, err, ErrBookHasActiveBorrows)
+	mockRepo.AssertNotCalled(t, "DeleteWithTx", mock.Anything, mock.Anything)
+}
+
+// Test DeleteBook - hold aktif dibatalkan dan copy yang disisihkan kembali available
+func TestDeleteBook_CancelsHolds(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	service := NewBookService(mockRepo, mockCopyRepo, mockBorrowRepo, mockReservationRepo, nil, nil, nil, nil, new(MockTransactionManager), nil)
+
+	copyID := uint(8)
+	heldCopy := &models.BookCopy{ID: copyID, BookID: 1, Status: models.CopyStatusOnHold}
+	holds := []models.Reservation{
+		{ID: 3, UserID: 2, BookID: 1, CopyID: &copyID, Status: models.ReservationStatusReady},
+		{ID: 4, UserID: 5, BookID: 1, Status: models.ReservationStatusWaiting},
+	}
+
+	// Setup mock
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil).Once()
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 1}, nil).Once()
+	mockBorrowRepo.On("CountActiveByBookIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
+	mockReservationRepo.On("FindActiveByBookWithTx", mock.Anything, uint(1)).Return(holds, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, mock.MatchedBy(func(hold *models.Reservation) bool {
+		return hold.Status == models.ReservationStatusCancelled
+	})).Return(nil).Twice()
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, copyID).Return(heldCopy, nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, heldCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+	mockRepo.On("DeleteWithTx", mock.Anything, uint(1)).Return(nil)
 
 	// Execute
-	err := service.DeleteBook(uint(1))
+	err := service.DeleteBook(context.Background(), uint(1))
 
 	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, models.CopyStatusAvailable, heldCopy.Status)
+	mockReservationRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
\ No newline at end of file
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"book-api/internal/storage"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBookNotInTrash		= apperrors.NotFound("book_not_in_trash", "book is not in trash")
+	ErrBookHasLoanHistory	= apperrors.Conflict("book_has_loan_history", "book has loan history and cannot be purged")
+)
+
+// TrashedBook - buku di trash beserta waktu dihapusnya
+type TrashedBook struct {
+	models.Book
+	DeletedAt	time.Time	`json:"deleted_at"`
+}
+
+// BookTrashService - buku yang dihapus lewat DeleteBook masuk trash dan bisa
+// di-restore atau dihapus permanen
+type BookTrashService interface {
+	GetTrashedBooks(page, pageSize int) ([]TrashedBook, int64, error)
+	RestoreBook(ctx context.Context, id uint) (*models.Book, error)
+	PurgeBook(ctx context.Context, id uint) error
+}
+
+type bookTrashService struct {
+	bookRepo	repository.BookRepository
+	borrowRepo	repository.BorrowRepository
+	txManager	database.TransactionManager
+	store		storage.BlobStore
+	audit		auditTrail
+}
+
+// NewBookTrashService - store dipakai untuk menghapus file cover saat purge
+func NewBookTrashService(
+	bookRepo repository.BookRepository,
+	borrowRepo repository.BorrowRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager database.TransactionManager,
+	store storage.BlobStore,
+) BookTrashService {
+	return &bookTrashService{
+		bookRepo:	bookRepo,
+		borrowRepo:	borrowRepo,
+		txManager:	txManager,
+		store:		store,
+		audit:		auditTrail{repo: auditRepo},
+	}
+}
+
+func (s *bookTrashService) GetTrashedBooks(page, pageSize int) ([]TrashedBook, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	books, err := s.bookRepo.FindDeleted(pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.bookRepo.CountDeleted()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	trashed := make([]TrashedBook, len(books))
+	for i, book := range books {
+		trashed[i] = TrashedBook{Book: book, DeletedAt: book.DeletedAt.Time}
+	}
+	return trashed, total, nil
+}
+
+// RestoreBook - ditolak jika ISBN-nya sudah dipakai buku lain yang dibuat setelah buku ini dihapus
+func (s *bookTrashService) RestoreBook(ctx context.Context, id uint) (*models.Book, error) {
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		book, err := s.bookRepo.FindDeletedByIDWithLock(tx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBookNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+
+		// Cek di transaction yang sama, bukan lewat koneksi lain yang bisa tertahan lock-nya
+		existing, err := s.bookRepo.FindByISBNsWithTx(tx, []string{book.ISBN})
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return ErrISBNExists
+		}
+
+		if err := s.bookRepo.RestoreWithTx(tx, id); err != nil {
+			return err
+		}
+		return s.audit.recordBook(ctx, tx, id, models.AuditActionRestore, nil, bookSnapshot(book))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bookRepo.FindByID(id)
+}
+
+// PurgeBook - hapus permanen buku di trash beserta copy, hold, relasi dan file cover-nya.
+// Buku yang pernah dipinjam tetap di trash karena riwayat peminjaman dan denda merujuk ke buku itu.
+func (s *bookTrashService) PurgeBook(ctx context.Context, id uint) error {
+	var book *models.Book
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		book, err = s.bookRepo.FindDeletedByIDWithLock(tx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBookNotInTrash
+		}
+		if err != nil {
+			return err
+		}
+
+		borrows, err := s.borrowRepo.CountByBookIDWithTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if borrows > 0 {
+			return ErrBookHasLoanHistory
+		}
+
+		if err := s.bookRepo.PurgeWithTx(tx, id); err != nil {
+			return err
+		}
+		return s.audit.recordBook(ctx, tx, id, models.AuditActionPurge, bookSnapshot(book), nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if book.CoverKey != "" {
+		deleteCoverBlobs(ctx, s.store, book.CoverKey, book.CoverType)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"book-api/internal/storage"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestRestoreBook - Success
+func TestRestoreBook_Success(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookTrashService(mockRepo, new(MockBorrowRepository), nil, new(MockTransactionManager), nil)
+
+	trashed := &models.Book{ID: 1, ISBN: "9780132350884"}
+	mockRepo.On("FindDeletedByIDWithLock", mock.Anything, uint(1)).Return(trashed, nil)
+	mockRepo.On("FindByISBNsWithTx", mock.Anything, []string{"9780132350884"}).Return([]models.Book{}, nil)
+	mockRepo.On("RestoreWithTx", mock.Anything, uint(1)).Return(nil)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Book{ID: 1, ISBN: "9780132350884"}, nil)
+
+	book, err := service.RestoreBook(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), book.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestRestoreBook - ISBN sudah dipakai buku baru
+func TestRestoreBook_ISBNTaken(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookTrashService(mockRepo, new(MockBorrowRepository), nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindDeletedByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, ISBN: "9780132350884"}, nil)
+	mockRepo.On("FindByISBNsWithTx", mock.Anything, []string{"9780132350884"}).Return([]models.Book{{ID: 2, ISBN: "9780132350884"}}, nil)
+
+	_, err := service.RestoreBook(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrISBNExists)
+	mockRepo.AssertNotCalled(t, "RestoreWithTx", mock.Anything, mock.Anything)
+}
+
+// TestRestoreBook - buku tidak ada di trash
+func TestRestoreBook_NotInTrash(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	service := NewBookTrashService(mockRepo, new(MockBorrowRepository), nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindDeletedByIDWithLock", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.RestoreBook(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrBookNotInTrash)
+}
+
+// TestPurgeBook - Success, file cover ikut dihapus
+func TestPurgeBook_Success(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "covers/1/abc.png", bytes.NewReader([]byte("png"))))
+
+	mockRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := NewBookTrashService(mockRepo, mockBorrowRepo, nil, new(MockTransactionManager), store)
+
+	mockRepo.On("FindDeletedByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, CoverKey: "covers/1/abc", CoverType: "image/png"}, nil)
+	mockBorrowRepo.On("CountByBookIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
+	mockRepo.On("PurgeWithTx", mock.Anything, uint(1)).Return(nil)
+
+	err = service.PurgeBook(ctx, 1)
+
+	assert.NoError(t, err)
+	_, _, err = store.Get(ctx, "covers/1/abc.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+// TestPurgeBook - buku yang pernah dipinjam tidak bisa dihapus permanen
+func TestPurgeBook_LoanHistory(t *testing.T) {
+	mockRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := NewBookTrashService(mockRepo, mockBorrowRepo, nil, new(MockTransactionManager), nil)
+
+	mockRepo.On("FindDeletedByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockBorrowRepo.On("CountByBookIDWithTx", mock.Anything, uint(1)).Return(int64(3), nil)
+
+	err := service.PurgeBook(context.Background(), 1)
+
+	assert.ErrorIs(t, err, ErrBookHasLoanHistory)
+	mockRepo.AssertNotCalled(t, "PurgeWithTx", mock.Anything, mock.Anything)
+}
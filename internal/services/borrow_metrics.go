@@ -0,0 +1,30 @@
+package services
+
+import "book-api/internal/apperrors"
+
+// BorrowMetrics - counter peminjaman untuk monitoring, diimplementasikan oleh
+// metrics.Metrics. Hanya dipanggil setelah transaction selesai, jadi transaction
+// yang di-rollback tidak ikut terhitung sebagai sukses.
+type BorrowMetrics interface {
+	BookBorrowed()
+	BookReturned()
+	BorrowsOverdue(count int64)
+	BorrowFailed(reason string)
+}
+
+// nopBorrowMetrics - dipakai jika NewBorrowService menerima metrics nil
+type nopBorrowMetrics struct{}
+
+func (nopBorrowMetrics) BookBorrowed()        {}
+func (nopBorrowMetrics) BookReturned()        {}
+func (nopBorrowMetrics) BorrowsOverdue(int64) {}
+func (nopBorrowMetrics) BorrowFailed(string)  {}
+
+// borrowFailureReason - code error domain (misalnya "book_out_of_stock") supaya jumlah
+// label tetap terbatas; error lain dihitung sebagai "internal"
+func borrowFailureReason(err error) string {
+	if domainErr := apperrors.As(err); domainErr != nil {
+		return domainErr.Code
+	}
+	return string(apperrors.KindInternal)
+}
@@ -1,67 +1,135 @@
 package services
 
 import (
+	"book-api/internal/apperrors"
 	"book-api/internal/database"
 	"book-api/internal/models"
 	"book-api/internal/repository"
+	"context"
 	"errors"
+	"math"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrBookNotFound			= apperrors.NotFound("book_not_found", "book not found")
+	ErrBookOutOfStock		= apperrors.BusinessRule("book_out_of_stock", "book out of stock")
+	ErrBookOnHold			= apperrors.BusinessRule("book_on_hold", "book is on hold for another member")
+	ErrBorrowNotFound		= apperrors.NotFound("borrow_not_found", "borrow record not found")
+	ErrBookAlreadyReturned	= apperrors.BusinessRule("book_already_returned", "book already returned")
+	ErrCopyNotOnLoan		= apperrors.BusinessRule("copy_not_on_loan", "copy is not on loan")
+	ErrBorrowOverdue		= apperrors.BusinessRule("borrow_overdue", "borrow is overdue and cannot be renewed")
+	ErrRenewalLimitReached	= apperrors.BusinessRule("renewal_limit_reached", "renewal limit reached")
+)
+
+// BorrowConfig - aturan peminjaman yang bisa dikonfigurasi
+type BorrowConfig struct {
+	LoanPeriod		time.Duration
+	RenewalPeriod	time.Duration
+	MaxRenewals		int
+	PickupWindow	time.Duration
+	Fines			FineConfig
+}
+
 type BorrowService interface {
-	BorrowBook(userID, bookID uint) (*models.Borrow, error)
-	ReturnBook(borrowID uint) (*models.Borrow, error)
+	BorrowBook(ctx context.Context, userID, bookID uint) (*models.Borrow, error)
+	ReturnBook(ctx context.Context, actor Actor, barcode string) (*models.Borrow, error)
+	RenewBorrow(ctx context.Context, actor Actor, borrowID uint) (*models.Borrow, error)
 	GetUserBorrows(userID uint, page, pageSize int) ([]models.Borrow, int64, error)
-	GetBorrowByID(borrowID uint) (*models.Borrow, error)
+	GetBorrowByID(actor Actor, borrowID uint) (*models.Borrow, error)
+	GetOverdueBorrows(page, pageSize int) ([]models.Borrow, int64, error)
+	MarkOverdueBorrows(ctx context.Context) (int64, error)
 }
 
 type borrowService struct {
 	borrowRepo 	repository.BorrowRepository
 	bookRepo 	repository.BookRepository
+	copyRepo	repository.BookCopyRepository
+	reservationRepo repository.ReservationRepository
+	fineRepo	repository.FineRepository
+	userRepo	repository.UserRepository
 	txManager 	database.TransactionManager
+	eligibility	EligibilityPolicy
+	config		BorrowConfig
+	holds		holdQueue
+	audit		auditTrail
+	metrics		BorrowMetrics
 }
 
 func NewBorrowService(
 	borrowRepo repository.BorrowRepository,
 	bookRepo repository.BookRepository,
+	copyRepo repository.BookCopyRepository,
+	reservationRepo repository.ReservationRepository,
+	fineRepo repository.FineRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
 	txManager database.TransactionManager,
+	eligibility EligibilityPolicy,
+	config BorrowConfig,
+	metrics BorrowMetrics,
 ) BorrowService {
+	if metrics == nil {
+		metrics = nopBorrowMetrics{}
+	}
 	return &borrowService{
 		borrowRepo: borrowRepo,
 		bookRepo: 	bookRepo,
+		copyRepo:	copyRepo,
+		reservationRepo: reservationRepo,
+		fineRepo:	fineRepo,
+		userRepo:	userRepo,
 		txManager:	txManager,
+		eligibility: eligibility,
+		config:		config,
+		holds:		holdQueue{reservationRepo: reservationRepo, copyRepo: copyRepo, pickupWindow: config.PickupWindow},
+		audit:		auditTrail{repo: auditRepo},
+		metrics:	metrics,
 	}
 }
 
-func (s *borrowService) BorrowBook(userID, bookID uint) (*models.Borrow, error) {
+func (s *borrowService) BorrowBook(ctx context.Context, userID, bookID uint) (*models.Borrow, error) {
 	var result *models.Borrow
 
 	// Semua operasi dalam transaction
-	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
-		// 1. Cek dan LOCK buku
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// 1. LOCK user supaya peminjaman paralel oleh user yang sama tidak melewati limit,
+		// lalu cek eligibility
+		if err := s.checkEligibility(tx, userID, bookID); err != nil {
+			return err
+		}
+
+		// 2. Cek dan LOCK buku. Semua perubahan status copy sebuah buku lewat lock ini.
 		book, err := s.bookRepo.FindByIDWithLock(tx, bookID)
 		if err != nil {
-			return errors.New("book not found")
+			return ErrBookNotFound
 		}
 
-		if book.Stock <= 0 {
-			return errors.New("book out of stock")
+		// 3. Ambil copy: dari hold yang sudah siap (copy sudah disisihkan),
+		// atau copy available pertama
+		bookCopy, err := s.pickCopy(tx, userID, bookID)
+		if err != nil {
+			return err
 		}
 
-		// 2. Kurangi stock buku
-		book.Stock--
-		if err := s.bookRepo.UpdateWithTx(tx, book); err != nil {
+		bookCopy.Status = models.CopyStatusBorrowed
+		if err := s.copyRepo.UpdateWithTx(tx, bookCopy); err != nil {
+			return err
+		}
+		if err := s.copyRepo.SyncBookStockWithTx(tx, bookID); err != nil {
 			return err
 		}
 
-		// 3. Buat record borrow
+		// 4. Buat record borrow
+		now := time.Now()
 		borrow := &models.Borrow{
 			UserID: userID,
 			BookID: bookID,
-			BorrowDate: time.Now(),
-			DueDate: time.Now().Add(14 * 24 * time.Hour), // dua minggu
+			CopyID: &bookCopy.ID,
+			BorrowDate: now,
+			DueDate: now.Add(s.config.LoanPeriod),
 			Status: models.BorrowStatusBorrowed,
 		}
 
@@ -69,45 +137,210 @@ func (s *borrowService) BorrowBook(userID, bookID uint) (*models.Borrow, error)
 			return err
 		}
 
+		// 5. Catat peminjaman beserta perubahan stock buku
+		stock, err := stockChangeWithTx(tx, s.bookRepo, bookID, book.Stock)
+		if err != nil {
+			return err
+		}
+		if err := s.audit.recordBorrow(ctx, tx, borrow, models.AuditActionBorrow, nil, stock); err != nil {
+			return err
+		}
+
+		borrow.Copy = bookCopy
 		result = borrow
 		return nil
 	})
 
 	if err != nil {
+		s.metrics.BorrowFailed(borrowFailureReason(err))
 		return nil, err
 	}
 
+	s.metrics.BookBorrowed()
 	return result, err
 }
 
-func (s *borrowService) ReturnBook(borrowID uint) (*models.Borrow, error) {
+// pickCopy - LOCK copy yang akan dipinjamkan dan tandai hold user (jika ada) sebagai terpenuhi
+func (s *borrowService) pickCopy(tx *gorm.DB, userID, bookID uint) (*models.BookCopy, error) {
+	var bookCopy *models.BookCopy
+
+	hold, err := s.reservationRepo.FindActiveByUserAndBookWithTx(tx, userID, bookID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if hold != nil && hold.Status == models.ReservationStatusReady && hold.CopyID != nil {
+		held, err := s.copyRepo.FindByIDWithLock(tx, *hold.CopyID)
+		if err != nil {
+			return nil, err
+		}
+		bookCopy = held
+	} else {
+		available, err := s.copyRepo.FindAvailableWithLock(tx, bookID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookOutOfStock
+		}
+		if err != nil {
+			return nil, err
+		}
+		bookCopy = available
+	}
+
+	// Hold yang masih menunggu ikut terpenuhi
+	if hold != nil {
+		hold.Status = models.ReservationStatusFulfilled
+		if err := s.reservationRepo.UpdateWithTx(tx, hold); err != nil {
+			return nil, err
+		}
+	}
+
+	return bookCopy, nil
+}
+
+// checkEligibility - kumpulkan kondisi peminjam dan evaluasi dengan EligibilityPolicy
+func (s *borrowService) checkEligibility(tx *gorm.DB, userID, bookID uint) error {
+	user, err := s.userRepo.FindByIDWithLock(tx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	loans, err := s.borrowRepo.FindActiveByUserIDWithTx(tx, userID)
+	if err != nil {
+		return err
+	}
+
+	balance, err := s.fineRepo.BalanceByUserIDWithTx(tx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.eligibility.Check(BorrowerStanding{
+		UserID:			userID,
+		Role:			user.Role,
+		BookID:			bookID,
+		ActiveLoans:	loans,
+		FineBalance:	balance,
+		Now:			time.Now(),
+	})
+}
+
+// ReturnBook - check-in copy berdasarkan barcode yang di-scan
+func (s *borrowService) ReturnBook(ctx context.Context, actor Actor, barcode string) (*models.Borrow, error) {
 	var result *models.Borrow
 
-	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
-		// 1. Cari dan LOCK borrow record
-		borrow, err := s.borrowRepo.FindByIDWithLock(tx, borrowID)
+	bookCopy, err := s.copyRepo.FindByBarcode(barcode)
+	if err != nil {
+		return nil, ErrCopyNotFound
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// 1. Cari dan LOCK peminjaman yang memegang copy ini. Peminjaman milik user lain
+		// diperlakukan seperti tidak ada supaya keberadaannya tidak bocor.
+		borrow, err := s.borrowRepo.FindActiveByCopyIDWithLock(tx, bookCopy.ID)
 		if err != nil {
-			return errors.New("borrow record not found")
+			return ErrCopyNotOnLoan
 		}
-		// 2. Cek apakah sudah dikembalikan
-		if borrow.Status == models.BorrowStatusReturned {
-			return errors.New("book already returned")
+		if !actor.canAccessBorrow(borrow) {
+			return ErrBorrowNotFound
 		}
-		// 3. Update status dan return date
+		before := borrowSnapshot(borrow)
+		// 2. Update status, return date dan catat keterlambatan
 		now := time.Now()
 		borrow.ReturnDate = &now
+		borrow.LateDays = lateDays(borrow.DueDate, now)
 		borrow.Status = models.BorrowStatusReturned
 		if err := s.borrowRepo.UpdateWithTx(tx, borrow); err != nil {
 			return err
 		}
-		// 4. Lock dan Update stock buku
-		book, err := s.bookRepo.FindByIDWithLock(tx, borrow.BookID); 
+		// 3. Catat denda keterlambatan di ledger
+		if fee := s.config.Fines.LateFee(borrow.LateDays); fee > 0 {
+			fine := &models.Fine{
+				UserID: borrow.UserID,
+				BorrowID: &borrow.ID,
+				Type: models.FineTypeLate,
+				Amount: fee,
+			}
+			if err := s.fineRepo.CreateWithTx(tx, fine); err != nil {
+				return err
+			}
+		}
+		// 4. Lock buku dan copy lalu serahkan copy ke antrian hold berikutnya,
+		// atau jadikan available jika tidak ada yang menunggu
+		book, err := s.bookRepo.FindByIDWithLock(tx, borrow.BookID)
+		if err != nil {
+			return bookNotFound(err)
+		}
+		locked, err := s.copyRepo.FindByIDWithLock(tx, bookCopy.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.holds.releaseCopy(tx, locked); err != nil {
+			return err
+		}
+		// 5. Catat pengembalian beserta perubahan stock buku
+		stock, err := stockChangeWithTx(tx, s.bookRepo, borrow.BookID, book.Stock)
+		if err != nil {
+			return err
+		}
+		if err := s.audit.recordBorrow(ctx, tx, borrow, models.AuditActionReturn, before, stock); err != nil {
+			return err
+		}
+
+		borrow.Copy = locked
+		result = borrow
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.BookReturned()
+	return result, nil
+}
+
+// RenewBorrow - perpanjang DueDate peminjaman milik user (atau milik siapa pun untuk staff)
+func (s *borrowService) RenewBorrow(ctx context.Context, actor Actor, borrowID uint) (*models.Borrow, error) {
+	var result *models.Borrow
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// 1. Cari dan LOCK borrow record
+		borrow, err := s.borrowRepo.FindByIDWithLock(tx, borrowID)
+		if err != nil || !actor.canAccessBorrow(borrow) {
+			return ErrBorrowNotFound
+		}
+
+		// 2. Validasi aturan perpanjangan
+		if borrow.Status == models.BorrowStatusReturned {
+			return ErrBookAlreadyReturned
+		}
+		now := time.Now()
+		if borrow.Status == models.BorrowStatusOverdue || now.After(borrow.DueDate) {
+			return ErrBorrowOverdue
+		}
+		if borrow.RenewalCount >= s.config.MaxRenewals {
+			return ErrRenewalLimitReached
+		}
+
+		// 3. LOCK buku lalu tolak jika member lain sedang antri buku ini
+		if _, err := s.bookRepo.FindByIDWithLock(tx, borrow.BookID); err != nil {
+			return bookNotFound(err)
+		}
+		holds, err := s.reservationRepo.CountActiveByBookWithTx(tx, borrow.BookID, borrow.UserID)
 		if err != nil {
 			return err
 		}
+		if holds > 0 {
+			return ErrBookOnHold
+		}
 
-		book.Stock++
-		if err := s.bookRepo.UpdateWithTx(tx, book); err != nil {
+		// 4. Perpanjang DueDate
+		before := borrowSnapshot(borrow)
+		borrow.DueDate = borrow.DueDate.Add(s.config.RenewalPeriod)
+		borrow.RenewalCount++
+		if err := s.borrowRepo.UpdateWithTx(tx, borrow); err != nil {
+			return err
+		}
+		if err := s.audit.recordBorrow(ctx, tx, borrow, models.AuditActionRenew, before, nil); err != nil {
 			return err
 		}
 
@@ -145,10 +378,70 @@ func (s *borrowService) GetUserBorrows(userID uint, page, pageSize int) ([]model
 	return borrows, total, nil
 }
 
-func (s *borrowService) GetBorrowByID(borrowID uint) (*models.Borrow, error) {
+func (s *borrowService) GetBorrowByID(actor Actor, borrowID uint) (*models.Borrow, error) {
 	borrow, err := s.borrowRepo.FindByID(borrowID)
-	if err != nil {
-		return nil, errors.New("borrow record not found")
+	if err != nil || !actor.canAccessBorrow(borrow) {
+		return nil, ErrBorrowNotFound
 	}
 	return borrow, nil
+}
+
+func (s *borrowService) GetOverdueBorrows(page, pageSize int) ([]models.Borrow, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	now := time.Now()
+
+	borrows, err := s.borrowRepo.FindOverdue(now, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.borrowRepo.CountOverdue(now)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return borrows, total, nil
+}
+
+// MarkOverdueBorrows - tandai peminjaman yang lewat DueDate sebagai overdue.
+// Dipanggil berkala oleh background job; setiap peminjaman dicatat di audit log tanpa actor.
+func (s *borrowService) MarkOverdueBorrows(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		borrows, err := s.borrowRepo.MarkOverdueWithTx(tx, time.Now())
+		if err != nil {
+			return err
+		}
+
+		for i := range borrows {
+			before := borrowSnapshot(&borrows[i])
+			before["status"] = string(models.BorrowStatusBorrowed)
+			if err := s.audit.recordBorrow(ctx, tx, &borrows[i], models.AuditActionOverdue, before, nil); err != nil {
+				return err
+			}
+		}
+		count = int64(len(borrows))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.metrics.BorrowsOverdue(count)
+	return count, nil
+}
+
+// lateDays - jumlah hari keterlambatan (dibulatkan ke atas)
+func lateDays(dueDate, returnedAt time.Time) int {
+	if !returnedAt.After(dueDate) {
+		return 0
+	}
+	return int(math.Ceil(returnedAt.Sub(dueDate).Hours() / 24))
 }
\ No newline at end of file
@@ -2,8 +2,10 @@ package services
 
 import (
 	"book-api/internal/models"
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -37,10 +39,26 @@ func (m *MockBorrowRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.B
 	}
 	return args.Get(0).(*models.Borrow), nil
 }
+func (m *MockBorrowRepository) FindAll(filter models.BorrowFilter, limit, offset int) ([]models.Borrow, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.Borrow), args.Error(1)
+}
 func (m *MockBorrowRepository) FindByUserID(userID uint, limit, offset int) ([]models.Borrow, error) {
 	args := m.Called(userID, limit, offset)
 	return args.Get(0).([]models.Borrow), nil
 }
+func (m *MockBorrowRepository) FindActiveByUserIDWithTx(tx *gorm.DB, userID uint) ([]models.Borrow, error) {
+	args := m.Called(tx, userID)
+	return args.Get(0).([]models.Borrow), args.Error(1)
+}
+func (m *MockBorrowRepository) FindActiveByCopyIDWithLock(tx *gorm.DB, copyID uint) (*models.Borrow, error) {
+	args := m.Called(tx, copyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Borrow), args.Error(1)
+}
+
 func (m *MockBorrowRepository) Update(borrow *models.Borrow) error {
 	args := m.Called(borrow)
 	return args.Error(0)
@@ -53,51 +71,174 @@ func (m *MockBorrowRepository) CountByUserID(userID uint) (int64, error) {
 	args := m.Called(userID)
 	return args.Get(0).(int64), args.Error(1)	
 }
+func (m *MockBorrowRepository) CountActiveByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error) {
+	args := m.Called(tx, bookID)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockBorrowRepository) CountByBookIDWithTx(tx *gorm.DB, bookID uint) (int64, error) {
+	args := m.Called(tx, bookID)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockBorrowRepository) MarkOverdueWithTx(tx *gorm.DB, now time.Time) ([]models.Borrow, error) {
+	args := m.Called(tx, now)
+	return args.Get(0).([]models.Borrow), args.Error(1)
+}
+func (m *MockBorrowRepository) FindOverdue(now time.Time, limit, offset int) ([]models.Borrow, error) {
+	args := m.Called(now, limit, offset)
+	return args.Get(0).([]models.Borrow), args.Error(1)
+}
+func (m *MockBorrowRepository) CountOverdue(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
 
 // MockTransactionManager
 type MockTransactionManager struct {
 	mock.Mock
 }
-func (m *MockTransactionManager) WithTransaction(fn func(*gorm.DB) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*gorm.DB) error) error {
 	return fn(nil)
 }
+func (m *MockTransactionManager) WithSavepoint(tx *gorm.DB, name string, fn func() error) error {
+	return fn()
+}
+
+// MockBorrowMetrics
+type MockBorrowMetrics struct {
+	mock.Mock
+}
+func (m *MockBorrowMetrics) BookBorrowed() {
+	m.Called()
+}
+func (m *MockBorrowMetrics) BookReturned() {
+	m.Called()
+}
+func (m *MockBorrowMetrics) BorrowsOverdue(count int64) {
+	m.Called(count)
+}
+func (m *MockBorrowMetrics) BorrowFailed(reason string) {
+	m.Called(reason)
+}
+
+var testBorrowConfig = BorrowConfig{
+	LoanPeriod:    14 * 24 * time.Hour,
+	RenewalPeriod: 7 * 24 * time.Hour,
+	MaxRenewals:   2,
+	PickupWindow:  3 * 24 * time.Hour,
+	Fines: FineConfig{
+		DailyRate:  1000,
+		MaxLateFee: 5000,
+	},
+}
+
+var testMember = Actor{UserID: 1, Role: models.RoleMember}
+
+var testEligibility = NewLimitPolicy(BorrowLimits{MaxActiveLoans: 3, BlockOnOverdue: true, MaxFineBalance: 10000}, nil)
 
-// TestBorrowBook - Success
+// TestBorrowBook - Success, copy available pertama dipinjamkan
 func TestBorrowBook_Success(t *testing.T) {
 	mockBorrowRepo 	:= new(MockBorrowRepository)
 	mockBookRepo 	:= new(MockBookRepository)
+	mockCopyRepo 	:= new(MockBookCopyRepository)
+	mockReservationRepo 	:= new(MockReservationRepository)
+	mockFineRepo 	:= new(MockFineRepository)
+	mockUserRepo 	:= new(MockUserRepository)
 	mockTxManager	:= new(MockTransactionManager)
-	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockTxManager)
+	mockMetrics		:= new(MockBorrowMetrics)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, mockMetrics)
 
 	book := &models.Book{
 		ID: 2,
 		Title: "Test Book",
 		Stock: 2,
 	}
+	bookCopy := &models.BookCopy{ID: 5, BookID: 2, Barcode: "BK000002-001", Status: models.CopyStatusAvailable}
 	// Buat ekspektasi
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.User{ID: 2, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(2)).Return(int64(0), nil)
 	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(book, nil)
-	mockBookRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("FindAvailableWithLock", mock.Anything, uint(2)).Return(bookCopy, nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(2)).Return(nil)
 	mockBorrowRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Borrow")).Return(nil)
+	mockMetrics.On("BookBorrowed").Return()
 
 	// Execute
-	borrow, err := service.BorrowBook(uint(2), uint(2))
+	borrow, err := service.BorrowBook(context.Background(), uint(2), uint(2))
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, borrow)
+	mockMetrics.AssertExpectations(t)
 	assert.Equal(t, uint(2), borrow.UserID)
 	assert.Equal(t, uint(2), borrow.BookID)
+	assert.Equal(t, uint(5), *borrow.CopyID)
 	assert.Equal(t, borrow.Status, models.BorrowStatusBorrowed)
+	assert.Equal(t, models.CopyStatusBorrowed, bookCopy.Status)
+	assert.WithinDuration(t, time.Now().Add(testBorrowConfig.LoanPeriod), borrow.DueDate, time.Minute)
 	mockBorrowRepo.AssertExpectations(t)
 	mockBookRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
 }
 
-// TestBorrowBook - Out of Stock
+// TestBorrowBook - peminjaman dicatat di audit log beserta perubahan stock buku
+func TestBorrowBook_RecordsAudit(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockAuditRepo := new(MockAuditLogRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, mockAuditRepo, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	bookCopy := &models.BookCopy{ID: 5, BookID: 2, Barcode: "BK000002-001", Status: models.CopyStatusAvailable}
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.User{ID: 1, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(1)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
+	// Dibaca sekali sebelum dan sekali sesudah stock dihitung ulang
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.Book{ID: 2, Stock: 1}, nil).Once()
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.Book{ID: 2, Stock: 0}, nil).Once()
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(1), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("FindAvailableWithLock", mock.Anything, uint(2)).Return(bookCopy, nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(2)).Return(nil)
+	mockBorrowRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Borrow")).Return(nil)
+	mockAuditRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.EntityType == models.AuditEntityBorrow &&
+			entry.Action == models.AuditActionBorrow &&
+			*entry.BookID == 2 &&
+			*entry.ActorID == 1 &&
+			entry.RequestID == "req-1" &&
+			entry.Changes["stock"] == models.AuditChange{Old: 1, New: 0} &&
+			entry.Changes["status"] == models.AuditChange{Old: nil, New: "borrowed"}
+	})).Return(nil)
+
+	actorID := uint(1)
+	ctx := WithAuditInfo(context.Background(), AuditInfo{ActorID: &actorID, RequestID: "req-1"})
+
+	// Execute
+	_, err := service.BorrowBook(ctx, uint(1), uint(2))
+
+	// Assert
+	assert.NoError(t, err)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+// TestBorrowBook - Out of Stock (tidak ada copy available)
 func TestBorrowBook_OutOfStock(t *testing.T) {
 	mockBorrowRepo := new(MockBorrowRepository)
 	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
 	mockTxManager := new(MockTransactionManager)
-	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockTxManager)
+	mockMetrics := new(MockBorrowMetrics)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, mockMetrics)
 
 	book := &models.Book{
 		ID: 2,
@@ -106,29 +247,77 @@ func TestBorrowBook_OutOfStock(t *testing.T) {
 	}
 
 	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.User{ID: 2, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(2)).Return(int64(0), nil)
 	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(book, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("FindAvailableWithLock", mock.Anything, uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	mockMetrics.On("BorrowFailed", "book_out_of_stock").Return()
 
 	// Execute
-	borrow, err := service.BorrowBook(uint(2), uint(2))
+	borrow, err := service.BorrowBook(context.Background(), uint(2), uint(2))
 
 	assert.Error(t, err)
 	assert.Nil(t, borrow)
 	assert.Equal(t, err.Error(), "book out of stock")
+	mockMetrics.AssertExpectations(t)
+	mockMetrics.AssertNotCalled(t, "BookBorrowed")
 	mockBookRepo.AssertExpectations(t)
+	mockBorrowRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestBorrowBook - Database error while looking up the user's hold
+func TestBorrowBook_HoldLookupError(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	mockMetrics := new(MockBorrowMetrics)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, mockMetrics)
+
+	dbErr := errors.New("connection reset")
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.User{ID: 2, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(2)).Return(int64(0), nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.Book{ID: 2, Stock: 1}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(2)).Return(nil, dbErr)
+	mockMetrics.On("BorrowFailed", "internal").Return()
+
+	// Execute
+	borrow, err := service.BorrowBook(context.Background(), uint(2), uint(2))
+
+	// Assert
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, borrow)
+	mockCopyRepo.AssertNotCalled(t, "FindAvailableWithLock", mock.Anything, mock.Anything)
+	mockBorrowRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
 }
 
 // TestBorrowBook - Book Not Found
 func TestBorrowBook_BookNotFound(t *testing.T) {
 	mockBorrowRepo := new(MockBorrowRepository)
 	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
 	mockTxManager := new(MockTransactionManager)
-	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockTxManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
 
 	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.User{ID: 1, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(1)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
 	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(999)).Return(nil, errors.New("not found"))
 
 	// Execute
-	borrow, err := service.BorrowBook(1, uint(999))
+	borrow, err := service.BorrowBook(context.Background(), 1, uint(999))
 
 	assert.Error(t, err)
 	assert.Nil(t, borrow)
@@ -136,17 +325,75 @@ func TestBorrowBook_BookNotFound(t *testing.T) {
 	mockBookRepo.AssertExpectations(t)
 }
 
-// TestReturnBook - Success
+// TestBorrowBook - Outstanding fines above threshold
+func TestBorrowBook_OutstandingFines(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.User{ID: 1, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(1)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(1)).Return(int64(10001), nil)
+
+	// Execute
+	borrow, err := service.BorrowBook(context.Background(), uint(1), uint(2))
+
+	assert.ErrorIs(t, err, ErrOutstandingFines)
+	assert.Nil(t, borrow)
+	mockBookRepo.AssertNotCalled(t, "FindByIDWithLock", mock.Anything, mock.Anything)
+	mockBorrowRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestBorrowBook - Same title already on loan
+func TestBorrowBook_AlreadyBorrowed(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	active := []models.Borrow{{ID: 4, UserID: 1, BookID: 2, DueDate: time.Now().Add(24 * time.Hour), Status: models.BorrowStatusBorrowed}}
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.User{ID: 1, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(1)).Return(active, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
+
+	// Execute
+	borrow, err := service.BorrowBook(context.Background(), uint(1), uint(2))
+
+	assert.ErrorIs(t, err, ErrAlreadyBorrowed)
+	assert.Nil(t, borrow)
+	mockBookRepo.AssertNotCalled(t, "FindByIDWithLock", mock.Anything, mock.Anything)
+}
+
+// TestReturnBook - Success, copy kembali available
 func TestReturnBook_Success(t *testing.T) {
 	mockBorrowRepo := new(MockBorrowRepository)
 	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
 	mockTxManager := new(MockTransactionManager)
-	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockTxManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
 
+	bookCopy := &models.BookCopy{ID: 5, BookID: 1, Barcode: "BK000001-001", Status: models.CopyStatusBorrowed}
 	borrow := &models.Borrow{
 		ID: 1,
 		UserID: 1,
 		BookID: 1,
+		CopyID: &bookCopy.ID,
+		DueDate: time.Now().Add(24 * time.Hour),
 		Status: models.BorrowStatusBorrowed,
 	}
 	book := &models.Book{
@@ -155,19 +402,490 @@ func TestReturnBook_Success(t *testing.T) {
 		Stock: 2,
 	}
 	// Expectations
-	mockBorrowRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(borrow, nil)
+	mockCopyRepo.On("FindByBarcode", "BK000001-001").Return(bookCopy, nil)
+	mockBorrowRepo.On("FindActiveByCopyIDWithLock", mock.Anything, uint(5)).Return(borrow, nil)
 	mockBorrowRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Borrow")).Return(nil)
 	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(book, nil)
-	mockBookRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(5)).Return(bookCopy, nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
 
 	// Execute
-	borrow, err := service.ReturnBook(uint(1))
+	borrow, err := service.ReturnBook(context.Background(), testMember, "BK000001-001")
 
 	// Asserts
 	assert.NoError(t, err)
 	assert.NotNil(t, borrow)
 	assert.Equal(t, borrow.Status, models.BorrowStatusReturned)
 	assert.NotNil(t, borrow.ReturnDate)
+	assert.Equal(t, 0, borrow.LateDays)
+	assert.Equal(t, models.CopyStatusAvailable, bookCopy.Status)
+	mockBorrowRepo.AssertExpectations(t)
+	mockBookRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
+}
+
+// TestReturnBook - Late (overdue) return
+func TestReturnBook_Late(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	bookCopy := &models.BookCopy{ID: 5, BookID: 1, Barcode: "BK000001-001", Status: models.CopyStatusBorrowed}
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 1,
+		BookID: 1,
+		CopyID: &bookCopy.ID,
+		DueDate: time.Now().Add(-50 * time.Hour), // lewat 2 hari lebih
+		Status: models.BorrowStatusOverdue,
+	}
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "BK000001-001").Return(bookCopy, nil)
+	mockBorrowRepo.On("FindActiveByCopyIDWithLock", mock.Anything, uint(5)).Return(borrow, nil)
+	mockBorrowRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Borrow")).Return(nil)
+	mockFineRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(f *models.Fine) bool {
+		// 3 hari x 1000
+		return f.Type == models.FineTypeLate && f.Amount == 3000 && f.UserID == 1 && *f.BorrowID == 1
+	})).Return(nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(5)).Return(bookCopy, nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	returned, err := service.ReturnBook(context.Background(), testMember, "BK000001-001")
+
+	// Asserts
+	assert.NoError(t, err)
+	assert.Equal(t, models.BorrowStatusReturned, returned.Status)
+	assert.Equal(t, 3, returned.LateDays)
+	assert.Equal(t, models.CopyStatusAvailable, bookCopy.Status)
+	mockFineRepo.AssertExpectations(t)
+	mockBorrowRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
+}
+
+// TestReturnBook - Barcode tidak terdaftar atau copy tidak sedang dipinjam
+func TestReturnBook_UnknownOrShelvedCopy(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	service := NewBorrowService(mockBorrowRepo, new(MockBookRepository), mockCopyRepo, new(MockReservationRepository), new(MockFineRepository), new(MockUserRepository), nil, new(MockTransactionManager), testEligibility, testBorrowConfig, nil)
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "missing").Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("FindByBarcode", "BK000001-002").Return(&models.BookCopy{ID: 6, BookID: 1, Status: models.CopyStatusAvailable}, nil)
+	mockBorrowRepo.On("FindActiveByCopyIDWithLock", mock.Anything, uint(6)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute & Asserts
+	returned, err := service.ReturnBook(context.Background(), testMember, "missing")
+	assert.ErrorIs(t, err, ErrCopyNotFound)
+	assert.Nil(t, returned)
+
+	returned, err = service.ReturnBook(context.Background(), testMember, "BK000001-002")
+	assert.ErrorIs(t, err, ErrCopyNotOnLoan)
+	assert.Nil(t, returned)
+}
+
+// TestMarkOverdueBorrows
+func TestMarkOverdueBorrows(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	mockAuditRepo := new(MockAuditLogRepository)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, mockAuditRepo, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	marked := []models.Borrow{
+		{ID: 1, BookID: 4, Status: models.BorrowStatusOverdue},
+		{ID: 2, BookID: 4, Status: models.BorrowStatusOverdue},
+		{ID: 3, BookID: 7, Status: models.BorrowStatusOverdue},
+	}
+
+	// Expectations - setiap peminjaman dicatat tanpa actor
+	mockBorrowRepo.On("MarkOverdueWithTx", mock.Anything, mock.AnythingOfType("time.Time")).Return(marked, nil)
+	mockAuditRepo.On("CreateWithTx", mock.Anything, mock.MatchedBy(func(entry *models.AuditLog) bool {
+		return entry.EntityType == models.AuditEntityBorrow &&
+			entry.Action == models.AuditActionOverdue &&
+			entry.ActorID == nil &&
+			entry.Changes["status"] == models.AuditChange{Old: "borrowed", New: "overdue"}
+	})).Return(nil).Times(3)
+
+	// Execute
+	count, err := service.MarkOverdueBorrows(context.Background())
+
+	// Asserts
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	mockBorrowRepo.AssertExpectations(t)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+// TestGetOverdueBorrows - pagination
+func TestGetOverdueBorrows(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	overdue := []models.Borrow{{ID: 3, Status: models.BorrowStatusOverdue}}
+
+	// Expectations - page 2, page size 5 => offset 5
+	mockBorrowRepo.On("FindOverdue", mock.AnythingOfType("time.Time"), 5, 5).Return(overdue, nil)
+	mockBorrowRepo.On("CountOverdue", mock.AnythingOfType("time.Time")).Return(int64(6), nil)
+
+	// Execute
+	borrows, total, err := service.GetOverdueBorrows(2, 5)
+
+	// Asserts
+	assert.NoError(t, err)
+	assert.Len(t, borrows, 1)
+	assert.Equal(t, int64(6), total)
+	mockBorrowRepo.AssertExpectations(t)
+}
+
+// TestRenewBorrow - Success
+func TestRenewBorrow_Success(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	dueDate := time.Now().Add(48 * time.Hour)
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 1,
+		BookID: 1,
+		DueDate: dueDate,
+		Status: models.BorrowStatusBorrowed,
+	}
+
+	// Expectations
+	mockBorrowRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(borrow, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockReservationRepo.On("CountActiveByBookWithTx", mock.Anything, uint(1), uint(1)).Return(int64(0), nil)
+	mockBorrowRepo.On("UpdateWithTx", mock.Anything, borrow).Return(nil)
+
+	// Execute
+	renewed, err := service.RenewBorrow(context.Background(), testMember, uint(1))
+
+	// Asserts
+	assert.NoError(t, err)
+	assert.Equal(t, 1, renewed.RenewalCount)
+	assert.Equal(t, dueDate.Add(testBorrowConfig.RenewalPeriod), renewed.DueDate)
 	mockBorrowRepo.AssertExpectations(t)
 	mockBookRepo.AssertExpectations(t)
+}
+
+// TestRenewBorrow - Limit Reached
+func TestRenewBorrow_LimitReached(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 1,
+		BookID: 1,
+		DueDate: time.Now().Add(48 * time.Hour),
+		RenewalCount: testBorrowConfig.MaxRenewals,
+		Status: models.BorrowStatusBorrowed,
+	}
+
+	// Expectations
+	mockBorrowRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(borrow, nil)
+
+	// Execute
+	renewed, err := service.RenewBorrow(context.Background(), testMember, uint(1))
+
+	// Asserts
+	assert.ErrorIs(t, err, ErrRenewalLimitReached)
+	assert.Nil(t, renewed)
+	mockBorrowRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestRenewBorrow - Overdue
+func TestRenewBorrow_Overdue(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 1,
+		BookID: 1,
+		DueDate: time.Now().Add(-time.Hour),
+		Status: models.BorrowStatusBorrowed,
+	}
+
+	// Expectations
+	mockBorrowRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(borrow, nil)
+
+	// Execute
+	renewed, err := service.RenewBorrow(context.Background(), testMember, uint(1))
+
+	// Asserts
+	assert.ErrorIs(t, err, ErrBorrowOverdue)
+	assert.Nil(t, renewed)
+}
+
+// TestRenewBorrow - Other User's Borrow
+func TestRenewBorrow_NotOwner(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 2,
+		BookID: 1,
+		DueDate: time.Now().Add(48 * time.Hour),
+		Status: models.BorrowStatusBorrowed,
+	}
+
+	// Expectations
+	mockBorrowRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(borrow, nil)
+
+	// Execute
+	renewed, err := service.RenewBorrow(context.Background(), testMember, uint(1))
+
+	// Asserts
+	assert.ErrorIs(t, err, ErrBorrowNotFound)
+	assert.Nil(t, renewed)
+}
+
+// TestBorrowBook - Fulfill Ready Hold dengan copy yang sudah disisihkan
+func TestBorrowBook_FulfillsReadyHold(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	heldCopy := &models.BookCopy{ID: 8, BookID: 2, Status: models.CopyStatusOnHold}
+	hold := &models.Reservation{ID: 7, UserID: 1, BookID: 2, CopyID: &heldCopy.ID, Status: models.ReservationStatusReady}
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.User{ID: 1, Role: models.RoleMember}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(1)).Return([]models.Borrow{}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(1)).Return(int64(0), nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.Book{ID: 2, Stock: 0}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(1), uint(2)).Return(hold, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(8)).Return(heldCopy, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, hold).Return(nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, heldCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(2)).Return(nil)
+	mockBorrowRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Borrow")).Return(nil)
+
+	// Execute
+	borrow, err := service.BorrowBook(context.Background(), uint(1), uint(2))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, borrow)
+	assert.Equal(t, uint(8), *borrow.CopyID)
+	assert.Equal(t, models.ReservationStatusFulfilled, hold.Status)
+	assert.Equal(t, models.CopyStatusBorrowed, heldCopy.Status)
+	mockCopyRepo.AssertNotCalled(t, "FindAvailableWithLock", mock.Anything, mock.Anything)
+	mockReservationRepo.AssertExpectations(t)
+	mockBorrowRepo.AssertExpectations(t)
+}
+
+// TestReturnBook - copy assigned to next hold in queue
+func TestReturnBook_AssignsNextHold(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	bookCopy := &models.BookCopy{ID: 5, BookID: 1, Barcode: "BK000001-001", Status: models.CopyStatusBorrowed}
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 1,
+		BookID: 1,
+		CopyID: &bookCopy.ID,
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: models.BorrowStatusBorrowed,
+	}
+	next := &models.Reservation{ID: 9, UserID: 3, BookID: 1, Status: models.ReservationStatusWaiting}
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "BK000001-001").Return(bookCopy, nil)
+	mockBorrowRepo.On("FindActiveByCopyIDWithLock", mock.Anything, uint(5)).Return(borrow, nil)
+	mockBorrowRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.Borrow")).Return(nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(5)).Return(bookCopy, nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(next, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, next).Return(nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	_, err := service.ReturnBook(context.Background(), testMember, "BK000001-001")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReservationStatusReady, next.Status)
+	assert.Equal(t, uint(5), *next.CopyID)
+	assert.NotNil(t, next.PickupDeadline)
+	assert.WithinDuration(t, time.Now().Add(testBorrowConfig.PickupWindow), *next.PickupDeadline, time.Minute)
+	assert.Equal(t, models.CopyStatusOnHold, bookCopy.Status)
+	mockReservationRepo.AssertExpectations(t)
+}
+
+// TestRenewBorrow - Another member has a hold
+func TestRenewBorrow_BookOnHold(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	borrow := &models.Borrow{
+		ID: 1,
+		UserID: 1,
+		BookID: 1,
+		DueDate: time.Now().Add(48 * time.Hour),
+		Status: models.BorrowStatusBorrowed,
+	}
+
+	// Expectations
+	mockBorrowRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(borrow, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockReservationRepo.On("CountActiveByBookWithTx", mock.Anything, uint(1), uint(1)).Return(int64(1), nil)
+
+	// Execute
+	renewed, err := service.RenewBorrow(context.Background(), testMember, uint(1))
+
+	// Asserts
+	assert.ErrorIs(t, err, ErrBookOnHold)
+	assert.Nil(t, renewed)
+	mockBorrowRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestReturnBook - Member cannot return someone else's borrow
+func TestReturnBook_NotOwner(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	borrow := &models.Borrow{ID: 1, UserID: 2, BookID: 1, DueDate: time.Now().Add(24 * time.Hour), Status: models.BorrowStatusBorrowed}
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "BK000001-001").Return(&models.BookCopy{ID: 5, BookID: 1, Status: models.CopyStatusBorrowed}, nil)
+	mockBorrowRepo.On("FindActiveByCopyIDWithLock", mock.Anything, uint(5)).Return(borrow, nil)
+
+	// Execute
+	returned, err := service.ReturnBook(context.Background(), testMember, "BK000001-001")
+
+	// Asserts
+	assert.ErrorIs(t, err, ErrBorrowNotFound)
+	assert.Nil(t, returned)
+	assert.Equal(t, models.BorrowStatusBorrowed, borrow.Status)
+	mockBorrowRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestReturnBook - Librarian returns a member's borrow
+func TestReturnBook_ByStaff(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockReservationRepo := new(MockReservationRepository)
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewBorrowService(mockBorrowRepo, mockBookRepo, mockCopyRepo, mockReservationRepo, mockFineRepo, mockUserRepo, nil, mockTxManager, testEligibility, testBorrowConfig, nil)
+
+	bookCopy := &models.BookCopy{ID: 5, BookID: 1, Status: models.CopyStatusBorrowed}
+	borrow := &models.Borrow{ID: 1, UserID: 2, BookID: 1, CopyID: &bookCopy.ID, DueDate: time.Now().Add(24 * time.Hour), Status: models.BorrowStatusBorrowed}
+	librarian := Actor{UserID: 9, Role: models.RoleLibrarian}
+
+	// Expectations
+	mockCopyRepo.On("FindByBarcode", "BK000001-001").Return(bookCopy, nil)
+	mockBorrowRepo.On("FindActiveByCopyIDWithLock", mock.Anything, uint(5)).Return(borrow, nil)
+	mockBorrowRepo.On("UpdateWithTx", mock.Anything, borrow).Return(nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, uint(5)).Return(bookCopy, nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, bookCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	returned, err := service.ReturnBook(context.Background(), librarian, "BK000001-001")
+
+	// Asserts
+	assert.NoError(t, err)
+	assert.Equal(t, models.BorrowStatusReturned, returned.Status)
+	mockBorrowRepo.AssertExpectations(t)
+}
+
+// TestGetBorrowByID - Owner, other member and staff
+func TestGetBorrowByID_Ownership(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := NewBorrowService(mockBorrowRepo, new(MockBookRepository), new(MockBookCopyRepository), new(MockReservationRepository), new(MockFineRepository), new(MockUserRepository), nil, new(MockTransactionManager), testEligibility, testBorrowConfig, nil)
+
+	// Expectations
+	mockBorrowRepo.On("FindByID", uint(1)).Return(&models.Borrow{ID: 1, UserID: 1}, nil)
+
+	// Execute & Asserts
+	borrow, err := service.GetBorrowByID(testMember, uint(1))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), borrow.ID)
+
+	borrow, err = service.GetBorrowByID(Actor{UserID: 2, Role: models.RoleMember}, uint(1))
+	assert.ErrorIs(t, err, ErrBorrowNotFound)
+	assert.Nil(t, borrow)
+
+	borrow, err = service.GetBorrowByID(Actor{UserID: 3, Role: models.RoleAdmin}, uint(1))
+	assert.NoError(t, err)
+	assert.NotNil(t, borrow)
 }
\ No newline at end of file
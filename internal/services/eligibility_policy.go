@@ -0,0 +1,91 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/models"
+	"time"
+)
+
+var (
+	ErrAlreadyBorrowed		= apperrors.Conflict("already_borrowed", "you already have an active loan of this book")
+	ErrLoanLimitReached		= apperrors.Forbidden("loan_limit_reached", "maximum number of active loans reached")
+	ErrHasOverdueLoans		= apperrors.Forbidden("has_overdue_loans", "return your overdue books before borrowing again")
+	ErrOutstandingFines		= apperrors.Forbidden("outstanding_fines", "outstanding fines exceed the borrowing limit")
+)
+
+// BorrowerStanding - kondisi peminjam saat ini, dibaca di dalam borrow transaction
+// setelah row user di-LOCK
+type BorrowerStanding struct {
+	UserID		uint
+	Role		models.Role
+	BookID		uint
+	ActiveLoans	[]models.Borrow
+	FineBalance	int64
+	Now			time.Time
+}
+
+// EligibilityPolicy - aturan apakah user boleh meminjam buku. Return error jika ditolak.
+type EligibilityPolicy interface {
+	Check(standing BorrowerStanding) error
+}
+
+// EligibilityFunc - adapter supaya fungsi biasa bisa dipakai sebagai EligibilityPolicy
+type EligibilityFunc func(standing BorrowerStanding) error
+
+func (f EligibilityFunc) Check(standing BorrowerStanding) error {
+	return f(standing)
+}
+
+// BorrowLimits - batas peminjaman untuk satu role
+type BorrowLimits struct {
+	MaxActiveLoans	int		// 0 = tanpa batas
+	BlockOnOverdue	bool	// tolak jika masih ada pinjaman yang lewat DueDate
+	MaxFineBalance	int64	// tolak jika saldo denda melebihi nilai ini
+}
+
+type limitPolicy struct {
+	defaults	BorrowLimits
+	roles		map[models.Role]BorrowLimits
+}
+
+// NewLimitPolicy - policy bawaan: tidak boleh meminjam judul yang sama dua kali,
+// tidak ada pinjaman overdue, saldo denda di bawah batas dan jumlah pinjaman aktif
+// di bawah MaxActiveLoans. Role yang ada di overrides memakai limit miliknya sendiri.
+func NewLimitPolicy(defaults BorrowLimits, overrides map[models.Role]BorrowLimits) EligibilityPolicy {
+	return &limitPolicy{defaults: defaults, roles: overrides}
+}
+
+func (p *limitPolicy) Check(standing BorrowerStanding) error {
+	limits := p.limitsFor(standing.Role)
+
+	for _, loan := range standing.ActiveLoans {
+		if loan.BookID == standing.BookID {
+			return ErrAlreadyBorrowed
+		}
+	}
+
+	if limits.BlockOnOverdue {
+		for _, loan := range standing.ActiveLoans {
+			if loan.Status == models.BorrowStatusOverdue || standing.Now.After(loan.DueDate) {
+				return ErrHasOverdueLoans
+			}
+		}
+	}
+
+	if standing.FineBalance > limits.MaxFineBalance {
+		return ErrOutstandingFines
+	}
+
+	if limits.MaxActiveLoans > 0 && len(standing.ActiveLoans) >= limits.MaxActiveLoans {
+		return ErrLoanLimitReached
+	}
+
+	return nil
+}
+
+func (p *limitPolicy) limitsFor(role models.Role) BorrowLimits {
+	if limits, ok := p.roles[role]; ok {
+		return limits
+	}
+	return p.defaults
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func activeLoans(n int, dueDate time.Time) []models.Borrow {
+	loans := make([]models.Borrow, n)
+	for i := range loans {
+		loans[i] = models.Borrow{ID: uint(i + 1), BookID: uint(100 + i), DueDate: dueDate, Status: models.BorrowStatusBorrowed}
+	}
+	return loans
+}
+
+// TestLimitPolicy - Eligible
+func TestLimitPolicy_Eligible(t *testing.T) {
+	policy := NewLimitPolicy(BorrowLimits{MaxActiveLoans: 3, BlockOnOverdue: true, MaxFineBalance: 10000}, nil)
+	now := time.Now()
+
+	err := policy.Check(BorrowerStanding{
+		Role:        models.RoleMember,
+		BookID:      1,
+		ActiveLoans: activeLoans(2, now.Add(24*time.Hour)),
+		FineBalance: 10000,
+		Now:         now,
+	})
+
+	assert.NoError(t, err)
+}
+
+// TestLimitPolicy - Max active loans reached
+func TestLimitPolicy_LoanLimitReached(t *testing.T) {
+	policy := NewLimitPolicy(BorrowLimits{MaxActiveLoans: 2}, nil)
+	now := time.Now()
+
+	err := policy.Check(BorrowerStanding{
+		Role:        models.RoleMember,
+		BookID:      1,
+		ActiveLoans: activeLoans(2, now.Add(24*time.Hour)),
+		Now:         now,
+	})
+
+	assert.ErrorIs(t, err, ErrLoanLimitReached)
+}
+
+// TestLimitPolicy - Role override lifts the limit
+func TestLimitPolicy_RoleOverride(t *testing.T) {
+	policy := NewLimitPolicy(BorrowLimits{MaxActiveLoans: 2}, map[models.Role]BorrowLimits{
+		models.RoleLibrarian: {MaxActiveLoans: 10},
+	})
+	now := time.Now()
+	standing := BorrowerStanding{
+		BookID:      1,
+		ActiveLoans: activeLoans(5, now.Add(24*time.Hour)),
+		Now:         now,
+	}
+
+	standing.Role = models.RoleLibrarian
+	assert.NoError(t, policy.Check(standing))
+
+	standing.Role = models.RoleMember
+	assert.ErrorIs(t, policy.Check(standing), ErrLoanLimitReached)
+}
+
+// TestLimitPolicy - Overdue loan blocks borrowing
+func TestLimitPolicy_HasOverdueLoans(t *testing.T) {
+	policy := NewLimitPolicy(BorrowLimits{BlockOnOverdue: true}, nil)
+	now := time.Now()
+
+	err := policy.Check(BorrowerStanding{
+		Role:        models.RoleMember,
+		BookID:      1,
+		ActiveLoans: activeLoans(1, now.Add(-time.Hour)),
+		Now:         now,
+	})
+
+	assert.ErrorIs(t, err, ErrHasOverdueLoans)
+}
+
+// TestLimitPolicy - Fines above threshold
+func TestLimitPolicy_OutstandingFines(t *testing.T) {
+	policy := NewLimitPolicy(BorrowLimits{MaxFineBalance: 10000}, nil)
+
+	err := policy.Check(BorrowerStanding{
+		Role:        models.RoleMember,
+		BookID:      1,
+		FineBalance: 10001,
+		Now:         time.Now(),
+	})
+
+	assert.ErrorIs(t, err, ErrOutstandingFines)
+}
+
+// TestEligibilityFunc - fungsi biasa sebagai policy tambahan
+func TestEligibilityFunc_CustomPolicy(t *testing.T) {
+	errNoWeekend := errors.New("no borrowing on weekends")
+	policy := EligibilityFunc(func(standing BorrowerStanding) error {
+		if standing.Now.Weekday() == time.Saturday {
+			return errNoWeekend
+		}
+		return nil
+	})
+
+	saturday := time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC)
+	assert.ErrorIs(t, policy.Check(BorrowerStanding{Now: saturday}), errNoWeekend)
+	assert.NoError(t, policy.Check(BorrowerStanding{Now: saturday.Add(48 * time.Hour)}))
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+var ErrUnsupportedExportFormat = apperrors.Validation("unsupported_export_format", "unsupported export format, use csv or jsonl")
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV		ExportFormat = "csv"
+	ExportFormatJSONL	ExportFormat = "jsonl"
+)
+
+// exportPageSize - jumlah row yang dibaca dari database per query
+const exportPageSize = 500
+
+// bookExportColumns - kolom CSV buku. title, author, isbn, description dan stock
+// sama dengan kolom bulk import sehingga hasil export bisa diimport kembali.
+var bookExportColumns = []string{"id", "title", "author", "isbn", "description", "stock", "created_at", "updated_at"}
+
+var borrowExportColumns = []string{
+	"id", "user_id", "user_email", "book_id", "book_title", "isbn", "copy_barcode",
+	"borrow_date", "due_date", "return_date", "status", "renewal_count", "late_days",
+}
+
+// BorrowExportRow - satu peminjaman dalam export, tanpa data sensitif user
+type BorrowExportRow struct {
+	ID				uint				`json:"id"`
+	UserID			uint				`json:"user_id"`
+	UserEmail		string				`json:"user_email"`
+	BookID			uint				`json:"book_id"`
+	BookTitle		string				`json:"book_title"`
+	ISBN			string				`json:"isbn"`
+	CopyBarcode		string				`json:"copy_barcode,omitempty"`
+	BorrowDate		time.Time			`json:"borrow_date"`
+	DueDate			time.Time			`json:"due_date"`
+	ReturnDate		*time.Time			`json:"return_date,omitempty"`
+	Status			models.BorrowStatus	`json:"status"`
+	RenewalCount	int					`json:"renewal_count"`
+	LateDays		int					`json:"late_days"`
+}
+
+type ExportService interface {
+	ExportBooks(w io.Writer, format ExportFormat, filter models.BookFilter) error
+	ExportBorrows(w io.Writer, format ExportFormat, filter models.BorrowFilter) error
+}
+
+type exportService struct {
+	bookRepo	repository.BookRepository
+	borrowRepo	repository.BorrowRepository
+	pageSize	int
+}
+
+func NewExportService(bookRepo repository.BookRepository, borrowRepo repository.BorrowRepository) ExportService {
+	return &exportService{
+		bookRepo:	bookRepo,
+		borrowRepo:	borrowRepo,
+		pageSize:	exportPageSize,
+	}
+}
+
+// ExportBooks - tulis semua buku yang cocok dengan filter ke w, halaman per halaman.
+// Tidak ada yang ditulis sebelum halaman pertama berhasil dibaca.
+func (s *exportService) ExportBooks(w io.Writer, format ExportFormat, filter models.BookFilter) error {
+	out, err := newExportWriter(w, format, bookExportColumns)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += s.pageSize {
+		books, err := s.bookRepo.FindAll(filter, s.pageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, book := range books {
+			record := []string{
+				uintString(book.ID), book.Title, book.Author, book.ISBN, book.Description,
+				strconv.Itoa(book.Stock), timeString(&book.CreatedAt), timeString(&book.UpdatedAt),
+			}
+			if err := out.write(record, book); err != nil {
+				return err
+			}
+		}
+		if err := out.flush(); err != nil {
+			return err
+		}
+
+		if len(books) < s.pageSize {
+			return nil
+		}
+	}
+}
+
+// ExportBorrows - tulis semua peminjaman yang cocok dengan filter ke w, urut berdasarkan id
+func (s *exportService) ExportBorrows(w io.Writer, format ExportFormat, filter models.BorrowFilter) error {
+	out, err := newExportWriter(w, format, borrowExportColumns)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += s.pageSize {
+		borrows, err := s.borrowRepo.FindAll(filter, s.pageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, borrow := range borrows {
+			row := BorrowExportRow{
+				ID:				borrow.ID,
+				UserID:			borrow.UserID,
+				UserEmail:		borrow.User.Email,
+				BookID:			borrow.BookID,
+				BookTitle:		borrow.Book.Title,
+				ISBN:			borrow.Book.ISBN,
+				BorrowDate:		borrow.BorrowDate,
+				DueDate:		borrow.DueDate,
+				ReturnDate:		borrow.ReturnDate,
+				Status:			borrow.Status,
+				RenewalCount:	borrow.RenewalCount,
+				LateDays:		borrow.LateDays,
+			}
+			if borrow.Copy != nil {
+				row.CopyBarcode = borrow.Copy.Barcode
+			}
+
+			record := []string{
+				uintString(row.ID), uintString(row.UserID), row.UserEmail, uintString(row.BookID),
+				row.BookTitle, row.ISBN, row.CopyBarcode, timeString(&row.BorrowDate), timeString(&row.DueDate),
+				timeString(row.ReturnDate), string(row.Status), strconv.Itoa(row.RenewalCount), strconv.Itoa(row.LateDays),
+			}
+			if err := out.write(record, row); err != nil {
+				return err
+			}
+		}
+		if err := out.flush(); err != nil {
+			return err
+		}
+
+		if len(borrows) < s.pageSize {
+			return nil
+		}
+	}
+}
+
+// exportWriter - encoder CSV atau JSON Lines. Header CSV baru ditulis bersama row pertama
+// atau saat flush pertama, supaya error sebelum itu tidak menghasilkan output setengah jadi.
+type exportWriter struct {
+	w		io.Writer
+	csv		*csv.Writer
+	json	*json.Encoder
+	header	[]string
+}
+
+func newExportWriter(w io.Writer, format ExportFormat, header []string) (*exportWriter, error) {
+	out := &exportWriter{w: w}
+	switch format {
+	case ExportFormatCSV:
+		out.csv = csv.NewWriter(w)
+		out.header = header
+	case ExportFormatJSONL:
+		out.json = json.NewEncoder(w)
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+	return out, nil
+}
+
+func (e *exportWriter) write(record []string, value interface{}) error {
+	if e.json != nil {
+		return e.json.Encode(value)
+	}
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.csv.Write(record)
+}
+
+// flush - kirim halaman yang sudah ditulis ke client jika w mendukung Flush (http.Flusher)
+func (e *exportWriter) flush() error {
+	if e.csv != nil {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := e.w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (e *exportWriter) writeHeader() error {
+	if e.header == nil {
+		return nil
+	}
+	header := e.header
+	e.header = nil
+	return e.csv.Write(header)
+}
+
+func uintString(n uint) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+func timeString(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
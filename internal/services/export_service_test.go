@@ -0,0 +1,124 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportBooks - CSV dibaca per halaman sampai halaman terakhir tidak penuh
+func TestExportBooks_CSVPaged(t *testing.T) {
+	mockBookRepo := new(MockBookRepository)
+	service := &exportService{bookRepo: mockBookRepo, pageSize: 2}
+
+	created := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	filter := models.BookFilter{Author: "fowler"}
+
+	// Expectations
+	mockBookRepo.On("FindAll", filter, 2, 0).Return([]models.Book{
+		{ID: 1, Title: "Refactoring", Author: "Martin Fowler", ISBN: "9780201485677", Stock: 2, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, Title: "UML Distilled", Author: "Martin Fowler", ISBN: "9780321193681", Description: "A brief guide, 3rd ed.", CreatedAt: created, UpdatedAt: created},
+	}, nil)
+	mockBookRepo.On("FindAll", filter, 2, 2).Return([]models.Book{
+		{ID: 5, Title: "Analysis Patterns", Author: "Martin Fowler", ISBN: "9780201895421", Stock: 1, CreatedAt: created, UpdatedAt: created},
+	}, nil)
+
+	// Execute
+	var out bytes.Buffer
+	err := service.ExportBooks(&out, ExportFormatCSV, filter)
+
+	// Asserts
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"id,title,author,isbn,description,stock,created_at,updated_at",
+		"1,Refactoring,Martin Fowler,9780201485677,,2,2024-03-01T08:00:00Z,2024-03-01T08:00:00Z",
+		`2,UML Distilled,Martin Fowler,9780321193681,"A brief guide, 3rd ed.",0,2024-03-01T08:00:00Z,2024-03-01T08:00:00Z`,
+		"5,Analysis Patterns,Martin Fowler,9780201895421,,1,2024-03-01T08:00:00Z,2024-03-01T08:00:00Z",
+		"",
+	}, "\n"), out.String())
+	mockBookRepo.AssertExpectations(t)
+}
+
+// TestExportBooks - hasil kosong tetap mendapat header CSV
+func TestExportBooks_Empty(t *testing.T) {
+	mockBookRepo := new(MockBookRepository)
+	service := &exportService{bookRepo: mockBookRepo, pageSize: 2}
+
+	mockBookRepo.On("FindAll", models.BookFilter{}, 2, 0).Return([]models.Book{}, nil)
+
+	var out bytes.Buffer
+	err := service.ExportBooks(&out, ExportFormatCSV, models.BookFilter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "id,title,author,isbn,description,stock,created_at,updated_at\n", out.String())
+}
+
+// TestExportBorrows - JSON Lines tanpa data sensitif user
+func TestExportBorrows_JSONL(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := &exportService{borrowRepo: mockBorrowRepo, pageSize: 10}
+
+	copyID := uint(4)
+	filter := models.BorrowFilter{Status: models.BorrowStatusReturned}
+	returned := time.Date(2024, time.March, 10, 9, 0, 0, 0, time.UTC)
+
+	// Expectations
+	mockBorrowRepo.On("FindAll", filter, 10, 0).Return([]models.Borrow{
+		{
+			ID: 7, UserID: 1, BookID: 3, CopyID: &copyID, ReturnDate: &returned,
+			Status: models.BorrowStatusReturned, LateDays: 2,
+			User: models.User{ID: 1, Email: "member@example.com", Password: "hash"},
+			Book: models.Book{ID: 3, Title: "Refactoring", ISBN: "9780201485677"},
+			Copy: &models.BookCopy{ID: 4, Barcode: "BK000003-001"},
+		},
+	}, nil)
+
+	// Execute
+	var out bytes.Buffer
+	err := service.ExportBorrows(&out, ExportFormatJSONL, filter)
+
+	// Asserts
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "hash")
+
+	var row BorrowExportRow
+	require.NoError(t, json.Unmarshal(out.Bytes(), &row))
+	assert.Equal(t, uint(7), row.ID)
+	assert.Equal(t, "member@example.com", row.UserEmail)
+	assert.Equal(t, "BK000003-001", row.CopyBarcode)
+	assert.Equal(t, 2, row.LateDays)
+	mockBorrowRepo.AssertExpectations(t)
+}
+
+// TestExportBorrows - error database sebelum halaman pertama, tidak ada output
+func TestExportBorrows_RepositoryError(t *testing.T) {
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := &exportService{borrowRepo: mockBorrowRepo, pageSize: 10}
+
+	mockBorrowRepo.On("FindAll", mock.Anything, 10, 0).Return([]models.Borrow{}, errors.New("connection reset"))
+
+	var out bytes.Buffer
+	err := service.ExportBorrows(&out, ExportFormatCSV, models.BorrowFilter{})
+
+	assert.EqualError(t, err, "connection reset")
+	assert.Zero(t, out.Len())
+}
+
+// TestExport - format tidak dikenal
+func TestExport_UnsupportedFormat(t *testing.T) {
+	service := NewExportService(new(MockBookRepository), new(MockBorrowRepository))
+
+	var out bytes.Buffer
+	err := service.ExportBooks(&out, ExportFormat("xlsx"), models.BookFilter{})
+
+	assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+	assert.Zero(t, out.Len())
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidFineType		= apperrors.Validation("invalid_fine_type", "fine type must be lost or damaged")
+	ErrInvalidFineAmount	= apperrors.Validation("invalid_fine_amount", "amount must be greater than 0")
+	ErrAmountExceedsBalance	= apperrors.BusinessRule("amount_exceeds_balance", "amount exceeds outstanding balance")
+)
+
+// FineConfig - tarif denda keterlambatan dalam satuan mata uang terkecil
+type FineConfig struct {
+	DailyRate	int64	// denda per hari keterlambatan
+	MaxLateFee	int64	// batas denda keterlambatan per peminjaman, 0 = tanpa batas
+}
+
+// LateFee - denda untuk sejumlah hari keterlambatan, dibatasi MaxLateFee
+func (c FineConfig) LateFee(days int) int64 {
+	if days <= 0 || c.DailyRate <= 0 {
+		return 0
+	}
+	fee := int64(days) * c.DailyRate
+	if c.MaxLateFee > 0 && fee > c.MaxLateFee {
+		fee = c.MaxLateFee
+	}
+	return fee
+}
+
+type FineService interface {
+	GetUserFines(userID uint, page, pageSize int) ([]models.Fine, int64, error)
+	GetBalance(userID uint) (int64, error)
+	ChargeFine(ctx context.Context, recordedBy, userID uint, fineType models.FineType, amount int64, borrowID *uint, note string) (*models.Fine, error)
+	RecordPayment(ctx context.Context, recordedBy, userID uint, amount int64, note string) (*models.Fine, error)
+	WaiveFine(ctx context.Context, recordedBy, userID uint, amount int64, note string) (*models.Fine, error)
+}
+
+type fineService struct {
+	fineRepo	repository.FineRepository
+	userRepo	repository.UserRepository
+	borrowRepo	repository.BorrowRepository
+	txManager	database.TransactionManager
+}
+
+func NewFineService(
+	fineRepo repository.FineRepository,
+	userRepo repository.UserRepository,
+	borrowRepo repository.BorrowRepository,
+	txManager database.TransactionManager,
+) FineService {
+	return &fineService{
+		fineRepo:	fineRepo,
+		userRepo:	userRepo,
+		borrowRepo:	borrowRepo,
+		txManager:	txManager,
+	}
+}
+
+func (s *fineService) GetUserFines(userID uint, page, pageSize int) ([]models.Fine, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	fines, err := s.fineRepo.FindByUserID(userID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.fineRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return fines, total, nil
+}
+
+func (s *fineService) GetBalance(userID uint) (int64, error) {
+	return s.fineRepo.BalanceByUserID(userID)
+}
+
+// ChargeFine - denda manual untuk buku hilang atau rusak
+func (s *fineService) ChargeFine(ctx context.Context, recordedBy, userID uint, fineType models.FineType, amount int64, borrowID *uint, note string) (*models.Fine, error) {
+	// Denda keterlambatan dihitung otomatis saat ReturnBook
+	if fineType != models.FineTypeLost && fineType != models.FineTypeDamaged {
+		return nil, ErrInvalidFineType
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidFineAmount
+	}
+
+	// Denda yang terkait peminjaman harus milik user yang sama
+	if borrowID != nil {
+		borrow, err := s.borrowRepo.FindByID(*borrowID)
+		if err != nil || borrow.UserID != userID {
+			return nil, ErrBorrowNotFound
+		}
+	}
+
+	fine := &models.Fine{
+		UserID:			userID,
+		BorrowID:		borrowID,
+		Type:			fineType,
+		Amount:			amount,
+		Note:			note,
+		RecordedByID:	&recordedBy,
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.userRepo.FindByIDWithLock(tx, userID); err != nil {
+			return ErrUserNotFound
+		}
+		return s.fineRepo.CreateWithTx(tx, fine)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fine, nil
+}
+
+func (s *fineService) RecordPayment(ctx context.Context, recordedBy, userID uint, amount int64, note string) (*models.Fine, error) {
+	return s.settle(ctx, recordedBy, userID, models.FineTypePayment, amount, note)
+}
+
+func (s *fineService) WaiveFine(ctx context.Context, recordedBy, userID uint, amount int64, note string) (*models.Fine, error) {
+	return s.settle(ctx, recordedBy, userID, models.FineTypeWaiver, amount, note)
+}
+
+// settle - catat pembayaran/penghapusan denda sebagai entri negatif.
+// Row user di-LOCK supaya dua pembayaran bersamaan tidak melebihi saldo.
+func (s *fineService) settle(ctx context.Context, recordedBy, userID uint, fineType models.FineType, amount int64, note string) (*models.Fine, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidFineAmount
+	}
+
+	var result *models.Fine
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.userRepo.FindByIDWithLock(tx, userID); err != nil {
+			return ErrUserNotFound
+		}
+
+		balance, err := s.fineRepo.BalanceByUserIDWithTx(tx, userID)
+		if err != nil {
+			return err
+		}
+		if amount > balance {
+			return ErrAmountExceedsBalance
+		}
+
+		fine := &models.Fine{
+			UserID:			userID,
+			Type:			fineType,
+			Amount:			-amount,
+			Note:			note,
+			RecordedByID:	&recordedBy,
+		}
+		if err := s.fineRepo.CreateWithTx(tx, fine); err != nil {
+			return err
+		}
+
+		result = fine
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
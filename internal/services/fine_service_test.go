@@ -0,0 +1,168 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockFineRepository
+type MockFineRepository struct {
+	mock.Mock
+}
+
+func (m *MockFineRepository) CreateWithTx(tx *gorm.DB, fine *models.Fine) error {
+	args := m.Called(tx, fine)
+	return args.Error(0)
+}
+
+func (m *MockFineRepository) FindByUserID(userID uint, limit, offset int) ([]models.Fine, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]models.Fine), args.Error(1)
+}
+
+func (m *MockFineRepository) CountByUserID(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockFineRepository) BalanceByUserID(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockFineRepository) BalanceByUserIDWithTx(tx *gorm.DB, userID uint) (int64, error) {
+	args := m.Called(tx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// TestFineConfig_LateFee - daily rate dengan batas maksimum
+func TestFineConfig_LateFee(t *testing.T) {
+	config := FineConfig{DailyRate: 1000, MaxLateFee: 5000}
+
+	assert.Equal(t, int64(0), config.LateFee(0))
+	assert.Equal(t, int64(3000), config.LateFee(3))
+	assert.Equal(t, int64(5000), config.LateFee(30))
+	assert.Equal(t, int64(30000), FineConfig{DailyRate: 1000}.LateFee(30))
+}
+
+// TestChargeFine - Success
+func TestChargeFine_Success(t *testing.T) {
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := NewFineService(mockFineRepo, mockUserRepo, mockBorrowRepo, new(MockTransactionManager))
+
+	borrowID := uint(7)
+
+	// Expectations
+	mockBorrowRepo.On("FindByID", uint(7)).Return(&models.Borrow{ID: 7, UserID: 2}, nil)
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.User{ID: 2}, nil)
+	mockFineRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Fine")).Return(nil)
+
+	// Execute
+	fine, err := service.ChargeFine(context.Background(), uint(1), uint(2), models.FineTypeLost, 75000, &borrowID, "Lost in flood")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.FineTypeLost, fine.Type)
+	assert.Equal(t, int64(75000), fine.Amount)
+	assert.Equal(t, uint(1), *fine.RecordedByID)
+	mockFineRepo.AssertExpectations(t)
+}
+
+// TestChargeFine - Late fees cannot be charged manually
+func TestChargeFine_InvalidType(t *testing.T) {
+	mockFineRepo := new(MockFineRepository)
+	service := NewFineService(mockFineRepo, new(MockUserRepository), new(MockBorrowRepository), new(MockTransactionManager))
+
+	// Execute
+	fine, err := service.ChargeFine(context.Background(), uint(1), uint(2), models.FineTypeLate, 1000, nil, "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidFineType)
+	assert.Nil(t, fine)
+	mockFineRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestChargeFine - Borrow belongs to another user
+func TestChargeFine_BorrowOfAnotherUser(t *testing.T) {
+	mockFineRepo := new(MockFineRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	service := NewFineService(mockFineRepo, new(MockUserRepository), mockBorrowRepo, new(MockTransactionManager))
+
+	borrowID := uint(7)
+
+	// Expectations
+	mockBorrowRepo.On("FindByID", uint(7)).Return(&models.Borrow{ID: 7, UserID: 3}, nil)
+
+	// Execute
+	fine, err := service.ChargeFine(context.Background(), uint(1), uint(2), models.FineTypeDamaged, 1000, &borrowID, "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrBorrowNotFound)
+	assert.Nil(t, fine)
+	mockFineRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestRecordPayment - Success
+func TestRecordPayment_Success(t *testing.T) {
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := NewFineService(mockFineRepo, mockUserRepo, new(MockBorrowRepository), new(MockTransactionManager))
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.User{ID: 2}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(2)).Return(int64(5000), nil)
+	mockFineRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Fine")).Return(nil)
+
+	// Execute
+	fine, err := service.RecordPayment(context.Background(), uint(1), uint(2), 5000, "Cash")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.FineTypePayment, fine.Type)
+	assert.Equal(t, int64(-5000), fine.Amount)
+	mockFineRepo.AssertExpectations(t)
+}
+
+// TestRecordPayment - Amount above outstanding balance
+func TestRecordPayment_ExceedsBalance(t *testing.T) {
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := NewFineService(mockFineRepo, mockUserRepo, new(MockBorrowRepository), new(MockTransactionManager))
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(&models.User{ID: 2}, nil)
+	mockFineRepo.On("BalanceByUserIDWithTx", mock.Anything, uint(2)).Return(int64(2000), nil)
+
+	// Execute
+	fine, err := service.RecordPayment(context.Background(), uint(1), uint(2), 5000, "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAmountExceedsBalance)
+	assert.Nil(t, fine)
+	mockFineRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestWaiveFine - User not found
+func TestWaiveFine_UserNotFound(t *testing.T) {
+	mockFineRepo := new(MockFineRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := NewFineService(mockFineRepo, mockUserRepo, new(MockBorrowRepository), new(MockTransactionManager))
+
+	// Expectations
+	mockUserRepo.On("FindByIDWithLock", mock.Anything, uint(99)).Return(nil, errors.New("record not found"))
+
+	// Execute
+	fine, err := service.WaiveFine(context.Background(), uint(1), uint(99), 1000, "")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, fine)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGenreNotFound	= apperrors.NotFound("genre_not_found", "genre not found")
+	ErrGenreExists		= apperrors.Conflict("genre_exists", "a genre with this name already exists")
+	ErrGenreInUse		= apperrors.Conflict("genre_in_use", "genre still has books, remove it from those books first")
+)
+
+// GenreInput - data genre untuk create dan update
+type GenreInput struct {
+	Name		string	`json:"name" validate:"required,min=1,max=100"`
+	Description	string	`json:"description" validate:"max=1000"`
+}
+
+type GenreService interface {
+	CreateGenre(input GenreInput) (*models.Genre, error)
+	GetGenres(query string, page, pageSize int) ([]models.Genre, int64, error)
+	GetGenreByID(id uint) (*models.Genre, error)
+	UpdateGenre(id uint, input GenreInput) (*models.Genre, error)
+	DeleteGenre(id uint) error
+}
+
+type genreService struct {
+	genreRepo repository.GenreRepository
+}
+
+func NewGenreService(genreRepo repository.GenreRepository) GenreService {
+	return &genreService{genreRepo: genreRepo}
+}
+
+// CreateGenre - nama yang hanya beda ejaan dianggap genre yang sama
+func (s *genreService) CreateGenre(input GenreInput) (*models.Genre, error) {
+	name := strings.TrimSpace(input.Name)
+	key := models.NameKey(name)
+	if key == "" {
+		return nil, ErrInvalidName
+	}
+
+	existing, err := s.genreRepo.FindByNameKey(key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrGenreExists
+	}
+
+	genre := &models.Genre{Name: name, NameKey: key, Description: input.Description}
+	// Index unik name_key tetap menjaga create yang berbarengan
+	if err := s.genreRepo.Create(genre); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrGenreExists
+		}
+		return nil, err
+	}
+	return genre, nil
+}
+
+func (s *genreService) GetGenres(query string, page, pageSize int) ([]models.Genre, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	genres, err := s.genreRepo.FindAll(query, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.genreRepo.Count(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return genres, total, nil
+}
+
+func (s *genreService) GetGenreByID(id uint) (*models.Genre, error) {
+	genre, err := s.genreRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrGenreNotFound
+	}
+	return genre, nil
+}
+
+func (s *genreService) UpdateGenre(id uint, input GenreInput) (*models.Genre, error) {
+	genre, err := s.genreRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrGenreNotFound
+	}
+
+	name := strings.TrimSpace(input.Name)
+	key := models.NameKey(name)
+	if key == "" {
+		return nil, ErrInvalidName
+	}
+
+	existing, err := s.genreRepo.FindByNameKey(key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ID != genre.ID {
+		return nil, ErrGenreExists
+	}
+
+	genre.Name = name
+	genre.NameKey = key
+	genre.Description = input.Description
+
+	if err := s.genreRepo.Update(genre); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrGenreExists
+		}
+		return nil, err
+	}
+	return genre, nil
+}
+
+// DeleteGenre - genre yang masih punya buku tidak boleh dihapus
+func (s *genreService) DeleteGenre(id uint) error {
+	if _, err := s.genreRepo.FindByID(id); err != nil {
+		return ErrGenreNotFound
+	}
+
+	count, err := s.genreRepo.CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrGenreInUse
+	}
+
+	return s.genreRepo.Delete(id)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockGenreRepository
+type MockGenreRepository struct {
+	mock.Mock
+}
+
+func (m *MockGenreRepository) Create(genre *models.Genre) error {
+	args := m.Called(genre)
+	return args.Error(0)
+}
+
+func (m *MockGenreRepository) FindAll(query string, limit, offset int) ([]models.Genre, error) {
+	args := m.Called(query, limit, offset)
+	return args.Get(0).([]models.Genre), args.Error(1)
+}
+
+func (m *MockGenreRepository) Count(query string) (int64, error) {
+	args := m.Called(query)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockGenreRepository) FindByID(id uint) (*models.Genre, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Genre), args.Error(1)
+}
+
+func (m *MockGenreRepository) FindByNameKey(nameKey string) (*models.Genre, error) {
+	args := m.Called(nameKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Genre), args.Error(1)
+}
+
+func (m *MockGenreRepository) FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Genre, error) {
+	args := m.Called(tx, ids)
+	return args.Get(0).([]models.Genre), args.Error(1)
+}
+
+func (m *MockGenreRepository) Update(genre *models.Genre) error {
+	args := m.Called(genre)
+	return args.Error(0)
+}
+
+func (m *MockGenreRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockGenreRepository) CountBooks(id uint) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// TestGetGenres - default pagination
+func TestGetGenres_Success(t *testing.T) {
+	mockRepo := new(MockGenreRepository)
+	service := NewGenreService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindAll", "fic", 10, 0).Return([]models.Genre{{ID: 1, Name: "Fiction"}, {ID: 2, Name: "Science Fiction"}}, nil)
+	mockRepo.On("Count", "fic").Return(int64(2), nil)
+
+	// Execute
+	genres, total, err := service.GetGenres("fic", 0, 500)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, genres, 2)
+	assert.Equal(t, int64(2), total)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestDeleteGenre - genre tanpa buku dihapus
+func TestDeleteGenre_Success(t *testing.T) {
+	mockRepo := new(MockGenreRepository)
+	service := NewGenreService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(4)).Return(&models.Genre{ID: 4}, nil)
+	mockRepo.On("CountBooks", uint(4)).Return(int64(0), nil)
+	mockRepo.On("Delete", uint(4)).Return(nil)
+
+	// Execute
+	err := service.DeleteGenre(4)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestCreateGenre - error database saat cek nama diteruskan
+func TestCreateGenre_LookupError(t *testing.T) {
+	mockRepo := new(MockGenreRepository)
+	service := NewGenreService(mockRepo)
+
+	dbErr := errors.New("connection reset")
+
+	// Setup mock
+	mockRepo.On("FindByNameKey", "fantasy").Return(nil, dbErr)
+
+	// Execute
+	genre, err := service.CreateGenre(GenreInput{Name: "Fantasy"})
+
+	// Assert
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, genre)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
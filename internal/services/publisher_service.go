@@ -0,0 +1,147 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPublisherNotFound	= apperrors.NotFound("publisher_not_found", "publisher not found")
+	ErrPublisherExists		= apperrors.Conflict("publisher_exists", "a publisher with this name already exists")
+	ErrPublisherInUse		= apperrors.Conflict("publisher_in_use", "publisher still has books, remove it from those books first")
+)
+
+// PublisherInput - data publisher untuk create dan update
+type PublisherInput struct {
+	Name	string	`json:"name" validate:"required,min=1,max=200"`
+	Website	string	`json:"website" validate:"omitempty,url,max=255"`
+}
+
+type PublisherService interface {
+	CreatePublisher(input PublisherInput) (*models.Publisher, error)
+	GetPublishers(query string, page, pageSize int) ([]models.Publisher, int64, error)
+	GetPublisherByID(id uint) (*models.Publisher, error)
+	UpdatePublisher(id uint, input PublisherInput) (*models.Publisher, error)
+	DeletePublisher(id uint) error
+}
+
+type publisherService struct {
+	publisherRepo repository.PublisherRepository
+}
+
+func NewPublisherService(publisherRepo repository.PublisherRepository) PublisherService {
+	return &publisherService{publisherRepo: publisherRepo}
+}
+
+// CreatePublisher - nama yang hanya beda ejaan dianggap publisher yang sama
+func (s *publisherService) CreatePublisher(input PublisherInput) (*models.Publisher, error) {
+	name := strings.TrimSpace(input.Name)
+	key := models.NameKey(name)
+	if key == "" {
+		return nil, ErrInvalidName
+	}
+
+	existing, err := s.publisherRepo.FindByNameKey(key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrPublisherExists
+	}
+
+	publisher := &models.Publisher{Name: name, NameKey: key, Website: input.Website}
+	// Index unik name_key tetap menjaga create yang berbarengan
+	if err := s.publisherRepo.Create(publisher); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrPublisherExists
+		}
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func (s *publisherService) GetPublishers(query string, page, pageSize int) ([]models.Publisher, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	publishers, err := s.publisherRepo.FindAll(query, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.publisherRepo.Count(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return publishers, total, nil
+}
+
+func (s *publisherService) GetPublisherByID(id uint) (*models.Publisher, error) {
+	publisher, err := s.publisherRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPublisherNotFound
+	}
+	return publisher, nil
+}
+
+func (s *publisherService) UpdatePublisher(id uint, input PublisherInput) (*models.Publisher, error) {
+	publisher, err := s.publisherRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPublisherNotFound
+	}
+
+	name := strings.TrimSpace(input.Name)
+	key := models.NameKey(name)
+	if key == "" {
+		return nil, ErrInvalidName
+	}
+
+	existing, err := s.publisherRepo.FindByNameKey(key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ID != publisher.ID {
+		return nil, ErrPublisherExists
+	}
+
+	publisher.Name = name
+	publisher.NameKey = key
+	publisher.Website = input.Website
+
+	if err := s.publisherRepo.Update(publisher); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrPublisherExists
+		}
+		return nil, err
+	}
+	return publisher, nil
+}
+
+// DeletePublisher - publisher yang masih punya buku tidak boleh dihapus
+func (s *publisherService) DeletePublisher(id uint) error {
+	if _, err := s.publisherRepo.FindByID(id); err != nil {
+		return ErrPublisherNotFound
+	}
+
+	count, err := s.publisherRepo.CountBooks(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPublisherInUse
+	}
+
+	return s.publisherRepo.Delete(id)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockPublisherRepository
+type MockPublisherRepository struct {
+	mock.Mock
+}
+
+func (m *MockPublisherRepository) Create(publisher *models.Publisher) error {
+	args := m.Called(publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherRepository) FindAll(query string, limit, offset int) ([]models.Publisher, error) {
+	args := m.Called(query, limit, offset)
+	return args.Get(0).([]models.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherRepository) Count(query string) (int64, error) {
+	args := m.Called(query)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPublisherRepository) FindByID(id uint) (*models.Publisher, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherRepository) FindByNameKey(nameKey string) (*models.Publisher, error) {
+	args := m.Called(nameKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherRepository) FindByIDsWithTx(tx *gorm.DB, ids []uint) ([]models.Publisher, error) {
+	args := m.Called(tx, ids)
+	return args.Get(0).([]models.Publisher), args.Error(1)
+}
+
+func (m *MockPublisherRepository) Update(publisher *models.Publisher) error {
+	args := m.Called(publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPublisherRepository) CountBooks(id uint) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// TestCreatePublisher - Success
+func TestCreatePublisher_Success(t *testing.T) {
+	mockRepo := new(MockPublisherRepository)
+	service := NewPublisherService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByNameKey", "oreillymedia").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Publisher")).Return(nil)
+
+	// Execute
+	publisher, err := service.CreatePublisher(PublisherInput{Name: "O'Reilly Media", Website: "https://www.oreilly.com"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "O'Reilly Media", publisher.Name)
+	assert.Equal(t, "oreillymedia", publisher.NameKey)
+	assert.Equal(t, "https://www.oreilly.com", publisher.Website)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestUpdatePublisher - nama sudah dipakai publisher lain
+func TestUpdatePublisher_NameTaken(t *testing.T) {
+	mockRepo := new(MockPublisherRepository)
+	service := NewPublisherService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(1)).Return(&models.Publisher{ID: 1, Name: "Addison Wesley"}, nil)
+	mockRepo.On("FindByNameKey", "oreillymedia").Return(&models.Publisher{ID: 2, Name: "O'Reilly Media"}, nil)
+
+	// Execute
+	publisher, err := service.UpdatePublisher(1, PublisherInput{Name: "OReilly Media"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPublisherExists)
+	assert.Nil(t, publisher)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestUpdatePublisher - update berbarengan yang kalah di index unik menjadi ErrPublisherExists
+func TestUpdatePublisher_DuplicateKey(t *testing.T) {
+	mockRepo := new(MockPublisherRepository)
+	service := NewPublisherService(mockRepo)
+
+	// Setup mock
+	mockRepo.On("FindByID", uint(1)).Return(&models.Publisher{ID: 1, Name: "Addison Wesley"}, nil)
+	mockRepo.On("FindByNameKey", "oreillymedia").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Publisher")).Return(gorm.ErrDuplicatedKey)
+
+	// Execute
+	publisher, err := service.UpdatePublisher(1, PublisherInput{Name: "OReilly Media"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrPublisherExists)
+	assert.Nil(t, publisher)
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBookAvailable	= apperrors.BusinessRule("book_available", "book is available, borrow it directly")
+	ErrHoldExists		= apperrors.Conflict("hold_exists", "you already have an active hold on this book")
+	ErrHoldNotFound		= apperrors.NotFound("hold_not_found", "hold not found")
+	ErrHoldOnLoan		= apperrors.BusinessRule("book_on_loan", "you already have this book on loan")
+)
+
+type ReservationService interface {
+	PlaceHold(ctx context.Context, userID, bookID uint) (*models.Reservation, error)
+	CancelHold(ctx context.Context, userID, bookID uint) (*models.Reservation, error)
+	GetUserHolds(userID uint, page, pageSize int) ([]models.Reservation, int64, error)
+	ExpirePickups(ctx context.Context) (int64, error)
+}
+
+type reservationService struct {
+	reservationRepo	repository.ReservationRepository
+	bookRepo		repository.BookRepository
+	copyRepo		repository.BookCopyRepository
+	borrowRepo		repository.BorrowRepository
+	txManager		database.TransactionManager
+	holds			holdQueue
+	audit			auditTrail
+}
+
+func NewReservationService(
+	reservationRepo repository.ReservationRepository,
+	bookRepo repository.BookRepository,
+	copyRepo repository.BookCopyRepository,
+	borrowRepo repository.BorrowRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager database.TransactionManager,
+	pickupWindow time.Duration,
+) ReservationService {
+	return &reservationService{
+		reservationRepo:	reservationRepo,
+		bookRepo:			bookRepo,
+		copyRepo:			copyRepo,
+		borrowRepo:			borrowRepo,
+		txManager:			txManager,
+		holds:				holdQueue{reservationRepo: reservationRepo, copyRepo: copyRepo, pickupWindow: pickupWindow},
+		audit:				auditTrail{repo: auditRepo},
+	}
+}
+
+func (s *reservationService) PlaceHold(ctx context.Context, userID, bookID uint) (*models.Reservation, error) {
+	var result *models.Reservation
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// 1. Cek dan LOCK buku supaya antrian konsisten dengan borrow/return
+		book, err := s.bookRepo.FindByIDWithLock(tx, bookID)
+		if err != nil {
+			return ErrBookNotFound
+		}
+
+		// 2. User yang sedang meminjam buku ini tidak perlu antri
+		loans, err := s.borrowRepo.FindActiveByUserIDWithTx(tx, userID)
+		if err != nil {
+			return err
+		}
+		for _, loan := range loans {
+			if loan.BookID == bookID {
+				return ErrHoldOnLoan
+			}
+		}
+
+		// 3. Hold hanya untuk buku yang sedang habis
+		if book.Stock > 0 {
+			return ErrBookAvailable
+		}
+
+		// 4. Satu hold aktif per user per buku
+		existing, err := s.reservationRepo.FindActiveByUserAndBookWithTx(tx, userID, bookID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			return ErrHoldExists
+		}
+
+		reservation := &models.Reservation{
+			UserID:	userID,
+			BookID:	bookID,
+			Status:	models.ReservationStatusWaiting,
+		}
+		if err := s.reservationRepo.CreateWithTx(tx, reservation); err != nil {
+			return err
+		}
+
+		result = reservation
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *reservationService) CancelHold(ctx context.Context, userID, bookID uint) (*models.Reservation, error) {
+	var result *models.Reservation
+
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		book, err := s.bookRepo.FindByIDWithLock(tx, bookID)
+		if err != nil {
+			return ErrBookNotFound
+		}
+
+		reservation, err := s.reservationRepo.FindActiveByUserAndBookWithTx(tx, userID, bookID)
+		if err != nil {
+			return ErrHoldNotFound
+		}
+
+		wasReady := reservation.Status == models.ReservationStatusReady
+		reservation.Status = models.ReservationStatusCancelled
+		if err := s.reservationRepo.UpdateWithTx(tx, reservation); err != nil {
+			return err
+		}
+
+		// Copy yang sudah disisihkan diteruskan ke antrian berikutnya
+		if wasReady {
+			if err := s.releaseHeldCopyWithTx(ctx, tx, reservation, book.Stock); err != nil {
+				return err
+			}
+		}
+
+		result = reservation
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *reservationService) GetUserHolds(userID uint, page, pageSize int) ([]models.Reservation, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	reservations, err := s.reservationRepo.FindByUserID(userID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.reservationRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reservations, total, nil
+}
+
+// ExpirePickups - hold yang tidak diambil sampai pickup deadline dianggap expired
+// dan eksemplarnya diteruskan ke antrian berikutnya. Dipanggil berkala oleh background job.
+func (s *reservationService) ExpirePickups(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	expired, err := s.reservationRepo.FindExpiredReady(now)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, candidate := range expired {
+		err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+			// LOCK buku dulu, baru hold dan copy (urutan lock sama dengan borrow/return)
+			book, err := s.bookRepo.FindByIDWithLock(tx, candidate.BookID)
+			if err != nil {
+				return err
+			}
+
+			reservation, err := s.reservationRepo.FindByIDWithLock(tx, candidate.ID)
+			if err != nil {
+				return err
+			}
+
+			// Bisa saja sudah diambil/dibatalkan sejak dibaca
+			if reservation.Status != models.ReservationStatusReady || reservation.PickupDeadline == nil || !now.After(*reservation.PickupDeadline) {
+				return nil
+			}
+
+			reservation.Status = models.ReservationStatusExpired
+			if err := s.reservationRepo.UpdateWithTx(tx, reservation); err != nil {
+				return err
+			}
+
+			if err := s.releaseHeldCopyWithTx(ctx, tx, reservation, book.Stock); err != nil {
+				return err
+			}
+
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+// releaseHeldCopyWithTx - teruskan copy hold yang batal/expired dan catat perubahan
+// stock buku jika copy kembali ke rak karena antrian kosong
+func (s *reservationService) releaseHeldCopyWithTx(ctx context.Context, tx *gorm.DB, reservation *models.Reservation, stockBefore int) error {
+	if err := s.holds.releaseHeldCopy(tx, reservation); err != nil {
+		return err
+	}
+	stock, err := stockChangeWithTx(tx, s.bookRepo, reservation.BookID, stockBefore)
+	if err != nil {
+		return err
+	}
+	return s.audit.recordStock(ctx, tx, reservation.BookID, stock)
+}
+
+// holdQueue - logika antrian hold yang dipakai bersama oleh BorrowService, ReservationService
+// dan BookCopyService
+type holdQueue struct {
+	reservationRepo	repository.ReservationRepository
+	copyRepo		repository.BookCopyRepository
+	pickupWindow	time.Duration
+}
+
+// releaseCopy - sisihkan copy untuk hold berikutnya dengan pickup deadline, atau jadikan
+// available jika antrian kosong. Buku dan copy harus sudah di-LOCK pemanggil.
+func (q holdQueue) releaseCopy(tx *gorm.DB, bookCopy *models.BookCopy) (*models.Reservation, error) {
+	next, err := q.reservationRepo.FindNextWaitingWithTx(tx, bookCopy.BookID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	bookCopy.Status = models.CopyStatusAvailable
+	if next != nil {
+		now := time.Now()
+		deadline := now.Add(q.pickupWindow)
+		next.Status = models.ReservationStatusReady
+		next.CopyID = &bookCopy.ID
+		next.ReadyAt = &now
+		next.PickupDeadline = &deadline
+		if err := q.reservationRepo.UpdateWithTx(tx, next); err != nil {
+			return nil, err
+		}
+		bookCopy.Status = models.CopyStatusOnHold
+	}
+
+	if err := q.copyRepo.UpdateWithTx(tx, bookCopy); err != nil {
+		return nil, err
+	}
+	if err := q.copyRepo.SyncBookStockWithTx(tx, bookCopy.BookID); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// releaseHeldCopy - LOCK copy yang disisihkan untuk hold yang batal/expired lalu teruskan
+func (q holdQueue) releaseHeldCopy(tx *gorm.DB, reservation *models.Reservation) error {
+	if reservation.CopyID == nil {
+		return nil
+	}
+
+	bookCopy, err := q.copyRepo.FindByIDWithLock(tx, *reservation.CopyID)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.releaseCopy(tx, bookCopy)
+	return err
+}
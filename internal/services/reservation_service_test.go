@@ -0,0 +1,281 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockReservationRepository
+type MockReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReservationRepository) CreateWithTx(tx *gorm.DB, reservation *models.Reservation) error {
+	args := m.Called(tx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) FindByIDWithLock(tx *gorm.DB, id uint) (*models.Reservation, error) {
+	args := m.Called(tx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) FindActiveByUserAndBookWithTx(tx *gorm.DB, userID, bookID uint) (*models.Reservation, error) {
+	args := m.Called(tx, userID, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) FindNextWaitingWithTx(tx *gorm.DB, bookID uint) (*models.Reservation, error) {
+	args := m.Called(tx, bookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) FindExpiredReady(now time.Time) ([]models.Reservation, error) {
+	args := m.Called(now)
+	return args.Get(0).([]models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) FindByUserID(userID uint, limit, offset int) ([]models.Reservation, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) UpdateWithTx(tx *gorm.DB, reservation *models.Reservation) error {
+	args := m.Called(tx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) FindActiveByBookWithTx(tx *gorm.DB, bookID uint) ([]models.Reservation, error) {
+	args := m.Called(tx, bookID)
+	return args.Get(0).([]models.Reservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) CountActiveByBookWithTx(tx *gorm.DB, bookID, excludeUserID uint) (int64, error) {
+	args := m.Called(tx, bookID, excludeUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReservationRepository) CountByUserID(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// TestPlaceHold - Success
+func TestPlaceHold_Success(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, new(MockBookCopyRepository), mockBorrowRepo, nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	// Expectations
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockReservationRepo.On("CreateWithTx", mock.Anything, mock.AnythingOfType("*models.Reservation")).Return(nil)
+
+	// Execute
+	hold, err := service.PlaceHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, hold)
+	assert.Equal(t, models.ReservationStatusWaiting, hold.Status)
+	mockReservationRepo.AssertExpectations(t)
+}
+
+// TestPlaceHold - Book still in stock
+func TestPlaceHold_BookAvailable(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, new(MockBookCopyRepository), mockBorrowRepo, nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	// Expectations
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 3}, nil)
+
+	// Execute
+	hold, err := service.PlaceHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrBookAvailable)
+	assert.Nil(t, hold)
+	mockReservationRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestPlaceHold - Duplicate hold
+func TestPlaceHold_AlreadyHolding(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, new(MockBookCopyRepository), mockBorrowRepo, nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	existing := &models.Reservation{ID: 4, UserID: 2, BookID: 1, Status: models.ReservationStatusWaiting}
+
+	// Expectations
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(1)).Return(existing, nil)
+
+	// Execute
+	hold, err := service.PlaceHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrHoldExists)
+	assert.Nil(t, hold)
+	mockReservationRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestPlaceHold - Member already has the book on loan
+func TestPlaceHold_BookOnLoan(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, new(MockBookCopyRepository), mockBorrowRepo, nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	// Expectations
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{{ID: 7, UserID: 2, BookID: 1}}, nil)
+
+	// Execute
+	hold, err := service.PlaceHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrHoldOnLoan)
+	assert.Nil(t, hold)
+	mockReservationRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestPlaceHold - Database error while looking up an existing hold
+func TestPlaceHold_LookupError(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockBorrowRepo := new(MockBorrowRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, new(MockBookCopyRepository), mockBorrowRepo, nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	dbErr := errors.New("connection reset")
+
+	// Expectations
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockBorrowRepo.On("FindActiveByUserIDWithTx", mock.Anything, uint(2)).Return([]models.Borrow{}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(1)).Return(nil, dbErr)
+
+	// Execute
+	hold, err := service.PlaceHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, hold)
+	mockReservationRepo.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything)
+}
+
+// TestCancelHold - Ready hold puts the copy back on the shelf when the queue is empty
+func TestCancelHold_ReadyReleasesCopy(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, mockCopyRepo, new(MockBorrowRepository), nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	copyID := uint(9)
+	heldCopy := &models.BookCopy{ID: copyID, BookID: 1, Status: models.CopyStatusOnHold}
+	hold := &models.Reservation{ID: 4, UserID: 2, BookID: 1, CopyID: &copyID, Status: models.ReservationStatusReady}
+
+	// Expectations
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(1)).Return(hold, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, hold).Return(nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, copyID).Return(heldCopy, nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, heldCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	cancelled, err := service.CancelHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReservationStatusCancelled, cancelled.Status)
+	assert.Equal(t, models.CopyStatusAvailable, heldCopy.Status)
+	mockReservationRepo.AssertExpectations(t)
+	mockCopyRepo.AssertExpectations(t)
+}
+
+// TestCancelHold - No active hold
+func TestCancelHold_NotFound(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, new(MockBookCopyRepository), new(MockBorrowRepository), nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	// Expectations
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1}, nil)
+	mockReservationRepo.On("FindActiveByUserAndBookWithTx", mock.Anything, uint(2), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	cancelled, err := service.CancelHold(context.Background(), uint(2), uint(1))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrHoldNotFound)
+	assert.Nil(t, cancelled)
+}
+
+// TestExpirePickups - Expired hold passes the copy to the next member in line
+func TestExpirePickups_PassesToNextHold(t *testing.T) {
+	mockReservationRepo := new(MockReservationRepository)
+	mockBookRepo := new(MockBookRepository)
+	mockCopyRepo := new(MockBookCopyRepository)
+	mockTxManager := new(MockTransactionManager)
+	service := NewReservationService(mockReservationRepo, mockBookRepo, mockCopyRepo, new(MockBorrowRepository), nil, mockTxManager, testBorrowConfig.PickupWindow)
+
+	copyID := uint(9)
+	heldCopy := &models.BookCopy{ID: copyID, BookID: 1, Status: models.CopyStatusOnHold}
+	deadline := time.Now().Add(-time.Hour)
+	expired := &models.Reservation{ID: 4, UserID: 2, BookID: 1, CopyID: &copyID, Status: models.ReservationStatusReady, PickupDeadline: &deadline}
+	next := &models.Reservation{ID: 5, UserID: 3, BookID: 1, Status: models.ReservationStatusWaiting}
+
+	// Expectations
+	mockReservationRepo.On("FindExpiredReady", mock.AnythingOfType("time.Time")).Return([]models.Reservation{*expired}, nil)
+	mockBookRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(&models.Book{ID: 1, Stock: 0}, nil)
+	mockReservationRepo.On("FindByIDWithLock", mock.Anything, uint(4)).Return(expired, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, expired).Return(nil)
+	mockCopyRepo.On("FindByIDWithLock", mock.Anything, copyID).Return(heldCopy, nil)
+	mockReservationRepo.On("FindNextWaitingWithTx", mock.Anything, uint(1)).Return(next, nil)
+	mockReservationRepo.On("UpdateWithTx", mock.Anything, next).Return(nil)
+	mockCopyRepo.On("UpdateWithTx", mock.Anything, heldCopy).Return(nil)
+	mockCopyRepo.On("SyncBookStockWithTx", mock.Anything, uint(1)).Return(nil)
+
+	// Execute
+	count, err := service.ExpirePickups(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, models.ReservationStatusExpired, expired.Status)
+	assert.Equal(t, models.ReservationStatusReady, next.Status)
+	assert.Equal(t, copyID, *next.CopyID)
+	assert.NotNil(t, next.PickupDeadline)
+	assert.Equal(t, models.CopyStatusOnHold, heldCopy.Status)
+	mockReservationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"book-api/internal/apperrors"
+	"book-api/internal/database"
+	"book-api/internal/models"
+	"book-api/internal/repository"
+	"book-api/internal/utils"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound = apperrors.NotFound("user_not_found", "user not found")
+	ErrInvalidRole  = apperrors.Validation("invalid_role", "invalid role")
+	ErrLastAdmin    = apperrors.BusinessRule("last_admin", "cannot demote the last admin")
+)
+
+type UserService interface {
+	GetAllUsers(page, pageSize int) ([]models.User, int64, error)
+	UpdateUserRole(ctx context.Context, userID uint, role models.Role) (*models.User, error)
+	BootstrapAdmin(name, email, password string) (*models.User, error)
+}
+
+type userService struct {
+	userRepo  repository.UserRepository
+	txManager database.TransactionManager
+}
+
+func NewUserService(userRepo repository.UserRepository, txManager database.TransactionManager) UserService {
+	return &userService{userRepo: userRepo, txManager: txManager}
+}
+
+func (s *userService) GetAllUsers(page, pageSize int) ([]models.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	users, err := s.userRepo.FindAll(pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.userRepo.Count()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (s *userService) UpdateUserRole(ctx context.Context, userID uint, role models.Role) (*models.User, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	var user *models.User
+	err := s.txManager.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// Lock semua admin lebih dulu: dua request yang menurunkan dua admin terakhir
+		// bersamaan harus bergantian, supaya yang kedua melihat admin tinggal satu
+		admins, err := s.userRepo.FindByRoleWithLock(tx, models.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.FindByIDWithLock(tx, userID)
+		if err != nil {
+			return ErrUserNotFound
+		}
+
+		// Jangan sampai sistem kehilangan admin terakhir
+		if user.Role == models.RoleAdmin && role != models.RoleAdmin && len(admins) <= 1 {
+			return ErrLastAdmin
+		}
+
+		user.Role = role
+		return s.userRepo.UpdateWithTx(tx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// BootstrapAdmin - buat admin pertama jika belum ada admin sama sekali.
+// Jika email sudah terdaftar, user tersebut dipromosikan menjadi admin.
+// Mengembalikan nil jika tidak ada perubahan.
+func (s *userService) BootstrapAdmin(name, email, password string) (*models.User, error) {
+	if email == "" {
+		return nil, nil
+	}
+
+	admins, err := s.userRepo.CountByRole(models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if admins > 0 {
+		return nil, nil
+	}
+
+	// Promosikan user yang sudah ada
+	if existingUser, _ := s.userRepo.FindByEmail(email); existingUser != nil {
+		existingUser.Role = models.RoleAdmin
+		if err := s.userRepo.Update(existingUser); err != nil {
+			return nil, err
+		}
+		return existingUser, nil
+	}
+
+	if password == "" {
+		return nil, errors.New("admin password is required to bootstrap a new admin")
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	admin := models.User{
+		Name:     name,
+		Email:    email,
+		Password: hashedPassword,
+		Role:     models.RoleAdmin,
+	}
+
+	if err := s.userRepo.Create(&admin); err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"book-api/internal/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test UpdateUserRole - Success
+func TestUpdateUserRole_Success(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	user := &models.User{ID: 2, Email: "member@example.com", Role: models.RoleMember}
+
+	// Setup mock
+	mockRepo.On("FindByRoleWithLock", mock.Anything, models.RoleAdmin).Return([]models.User{{ID: 1, Role: models.RoleAdmin}}, nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(2)).Return(user, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, mock.AnythingOfType("*models.User")).Return(nil)
+
+	// Execute
+	updated, err := service.UpdateUserRole(context.Background(), 2, models.RoleLibrarian)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleLibrarian, updated.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test UpdateUserRole - Invalid Role
+func TestUpdateUserRole_InvalidRole(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	// Execute
+	user, err := service.UpdateUserRole(context.Background(), 2, models.Role("superuser"))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidRole)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "FindByIDWithLock", mock.Anything, mock.Anything)
+}
+
+// Test UpdateUserRole - User Not Found
+func TestUpdateUserRole_UserNotFound(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	// Setup mock
+	mockRepo.On("FindByRoleWithLock", mock.Anything, models.RoleAdmin).Return([]models.User{}, nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(999)).Return(nil, errors.New("record not found"))
+
+	// Execute
+	user, err := service.UpdateUserRole(context.Background(), 999, models.RoleAdmin)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, user)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test UpdateUserRole - Last Admin
+func TestUpdateUserRole_LastAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	admin := &models.User{ID: 1, Email: "admin@example.com", Role: models.RoleAdmin}
+
+	// Setup mock - hanya ada satu admin
+	mockRepo.On("FindByRoleWithLock", mock.Anything, models.RoleAdmin).Return([]models.User{*admin}, nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(admin, nil)
+
+	// Execute
+	user, err := service.UpdateUserRole(context.Background(), 1, models.RoleMember)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrLastAdmin)
+	assert.Nil(t, user)
+	mockRepo.AssertNotCalled(t, "UpdateWithTx", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test UpdateUserRole - Demote Admin - admin lain masih ada, admin dikunci sebelum dihitung
+func TestUpdateUserRole_DemoteAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	admin := &models.User{ID: 1, Email: "admin@example.com", Role: models.RoleAdmin}
+
+	// Setup mock - masih ada dua admin
+	mockRepo.On("FindByRoleWithLock", mock.Anything, models.RoleAdmin).Return([]models.User{*admin, {ID: 3, Role: models.RoleAdmin}}, nil)
+	mockRepo.On("FindByIDWithLock", mock.Anything, uint(1)).Return(admin, nil)
+	mockRepo.On("UpdateWithTx", mock.Anything, admin).Return(nil)
+
+	// Execute
+	updated, err := service.UpdateUserRole(context.Background(), 1, models.RoleMember)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleMember, updated.Role)
+	mockRepo.AssertNotCalled(t, "CountByRole", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test BootstrapAdmin - Create New Admin
+func TestBootstrapAdmin_CreatesAdmin(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	// Setup mock - belum ada admin dan email belum terdaftar
+	mockRepo.On("CountByRole", models.RoleAdmin).Return(int64(0), nil)
+	mockRepo.On("FindByEmail", "admin@example.com").Return(nil, errors.New("not found"))
+	mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
+
+	// Execute
+	admin, err := service.BootstrapAdmin("Admin", "admin@example.com", "password123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotNil(t, admin)
+	assert.Equal(t, models.RoleAdmin, admin.Role)
+	assert.NotEqual(t, "password123", admin.Password)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test BootstrapAdmin - Promote Existing User
+func TestBootstrapAdmin_PromotesExistingUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	existingUser := &models.User{ID: 5, Email: "admin@example.com", Role: models.RoleMember}
+
+	// Setup mock
+	mockRepo.On("CountByRole", models.RoleAdmin).Return(int64(0), nil)
+	mockRepo.On("FindByEmail", "admin@example.com").Return(existingUser, nil)
+	mockRepo.On("Update", existingUser).Return(nil)
+
+	// Execute
+	admin, err := service.BootstrapAdmin("Admin", "admin@example.com", "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), admin.ID)
+	assert.Equal(t, models.RoleAdmin, admin.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test BootstrapAdmin - Admin Already Exists
+func TestBootstrapAdmin_AdminAlreadyExists(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, new(MockTransactionManager))
+
+	// Setup mock
+	mockRepo.On("CountByRole", models.RoleAdmin).Return(int64(1), nil)
+
+	// Execute
+	admin, err := service.BootstrapAdmin("Admin", "admin@example.com", "password123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, admin)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
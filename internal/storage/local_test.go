@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalStore - put, get, overwrite dan delete
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "covers/1/a.jpg", strings.NewReader("first")))
+	require.NoError(t, store.Put(ctx, "covers/1/a.jpg", strings.NewReader("second")))
+
+	body, info, err := store.Get(ctx, "covers/1/a.jpg")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "second", string(data))
+	assert.Equal(t, int64(6), info.Size)
+
+	require.NoError(t, store.Delete(ctx, "covers/1/a.jpg"))
+	require.NoError(t, store.Delete(ctx, "covers/1/a.jpg"))
+
+	_, _, err = store.Get(ctx, "covers/1/a.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestLocalStore - key yang keluar dari root ditolak
+func TestLocalStore_InvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "covers/../../x", "covers//a", `covers\a`} {
+		assert.ErrorIs(t, store.Put(ctx, key, strings.NewReader("x")), ErrInvalidKey, key)
+	}
+}
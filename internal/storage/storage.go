@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound		= errors.New("blob not found")
+	ErrInvalidKey	= errors.New("invalid blob key")
+)
+
+// BlobInfo - metadata blob yang dibaca
+type BlobInfo struct {
+	Size		int64
+	ModTime		time.Time
+}
+
+// BlobStore - penyimpanan file seperti cover buku. Key adalah path relatif dengan
+// pemisah "/", misalnya "covers/12/3f2a9c.jpg". Implementasi lain (S3, GCS) cukup
+// memenuhi interface ini.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *BlobInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey - key tidak boleh kosong, absolut, atau keluar dari root dengan ".."
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
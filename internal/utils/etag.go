@@ -0,0 +1,25 @@
+package utils
+
+import "strings"
+
+// MatchETag - header If-Match atau If-None-Match berisi etag ini atau "*".
+// If-None-Match memakai weak comparison (prefix W/ diabaikan), If-Match memakai
+// strong comparison sehingga weak tag tidak pernah cocok.
+func MatchETag(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -10,16 +10,20 @@ import (
 type JWTClaim struct {
 	UserID uint `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken, generate JWT token untuk user yang sudah terautentikasi
-func GenerateToken(userID uint, email, secret string) (string, error) {
+// GenerateToken, generate JWT access token untuk user yang sudah terautentikasi.
+// jti dipakai untuk mengecek apakah token sudah dicabut (logout/rotasi).
+func GenerateToken(userID uint, email, role, jti, secret string, ttl time.Duration) (string, error) {
 	claims := &JWTClaim{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID: jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt: jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType - media type RFC 7807 untuk response error
+const ProblemContentType = "application/problem+json"
+
+// ErrorFormat - bentuk body response error (ERROR_FORMAT)
+type ErrorFormat string
+
+const (
+	ErrorFormatLegacy	ErrorFormat = "legacy"	// utils.Response {success, message, error, code}
+	ErrorFormatProblem	ErrorFormat = "problem"	// application/problem+json
+)
+
+// ParseErrorFormat - nilai ERROR_FORMAT yang valid
+func ParseErrorFormat(value string) (ErrorFormat, error) {
+	switch format := ErrorFormat(value); format {
+	case ErrorFormatLegacy, ErrorFormatProblem:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown ERROR_FORMAT %q, use %s or %s", value, ErrorFormatLegacy, ErrorFormatProblem)
+}
+
+// Problem - body application/problem+json (RFC 7807) dengan member tambahan
+// code dan errors
+type Problem struct {
+	Type		string			`json:"type"`
+	Title		string			`json:"title"`
+	Status		int				`json:"status"`
+	Detail		string			`json:"detail,omitempty"`
+	Instance	string			`json:"instance,omitempty"`	// request ID (X-Request-Id)
+	Code		string			`json:"code"`
+	Errors		[]FieldError	`json:"errors,omitempty"`		// hanya untuk error validasi
+}
+
+// problemWriter - ResponseWriter untuk request yang error-nya dikirim sebagai
+// problem+json. Dipasang oleh middleware ErrorFormat.
+type problemWriter struct {
+	http.ResponseWriter
+	instance	string
+}
+
+// WithProblemErrors - response error yang ditulis lewat w memakai problem+json
+// dengan instance (request ID) ini
+func WithProblemErrors(w http.ResponseWriter, instance string) http.ResponseWriter {
+	return &problemWriter{ResponseWriter: w, instance: instance}
+}
+
+// Flush - export CSV/JSON mengirim data per halaman
+func (p *problemWriter) Flush() {
+	if flusher, ok := p.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap - untuk http.ResponseController
+func (p *problemWriter) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+// NegotiateErrorFormat - Accept yang menyebut application/problem+json memilih
+// problem+json, selain itu format default dari ERROR_FORMAT
+func NegotiateErrorFormat(accept string, fallback ErrorFormat) ErrorFormat {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == ProblemContentType {
+			return ErrorFormatProblem
+		}
+	}
+	return fallback
+}
+
+// problemTarget - problemWriter di rantai wrapper w, nil jika error memakai format lama
+func problemTarget(w http.ResponseWriter) *problemWriter {
+	for {
+		switch current := w.(type) {
+		case *problemWriter:
+			return current
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = current.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// writeProblem - type berupa URN dari code supaya stabil tanpa harus ada halaman
+// dokumentasi; error umum tanpa code sendiri memakai about:blank
+func writeProblem(w http.ResponseWriter, instance string, status int, code, message string, fields []FieldError) {
+	problemType := "about:blank"
+	if code != StatusCode(status) {
+		problemType = "urn:book-api:problem:" + code
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:		problemType,
+		Title:		http.StatusText(status),
+		Status:		status,
+		Detail:		message,
+		Instance:	instance,
+		Code:		code,
+		Errors:		fields,
+	})
+}
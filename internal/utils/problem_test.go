@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorResponse_Problem - WithProblemErrors mengubah response error menjadi problem+json
+func TestErrorResponse_Problem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	fields := []FieldError{{Field: "title", Rule: "required", Message: "title is required"}}
+
+	ErrorResponseWithDetails(WithProblemErrors(rec, "host/abc-000001"), http.StatusBadRequest, "validation_failed", "Title is required", fields)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var problem Problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	assert.Equal(t, Problem{
+		Type:		"urn:book-api:problem:validation_failed",
+		Title:		"Bad Request",
+		Status:		http.StatusBadRequest,
+		Detail:		"Title is required",
+		Instance:	"host/abc-000001",
+		Code:		"validation_failed",
+		Errors:		fields,
+	}, problem)
+}
+
+// TestErrorResponse_ProblemGenericCode - error tanpa code sendiri memakai about:blank
+func TestErrorResponse_ProblemGenericCode(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	ErrorResponse(WithProblemErrors(rec, ""), http.StatusUnauthorized, "Missing authorization header")
+
+	var problem Problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "unauthorized", problem.Code)
+}
+
+// TestErrorResponse_Legacy - tanpa WithProblemErrors body tetap utils.Response
+func TestErrorResponse_Legacy(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	ErrorResponseWithDetails(rec, http.StatusBadRequest, "validation_failed", "Title is required", []FieldError{{Field: "title"}})
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"success": false, "message": "", "error": "Title is required", "code": "validation_failed"}`, rec.Body.String())
+}
+
+// TestNegotiateErrorFormat - Accept problem+json menang atas ERROR_FORMAT
+func TestNegotiateErrorFormat(t *testing.T) {
+	assert.Equal(t, ErrorFormatProblem, NegotiateErrorFormat("application/json, application/problem+json;q=0.9", ErrorFormatLegacy))
+	assert.Equal(t, ErrorFormatLegacy, NegotiateErrorFormat("application/json", ErrorFormatLegacy))
+	assert.Equal(t, ErrorFormatProblem, NegotiateErrorFormat("", ErrorFormatProblem))
+
+	_, err := ParseErrorFormat("xml")
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 )
 
 type Response struct {
@@ -10,6 +11,7 @@ type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"` // kode error yang stabil, misalnya "book_out_of_stock"
 }
 
 type PaginatedResponse struct {
@@ -36,10 +38,37 @@ func SuccessResponse(w http.ResponseWriter, status int, message string, data int
 	})
 }
 
-// ErrorResponse - respon error
+// ErrorResponse - respon error dengan code umum dari HTTP status
 func ErrorResponse(w http.ResponseWriter, status int, message string) {
+	ErrorResponseWithCode(w, status, StatusCode(status), message)
+}
+
+// ErrorResponseWithCode - respon error dengan code yang lebih spesifik
+func ErrorResponseWithCode(w http.ResponseWriter, status int, code, message string) {
+	ErrorResponseWithDetails(w, status, code, message, nil)
+}
+
+// ErrorResponseWithDetails - respon error beserta field yang gagal validasi. Format
+// lama tidak punya tempat untuk fields; detailnya hanya ada di problem+json.
+func ErrorResponseWithDetails(w http.ResponseWriter, status int, code, message string, fields []FieldError) {
+	if target := problemTarget(w); target != nil {
+		writeProblem(w, target.instance, status, code, message, fields)
+		return
+	}
+
 	WriteJSON(w, status, Response{
 		Success: false,
 		Error: 	 message,
+		Code:	 code,
 	})
+}
+
+// StatusCode - code umum untuk error yang tidak punya code sendiri,
+// misalnya 404 menjadi "not_found"
+func StatusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ToLower(strings.ReplaceAll(text, " ", "_"))
 }
\ No newline at end of file
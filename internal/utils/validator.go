@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"book-api/internal/isbn"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -9,8 +12,59 @@ import (
 
 var validate *validator.Validate
 
+// embeddedField - nama untuk struct embedded tanpa tag json (misalnya BookInput),
+// yang field-nya di JSON berada di level yang sama dengan struct luarnya
+const embeddedField = "-embedded-"
+
 func init() {
 	validate = validator.New();
+
+	// Nama field di FieldError mengikuti tag json supaya sama dengan body request.
+	// Field tanpa tag json memakai nama Go-nya, seperti encoding/json.
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" && field.Anonymous {
+			return embeddedField
+		}
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// isbn - ISBN-10 atau ISBN-13 dengan check digit yang benar, pemisah boleh ada.
+	// Menggantikan tag isbn bawaan validator.
+	validate.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		return isbn.Valid(fl.Field().String())
+	})
+}
+
+// FieldError - satu field yang gagal validasi, dengan nama field dari tag json
+type FieldError struct {
+	Field	string	`json:"field"`
+	Rule	string	`json:"rule"`
+	Param	string	`json:"param,omitempty"`
+	Message	string	`json:"message"`
+}
+
+// ValidationError - semua field yang gagal validasi. Error() tetap pesan lama yang
+// digabung dengan "; " dan memakai nama field Go, untuk client yang sudah ada.
+type ValidationError struct {
+	Fields	[]FieldError
+	legacy	[]string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.legacy, "; ")
+}
+
+// ValidationFields - detail field dari ValidationError di rantai err, nil jika tidak ada
+func ValidationFields(err error) []FieldError {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Fields
+	}
+	return nil
 }
 
 // ValidateStruct validates a struct based on the `validate` tags
@@ -20,34 +74,71 @@ func ValidateStruct(s interface{}) error {
 		return nil
 	}
 
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
 	// Format validation errors become more readable
-	var errors []string
-	if errs, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range errs {
-			errors = append(errors, formatValidationError(e))
-		}
+	validationErr := &ValidationError{}
+	for _, e := range errs {
+		field := jsonFieldPath(e)
+		validationErr.Fields = append(validationErr.Fields, FieldError{
+			Field:		field,
+			Rule:		e.Tag(),
+			Param:		e.Param(),
+			Message:	formatValidationError(e, field),
+		})
+		validationErr.legacy = append(validationErr.legacy, formatValidationError(e, e.StructField()))
 	}
 
-	return fmt.Errorf("%s", strings.Join(errors, "; "))
+	return validationErr
 }
 
-// formatValidationError formats a single validation error
-func formatValidationError(e validator.FieldError) string {
-	field := e.Field()
+// jsonFieldPath - path field tanpa nama struct paling luar dan tanpa struct embedded,
+// misalnya "CreateBookRequest.-embedded-.genre_ids[0]" menjadi "genre_ids[0]"
+func jsonFieldPath(e validator.FieldError) string {
+	var parts []string
+	for _, part := range strings.Split(e.Namespace(), ".")[1:] {
+		if part != "" && part != embeddedField {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return e.Field()
+	}
+	return strings.Join(parts, ".")
+}
 
+// formatValidationError formats a single validation error
+func formatValidationError(e validator.FieldError, field string) string {
 	switch e.Tag() {
-	case "required":
+	case "required", "required_without":
 		return fmt.Sprintf("%s is required", field)
 	case "email":
 		return fmt.Sprintf("%s must be a valid email address", field)
 	case "min":
+		if e.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s must contain at least %s items", field, e.Param())
+		}
 		return fmt.Sprintf("%s must be at least %s characters", field, e.Param())
 	case "max":
+		if e.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s must contain at most %s items", field, e.Param())
+		}
 		return fmt.Sprintf("%s must be at most %s characters", field, e.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, e.Param())
 	case "gte":
 		return fmt.Sprintf("%s must be greater than or equal to %s", field, e.Param())
 	case "lte":
 		return fmt.Sprintf("%s must be less than or equal to %s", field, e.Param())
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "isbn":
+		return fmt.Sprintf("%s must be a valid ISBN-10 or ISBN-13", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", field, e.Param())
 	default:
 		return fmt.Sprintf("%s is invalid", field)
 	}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatorInput struct {
+	Title		string	`json:"title" validate:"required"`
+	GenreIDs	[]uint	`json:"genre_ids,omitempty" validate:"omitempty,dive,gt=0"`
+}
+
+type validatorRequest struct {
+	validatorInput
+	Email	string	`json:"email" validate:"required,email"`
+}
+
+// TestValidateStruct_FieldErrors - nama field dari tag json, struct embedded tidak muncul di path
+func TestValidateStruct_FieldErrors(t *testing.T) {
+	err := ValidateStruct(validatorRequest{
+		validatorInput:	validatorInput{GenreIDs: []uint{3, 0}},
+		Email:			"not-an-email",
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, []FieldError{
+		{Field: "title", Rule: "required", Message: "title is required"},
+		{Field: "genre_ids[1]", Rule: "gt", Param: "0", Message: "genre_ids[1] must be greater than 0"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+	}, ValidationFields(fmt.Errorf("wrapped: %w", err)))
+}
+
+// TestValidateStruct_LegacyMessage - pesan lama tetap memakai nama field Go
+func TestValidateStruct_LegacyMessage(t *testing.T) {
+	err := ValidateStruct(validatorRequest{Email: "a@example.com"})
+	require.Error(t, err)
+	assert.Equal(t, "Title is required", err.Error())
+
+	assert.NoError(t, ValidateStruct(validatorRequest{validatorInput: validatorInput{Title: "Dune"}, Email: "a@example.com"}))
+}